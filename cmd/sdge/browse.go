@@ -0,0 +1,351 @@
+// cmd/sdge/browse.go
+// Exploración paginada del catálogo, con filtros y orden, en el menú
+// interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sortLabels nombra cada orden del catálogo en el menú.
+var sortLabels = map[string]string{
+	models.SortRating:   "Mejor calificados",
+	models.SortTitle:    "Título (A-Z)",
+	models.SortNewest:   "Estrenos más recientes",
+	models.SortRecent:   "Agregados recientemente",
+	models.SortDuration: "Más cortos primero",
+}
+
+func browseAudiovisual(isGuest bool) {
+	var q models.CatalogQuery
+	for {
+		page, err := contentService.QueryAudiovisual(currentUser.profile(), q, time.Now())
+		if err != nil {
+			fmt.Printf("Error al cargar contenido: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Println("\n🎬 Contenido Audiovisual Disponible:")
+		printQuery(q)
+		if page.Total == 0 {
+			fmt.Println("No hay contenido audiovisual disponible para tu clasificación de edad con estos filtros.")
+		}
+		for _, c := range page.Items {
+			fmt.Printf("ID: %d | %s (%s, %d)\n", c.ID, c.Title, c.Type, c.ReleaseYear)
+			fmt.Printf("   Género: %s | Duración: %d min | Clasificación: %s\n", c.Genre, c.Duration, c.AgeRating)
+			fmt.Printf("   Promedio: %.1f⭐ | Votos: %d\n", c.AverageRating, c.RatingCount)
+			fmt.Println("────────────────────────────────────────")
+		}
+
+		choice, ok := browseCommand(&q, page.Offset, page.Limit, page.Total, len(page.Items), isGuest, true)
+		if !ok {
+			return
+		}
+		if choice > 0 {
+			showAudiovisualDetail(choice)
+		}
+	}
+}
+
+func browseAudio(isGuest bool) {
+	var q models.CatalogQuery
+	for {
+		page, err := contentService.QueryAudio(currentUser.profile(), q, time.Now())
+		if err != nil {
+			fmt.Printf("Error al cargar contenido: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Println("\n🎵 Contenido de Audio Disponible:")
+		printQuery(q)
+		if page.Total == 0 {
+			fmt.Println("No hay contenido de audio disponible para tu clasificación de edad con estos filtros.")
+		}
+		for _, c := range page.Items {
+			fmt.Printf("ID: %d | %s - %s\n", c.ID, c.Artist, c.Title)
+			fmt.Printf("   Tipo: %s | Género: %s | Álbum: %s\n", c.Type, c.Genre, c.Album)
+			fmt.Printf("   Duración: %d min | Clasificación: %s\n", c.Duration, c.AgeRating)
+			fmt.Printf("   Promedio: %.1f⭐ | Votos: %d\n", c.AverageRating, c.RatingCount)
+			fmt.Println("────────────────────────────────────────")
+		}
+
+		choice, ok := browseCommand(&q, page.Offset, page.Limit, page.Total, len(page.Items), isGuest, false)
+		if !ok {
+			return
+		}
+		if choice > 0 {
+			showAudioDetail(choice)
+		}
+	}
+}
+
+// browseCommand muestra la posición en el listado y atiende la opción
+// elegida: cambiar de página, filtrar u ordenar actualizan q; un número es el
+// ID del contenido a abrir. Devuelve false para volver al menú anterior.
+func browseCommand(q *models.CatalogQuery, offset, limit, total, shown int, isGuest, hasYear bool) (int, bool) {
+	if total > 0 {
+		pages := (total + limit - 1) / limit
+		fmt.Printf("Página %d de %d (%d títulos)\n", offset/limit+1, pages, total)
+	}
+
+	var options []string
+	if offset+shown < total {
+		options = append(options, "S. Siguiente")
+	}
+	if offset > 0 {
+		options = append(options, "A. Anterior")
+	}
+	options = append(options, "F. Filtrar", "O. Ordenar", "L. Limpiar filtros")
+	fmt.Println(strings.Join(options, " | "))
+
+	prompt := "\nSeleccione una opción (0 para volver): "
+	if !isGuest {
+		prompt = "\nSeleccione una opción o ingrese el ID del contenido para ver detalles (0 para volver): "
+	}
+	input := strings.ToUpper(utils.ReadLine(prompt))
+
+	switch input {
+	case "0", "":
+		return 0, false
+	case "S":
+		if offset+shown < total {
+			q.Offset = offset + limit
+		}
+	case "A":
+		q.Offset = max(offset-limit, 0)
+	case "F":
+		readCatalogFilters(q, hasYear)
+		q.Offset = 0
+	case "O":
+		chooseSort(q, hasYear)
+		q.Offset = 0
+	case "L":
+		*q = models.CatalogQuery{Sort: q.Sort}
+	default:
+		if isGuest {
+			return 0, true
+		}
+		id, err := utils.ToInt(input)
+		if err != nil {
+			fmt.Println("ID inválido.")
+			utils.WaitForEnter()
+			return 0, true
+		}
+		return id, true
+	}
+	return 0, true
+}
+
+// readCatalogFilters pide los filtros de a uno; dejar un campo vacío lo
+// quita. El año de estreno solo se pide en el catálogo audiovisual.
+func readCatalogFilters(q *models.CatalogQuery, hasYear bool) {
+	fmt.Println("\nFiltros (deje vacío para no filtrar por ese campo)")
+	q.Genre = utils.ReadLine("Género: ")
+	q.Kind = utils.ReadLine("Tipo: ")
+	if hasYear {
+		q.YearFrom = readOptionalInt("Año desde: ")
+		q.YearTo = readOptionalInt("Año hasta: ")
+	}
+	q.MinDuration = readOptionalInt("Duración mínima (min): ")
+	q.MaxDuration = readOptionalInt("Duración máxima (min): ")
+	q.MinRating = 0
+	if value := utils.ReadLine("Calificación mínima (1.0 - 10.0): "); value != "" {
+		rating, err := utils.ToFloat(value)
+		if err != nil || rating < 1.0 || rating > 10.0 {
+			fmt.Println("Calificación inválida, se ignora.")
+		} else {
+			q.MinRating = rating
+		}
+	}
+	q.AgeRating = utils.ReadLine("Clasificación (p. ej. PG-13): ")
+
+	if q.YearTo > 0 && q.YearTo < q.YearFrom {
+		fmt.Println("El año hasta es anterior al año desde, se ignora.")
+		q.YearTo = 0
+	}
+	if q.MaxDuration > 0 && q.MaxDuration < q.MinDuration {
+		fmt.Println("La duración máxima es menor que la mínima, se ignora.")
+		q.MaxDuration = 0
+	}
+}
+
+// readOptionalInt lee un entero positivo; vacío o inválido es cero.
+func readOptionalInt(prompt string) int {
+	value := utils.ReadLine(prompt)
+	if value == "" {
+		return 0
+	}
+	n, err := utils.ToInt(value)
+	if err != nil || n < 0 {
+		fmt.Println("Valor inválido, se ignora.")
+		return 0
+	}
+	return n
+}
+
+// chooseSort ofrece los órdenes del catálogo; el de estreno solo si el
+// catálogo tiene año.
+func chooseSort(q *models.CatalogQuery, hasYear bool) {
+	var sorts []string
+	for _, sort := range models.CatalogSorts {
+		if sort == models.SortNewest && !hasYear {
+			continue
+		}
+		sorts = append(sorts, sort)
+	}
+
+	fmt.Println("\nOrdenar por:")
+	for i, sort := range sorts {
+		fmt.Printf("%d. %s\n", i+1, sortLabels[sort])
+	}
+	n, err := utils.ToInt(utils.ReadLine("Seleccione un orden: "))
+	if err != nil || n < 1 || n > len(sorts) {
+		return
+	}
+	q.Sort = sorts[n-1]
+}
+
+// printQuery resume el orden y los filtros activos.
+func printQuery(q models.CatalogQuery) {
+	sort := q.Sort
+	if sort == "" {
+		sort = models.SortRating
+	}
+	var filters []string
+	if q.Genre != "" {
+		filters = append(filters, "género "+q.Genre)
+	}
+	if q.Kind != "" {
+		filters = append(filters, "tipo "+q.Kind)
+	}
+	if q.YearFrom > 0 || q.YearTo > 0 {
+		filters = append(filters, fmt.Sprintf("años %s-%s", optionalInt(q.YearFrom), optionalInt(q.YearTo)))
+	}
+	if q.MinDuration > 0 || q.MaxDuration > 0 {
+		filters = append(filters, fmt.Sprintf("duración %s-%s min", optionalInt(q.MinDuration), optionalInt(q.MaxDuration)))
+	}
+	if q.MinRating > 0 {
+		filters = append(filters, fmt.Sprintf("desde %.1f⭐", q.MinRating))
+	}
+	if q.AgeRating != "" {
+		filters = append(filters, "clasificación "+q.AgeRating)
+	}
+
+	fmt.Printf("Orden: %s", sortLabels[sort])
+	if len(filters) > 0 {
+		fmt.Printf(" | Filtros: %s", strings.Join(filters, ", "))
+	}
+	fmt.Println()
+}
+
+// optionalInt muestra n, o nada si es cero.
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+func showAudiovisualDetail(contentID int) {
+	content, err := contentService.GetAudiovisualByID(currentUser.profile(), contentID, time.Now())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("═══ %s ═══\n", content.Title)
+	fmt.Printf("Tipo: %s\n", content.Type)
+	fmt.Printf("Género: %s\n", content.Genre)
+	fmt.Printf("Sinopsis: %s\n", content.Synopsis)
+	fmt.Printf("Director: %s\n", content.Director)
+	fmt.Printf("Año: %d\n", content.ReleaseYear)
+	fmt.Printf("Duración: %d minutos\n", content.Duration)
+	fmt.Printf("Clasificación: %s (%s)\n", content.AgeRating, content.RatingSystem)
+	printRatingSummary(content.Ref())
+	printTopReviews(content.Ref())
+	isSeries := content.Type == models.TypeSeries
+	fmt.Println("\n1. Reproducir")
+	fmt.Println("2. Marcar como favorito")
+	fmt.Println("3. Calificar")
+	fmt.Println("4. Reseñas")
+	if isSeries {
+		fmt.Println("5. Temporadas y Episodios")
+		fmt.Println("6. Volver")
+	} else {
+		fmt.Println("5. Volver")
+	}
+	action := utils.ReadLine("Seleccione una acción: ")
+
+	switch action {
+	case "1":
+		playAudiovisual(contentID)
+	case "2":
+		err = playbackService.AddFavorite(currentUser.profile(), content.Ref(), time.Now())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			fmt.Println("¡Agregado a Mi Lista!")
+		}
+		utils.WaitForEnter()
+	case "3":
+		rateContent(content.Ref())
+	case "4":
+		showReviews(content.Ref())
+	case "5":
+		if isSeries {
+			showSeasons(content)
+		}
+	}
+}
+
+func showAudioDetail(contentID int) {
+	content, err := contentService.GetAudioByID(currentUser.profile(), contentID, time.Now())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("═══ %s ═══\n", content.Title)
+	fmt.Printf("Artista: %s\n", content.Artist)
+	fmt.Printf("Álbum: %s\n", content.Album)
+	fmt.Printf("Género: %s\n", content.Genre)
+	fmt.Printf("Duración: %d minutos\n", content.Duration)
+	fmt.Printf("Clasificación: %s (%s)\n", content.AgeRating, content.RatingSystem)
+	printRatingSummary(content.Ref())
+	printTopReviews(content.Ref())
+	fmt.Println("\n1. Reproducir")
+	fmt.Println("2. Marcar como favorito")
+	fmt.Println("3. Calificar")
+	fmt.Println("4. Reseñas")
+	fmt.Println("5. Volver")
+	action := utils.ReadLine("Seleccione una acción: ")
+
+	switch action {
+	case "1":
+		playAudio(contentID)
+	case "2":
+		err = playbackService.AddFavorite(currentUser.profile(), content.Ref(), time.Now())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			fmt.Println("¡Agregado a Mi Lista!")
+		}
+		utils.WaitForEnter()
+	case "3":
+		rateContent(content.Ref())
+	case "4":
+		showReviews(content.Ref())
+	}
+}
@@ -0,0 +1,186 @@
+// cmd/sdge/commands.go
+// Subcomandos no interactivos de sdge (sdge <comando> [opciones]).
+package main
+
+import (
+	"SDGEStreaming/internal/api"
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/services"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// runCommand ejecuta el subcomando indicado y termina el proceso con
+// código 1 si falla.
+func runCommand(name string, args []string) {
+	var err error
+	switch name {
+	case "serve":
+		err = runServe(args)
+	case "migrate":
+		err = runMigrate(args)
+	default:
+		err = fmt.Errorf("comando desconocido %q (disponibles: serve, migrate)", name)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runServe levanta la API REST y espera hasta recibir SIGINT/SIGTERM.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "dirección en la que escucha la API")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           api.NewServer(userService, contentService, subscriptionService, playbackService, billingService, streamService, authService, profileService, parentalService, libraryService, searchService, recommendationService, trendingService, reviewService).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go expireSubscriptionsPeriodically(ctx, time.Hour)
+	go refreshPeriodically(ctx, services.TrendingRefreshInterval, "tendencias", trendingService.Refresh)
+	go refreshPeriodically(ctx, services.RecommendationRefreshInterval, "recomendaciones", recommendationService.Refresh)
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("API de SDGEStreaming escuchando en %s", *addr)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Deteniendo la API...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// expireSubscriptionsPeriodically procesa los vencimientos de suscripciones
+// cada `interval` mientras el servidor esté en marcha.
+func expireSubscriptionsPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n, err := subscriptionService.ProcessExpirations(now)
+			if err != nil {
+				log.Printf("Error procesando vencimientos de suscripciones: %v", err)
+			}
+			if n > 0 {
+				log.Printf("Suscripciones vencidas procesadas: %d", n)
+			}
+		}
+	}
+}
+
+// refreshPeriodically ejecuta refresh al iniciar y luego cada `interval`
+// mientras el servidor esté en marcha, para que ninguna consulta tenga que
+// esperar el cálculo. what describe lo que se calcula en los mensajes de error.
+func refreshPeriodically(ctx context.Context, interval time.Duration, what string, refresh func(time.Time) error) {
+	if err := refresh(time.Now()); err != nil {
+		log.Printf("Error calculando %s: %v", what, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := refresh(now); err != nil {
+				log.Printf("Error calculando %s: %v", what, err)
+			}
+		}
+	}
+}
+
+// runMigrate implementa `sdge migrate up|down [n]|status`.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: sdge migrate up|down [n]|status")
+	}
+
+	if err := db.Open(dbPath); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := db.MigrateUp()
+		for _, m := range applied {
+			fmt.Printf("✓ %04d_%s aplicada\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("El esquema ya está actualizado.")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("número de pasos inválido: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := db.MigrateDown(steps)
+		for _, m := range reverted {
+			fmt.Printf("✓ %04d_%s revertida\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No hay migraciones aplicadas.")
+		}
+	case "status":
+		list, err := db.Status()
+		if err != nil {
+			return err
+		}
+		for _, m := range list {
+			if m.Applied {
+				fmt.Printf("[x] %04d_%s (aplicada %s)\n", m.Version, m.Name, m.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("[ ] %04d_%s (pendiente)\n", m.Version, m.Name)
+			}
+		}
+	default:
+		return fmt.Errorf("subcomando desconocido %q (disponibles: up, down, status)", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+// cmd/sdge/library.go
+// Artistas, álbumes y podcasts en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"time"
+)
+
+// browseArtists lista los artistas (★ los que sigue el perfil) y abre la
+// discografía del elegido.
+func browseArtists(isGuest bool) {
+	for {
+		artists, err := libraryService.GetArtists()
+		if err != nil {
+			fmt.Printf("Error al cargar artistas: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+		followed, err := followedArtistIDs()
+		if err != nil {
+			fmt.Printf("Error al cargar artistas seguidos: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Println("\n🎤 Artistas:")
+		if len(artists) == 0 {
+			fmt.Println("No hay artistas en el catálogo.")
+			utils.WaitForEnter()
+			return
+		}
+		for _, a := range artists {
+			mark := ""
+			if followed[a.ID] {
+				mark = " ★"
+			}
+			fmt.Printf("ID: %d | %s%s\n", a.ID, a.Name, mark)
+		}
+		if isGuest {
+			utils.WaitForEnter()
+			return
+		}
+
+		artistIDStr := utils.ReadLine("\nIngrese el ID del artista para ver su discografía (0 para volver): ")
+		if artistIDStr == "0" || artistIDStr == "" {
+			return
+		}
+		artistID, err := utils.ToInt(artistIDStr)
+		if err != nil {
+			fmt.Println("ID inválido.")
+			utils.WaitForEnter()
+			continue
+		}
+		showArtist(artistID)
+	}
+}
+
+func followedArtistIDs() (map[int]bool, error) {
+	artists, err := libraryService.GetFollowedArtists(currentUser.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[int]bool, len(artists))
+	for _, a := range artists {
+		ids[a.ID] = true
+	}
+	return ids, nil
+}
+
+// showArtist muestra la discografía del artista y permite seguirlo,
+// abrir un álbum o reproducir una pista.
+func showArtist(artistID int) {
+	for {
+		artist, err := libraryService.GetArtist(currentUser.profile(), artistID, time.Now())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+		followed, err := followedArtistIDs()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Printf("═══ %s ═══\n", artist.Name)
+		if len(artist.Albums) == 0 && len(artist.Singles) == 0 {
+			fmt.Println("No hay pistas disponibles de este artista.")
+		}
+		for _, album := range artist.Albums {
+			fmt.Printf("\n💿 %s (ID: %d)\n", album.Title, album.ID)
+			printTracks(album.Tracks)
+		}
+		if len(artist.Singles) > 0 {
+			fmt.Println("\nSencillos:")
+			printTracks(artist.Singles)
+		}
+
+		fmt.Println()
+		if followed[artist.ID] {
+			fmt.Println("1. Dejar de seguir")
+		} else {
+			fmt.Println("1. Seguir artista")
+		}
+		fmt.Println("2. Ver álbum")
+		fmt.Println("3. Reproducir pista")
+		fmt.Println("4. Volver")
+		fmt.Print("\nSeleccione una opción: ")
+
+		switch utils.ReadLine("") {
+		case "1":
+			if followed[artist.ID] {
+				err = libraryService.UnfollowArtist(currentUser.ProfileID, artist.ID)
+			} else {
+				err = libraryService.FollowArtist(currentUser.ProfileID, artist.ID, time.Now())
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				utils.WaitForEnter()
+			}
+		case "2":
+			albumID, err := utils.ToInt(utils.ReadLine("ID del álbum: "))
+			if err != nil {
+				fmt.Println("ID inválido.")
+				utils.WaitForEnter()
+				continue
+			}
+			showAlbum(albumID)
+		case "3":
+			playTrack()
+		case "4":
+			return
+		default:
+			fmt.Println("Opción inválida.")
+			utils.WaitForEnter()
+		}
+	}
+}
+
+// showAlbum muestra las pistas del álbum en orden.
+func showAlbum(albumID int) {
+	album, err := libraryService.GetAlbum(currentUser.profile(), albumID, time.Now())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("═══ %s - %s ═══\n", album.Artist, album.Title)
+	if len(album.Tracks) == 0 {
+		fmt.Println("No hay pistas disponibles en este álbum.")
+		utils.WaitForEnter()
+		return
+	}
+	printTracks(album.Tracks)
+	playTrack()
+}
+
+func printTracks(tracks []models.AudioContent) {
+	for _, t := range tracks {
+		fmt.Printf("   ID: %d | %d. %s (%d min)\n", t.ID, t.TrackNumber, t.Title, t.Duration)
+	}
+}
+
+// playTrack pide el ID de una pista y la reproduce.
+func playTrack() {
+	trackIDStr := utils.ReadLine("\nIngrese el ID de la pista para reproducirla (0 para volver): ")
+	if trackIDStr == "0" || trackIDStr == "" {
+		return
+	}
+	trackID, err := utils.ToInt(trackIDStr)
+	if err != nil {
+		fmt.Println("ID inválido.")
+		utils.WaitForEnter()
+		return
+	}
+	playAudio(trackID)
+}
+
+// browseShows lista los podcasts y muestra los episodios del elegido.
+func browseShows(isGuest bool) {
+	shows, err := libraryService.GetShows()
+	if err != nil {
+		fmt.Printf("Error al cargar podcasts: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Println("\n🎙 Podcasts:")
+	if len(shows) == 0 {
+		fmt.Println("No hay podcasts en el catálogo.")
+		utils.WaitForEnter()
+		return
+	}
+	for _, sh := range shows {
+		fmt.Printf("ID: %d | %s\n", sh.ID, sh.Title)
+	}
+	if isGuest {
+		utils.WaitForEnter()
+		return
+	}
+
+	showIDStr := utils.ReadLine("\nIngrese el ID del podcast para ver sus episodios (0 para volver): ")
+	if showIDStr == "0" || showIDStr == "" {
+		return
+	}
+	showID, err := utils.ToInt(showIDStr)
+	if err != nil {
+		fmt.Println("ID inválido.")
+		utils.WaitForEnter()
+		return
+	}
+
+	show, err := libraryService.GetShow(currentUser.profile(), showID, time.Now())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	utils.ClearScreen()
+	fmt.Printf("═══ %s ═══\n", show.Title)
+	if len(show.Episodes) == 0 {
+		fmt.Println("No hay episodios disponibles de este podcast.")
+		utils.WaitForEnter()
+		return
+	}
+	fmt.Println("Episodios, del más reciente al más antiguo:")
+	printTracks(show.Episodes)
+	playTrack()
+}
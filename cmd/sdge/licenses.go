@@ -0,0 +1,130 @@
+// cmd/sdge/licenses.go
+// Licencias de contenido (ventana y países) y región de la cuenta en el menú
+// interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout es el formato de las fechas que se ingresan por consola.
+const dateLayout = "2006-01-02"
+
+func manageLicense() {
+	utils.ClearScreen()
+	fmt.Println("Licencia y Regiones")
+	fmt.Println("═══════════════════")
+
+	contentType, ok := readContentType()
+	if !ok {
+		fmt.Println("Opción inválida.")
+		utils.WaitForEnter()
+		return
+	}
+	contentID, err := utils.ToInt(utils.ReadLine("ID del contenido: "))
+	if err != nil {
+		fmt.Println("ID inválido.")
+		utils.WaitForEnter()
+		return
+	}
+
+	license, err := contentService.GetLicense(contentID, contentType)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	fmt.Println("\nLicencia actual:")
+	printLicense(license)
+
+	fmt.Println("\nIngrese la nueva licencia (reemplaza a la actual).")
+	from, err := readDate("Disponible desde (AAAA-MM-DD, Enter sin inicio): ")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	until, err := readDate("Disponible hasta, inclusive (AAAA-MM-DD, Enter sin fin): ")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	if until != nil {
+		// La licencia vence al terminar el día indicado.
+		end := until.AddDate(0, 0, 1)
+		until = &end
+	}
+
+	license.AvailableFrom = from
+	license.AvailableUntil = until
+	license.Countries = splitList(utils.ReadLine("Países separados por coma (Enter para todo el mundo): "))
+	if err := contentService.SetLicense(currentUser.actor(), license); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Println("\nLicencia actualizada:")
+		printLicense(license)
+	}
+	utils.WaitForEnter()
+}
+
+// readContentType pide elegir uno de los catálogos registrados.
+func readContentType() (string, bool) {
+	options := make([]string, len(models.Catalogs))
+	for i, c := range models.Catalogs {
+		options[i] = fmt.Sprintf("%d. %s", i+1, c.Label)
+	}
+	n, err := utils.ToInt(utils.ReadLine(fmt.Sprintf("Tipo de contenido (%s): ", strings.Join(options, ", "))))
+	if err != nil || n < 1 || n > len(models.Catalogs) {
+		return "", false
+	}
+	return models.Catalogs[n-1].ContentType, true
+}
+
+func printLicense(license *models.ContentLicense) {
+	from, until := "sin inicio", "sin fin"
+	if license.AvailableFrom != nil {
+		from = license.AvailableFrom.Local().Format("2006-01-02 15:04")
+	}
+	if license.AvailableUntil != nil {
+		until = license.AvailableUntil.Local().Format("2006-01-02 15:04")
+	}
+	countries := "todo el mundo"
+	if len(license.Countries) > 0 {
+		countries = strings.Join(license.Countries, ", ")
+	}
+	fmt.Printf("Ventana: %s → %s\n", from, until)
+	fmt.Printf("Países: %s\n", countries)
+}
+
+// readDate lee una fecha local; vacío devuelve nil.
+func readDate(prompt string) (*time.Time, error) {
+	value := utils.ReadLine(prompt)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("fecha '%s' no válida (use AAAA-MM-DD)", value)
+	}
+	return &t, nil
+}
+
+// changeUserCountry cambia la región de otra cuenta (users.manage).
+func changeUserCountry(user models.User) {
+	country := utils.ReadLine(fmt.Sprintf("Nuevo país de %s (código ISO de 2 letras, actual %s; Enter para cancelar): ", user.Name, user.Country))
+	if country == "" {
+		return
+	}
+	updated, err := userService.SetCountry(currentUser.actor(), user.ID, country)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Región de %s actualizada a %s.\n", updated.Name, updated.Country)
+	}
+	utils.WaitForEnter()
+}
@@ -8,12 +8,15 @@ package main
 import (
 	"SDGEStreaming/internal/db"
 	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/payments"
 	"SDGEStreaming/internal/repositories"
 	"SDGEStreaming/internal/security"
 	"SDGEStreaming/internal/services"
 	"SDGEStreaming/internal/utils"
+	"bytes"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -29,20 +32,58 @@ type CurrentUser struct {
 	PlanName  string
 	Age       int
 	AgeRating string
-	IsAdmin   bool
+	Country   string
+	Role      string
+
+	// Perfil del hogar con el que se está usando la aplicación. Favoritos,
+	// historial, calificaciones y el filtro por edad son del perfil.
+	ProfileID        int
+	ProfileName      string
+	ProfileAgeRating string
+}
+
+// actor devuelve el usuario actual tal como lo esperan los servicios para
+// verificar permisos.
+func (c *CurrentUser) actor() *models.User {
+	return &models.User{ID: c.ID, Name: c.Name, Email: c.Email, PlanID: c.PlanID, AgeRating: c.AgeRating, Role: c.Role}
+}
+
+// profile devuelve el perfil actual tal como lo esperan los servicios para
+// aplicar el control parental.
+func (c *CurrentUser) profile() *models.Profile {
+	return &models.Profile{ID: c.ProfileID, UserID: c.ID, Name: c.ProfileName, AgeRating: c.ProfileAgeRating}
 }
 
 var (
-	userService         *services.UserService
-	contentService      *services.ContentService
-	subscriptionService *services.SubscriptionService
-	playbackService     *services.PlaybackService
+	userService           *services.UserService
+	contentService        *services.ContentService
+	subscriptionService   *services.SubscriptionService
+	playbackService       *services.PlaybackService
+	billingService        *services.BillingService
+	streamService         *services.StreamService
+	authService           *services.AuthService
+	profileService        *services.ProfileService
+	parentalService       *services.ParentalService
+	libraryService        *services.LibraryService
+	searchService         *services.SearchService
+	recommendationService *services.RecommendationService
+	trendingService       *services.TrendingService
+	reviewService         *services.ReviewService
 
 	userRepo repositories.UserRepo
 )
 
+const dbPath = "sdgestreaming.db"
+
 func main() {
-	if err := db.InitDB("sdgestreaming.db"); err != nil {
+	// `sdge migrate` gestiona el esquema por su cuenta: no debe aplicar
+	// migraciones automáticamente al abrir la base de datos.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
+	if err := db.InitDB(dbPath); err != nil {
 		fmt.Printf("Error fatal al iniciar la base de datos: %v\n", err)
 		os.Exit(1)
 	}
@@ -54,6 +95,19 @@ func main() {
 	subscriptionRepo := repositories.NewSubscriptionRepo()
 	playbackHistoryRepo := repositories.NewPlaybackHistoryRepo()
 	favoriteRepo := repositories.NewFavoriteRepo()
+	billingRepo := repositories.NewBillingRepo()
+	streamSessionRepo := repositories.NewStreamSessionRepo()
+	authSessionRepo := repositories.NewAuthSessionRepo()
+	profileRepo := repositories.NewProfileRepo()
+	parentalRepo := repositories.NewParentalRepo()
+	ratingRepo := repositories.NewRatingRepo()
+	userRatingRepo := repositories.NewUserRatingRepo()
+	seriesRepo := repositories.NewSeriesRepo()
+	libraryRepo := repositories.NewLibraryRepo()
+	searchRepo := repositories.NewSearchRepo()
+	recommendationRepo := repositories.NewRecommendationRepo()
+	trendingRepo := repositories.NewTrendingRepo()
+	reviewRepo := repositories.NewReviewRepo()
 
 	// Crear usuario admin si no existe
 	adminUser, err := userRepo.FindByEmail("admin@sdge.com")
@@ -72,7 +126,8 @@ func main() {
 				Age:          30,
 				PlanID:       3,
 				AgeRating:    "Adulto",
-				IsAdmin:      true,
+				Country:      models.DefaultCountry,
+				Role:         models.RoleSuperAdmin,
 				PasswordHash: hashedPass,
 				CreatedAt:    now,
 				LastLogin:    now,
@@ -84,15 +139,36 @@ func main() {
 	}
 
 	userService = services.NewUserService(userRepo, subscriptionRepo)
-	contentService = services.NewContentService(contentRepo)
-	subscriptionService = services.NewSubscriptionService(subscriptionRepo, userRepo)
-	playbackService = services.NewPlaybackService(playbackHistoryRepo, favoriteRepo, contentRepo)
+	parentalService = services.NewParentalService(parentalRepo, profileRepo, userRepo, contentRepo, ratingRepo)
+	contentService = services.NewContentService(contentRepo, ratingRepo, userRatingRepo, userRepo, seriesRepo, libraryRepo, parentalService)
+	subscriptionService = services.NewSubscriptionService(subscriptionRepo, userRepo, billingRepo, payments.NewSimulator(payments.DefaultSimulatorConfig()))
+	playbackService = services.NewPlaybackService(playbackHistoryRepo, favoriteRepo, contentRepo, userRepo, subscriptionRepo, seriesRepo, parentalService)
+	billingService = services.NewBillingService(billingRepo)
+	streamService = services.NewStreamService(streamSessionRepo, userRepo, subscriptionRepo, contentRepo, parentalService)
+	authService = services.NewAuthService(authSessionRepo, userRepo, userService)
+	profileService = services.NewProfileService(profileRepo, userRepo, subscriptionRepo, authSessionRepo, contentService)
+	libraryService = services.NewLibraryService(libraryRepo, contentRepo, contentService)
+	searchService = services.NewSearchService(searchRepo, contentService)
+	recommendationService = services.NewRecommendationService(recommendationRepo, contentRepo, contentService)
+	trendingService = services.NewTrendingService(trendingRepo, contentRepo, contentService)
+	reviewService = services.NewReviewService(reviewRepo, contentRepo, userRepo, parentalService)
+
+	// Cerrar los períodos de suscripción que vencieron mientras la app no corría
+	if _, err := subscriptionService.ProcessExpirations(time.Now()); err != nil {
+		fmt.Printf("Error procesando vencimientos de suscripciones: %v\n", err)
+	}
+
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
 
 	utils.ClearScreen()
 	runApplication()
 }
 
 func runApplication() {
+	resumeSession()
 	for {
 		if currentUser == nil {
 			showAuthMenu()
@@ -135,26 +211,15 @@ func login() {
 	email := utils.ReadLine("Email: ")
 	password := utils.ReadLine("Contraseña: ")
 
-	user, err := userService.Login(email, password)
+	user, tokens, err := authService.Login(email, password, cliClient, time.Now())
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		utils.WaitForEnter()
 		return
 	}
 
-	planName := getPlanName(user.PlanID)
-
-	currentUser = &CurrentUser{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		PlanID:    user.PlanID,
-		PlanName:  planName,
-		Age:       user.Age,
-		AgeRating: user.AgeRating,
-		IsAdmin:   user.IsAdmin,
-	}
-	fmt.Printf("¡Bienvenido, %s!\n", user.Name)
+	startSession(user, tokens)
+	fmt.Printf("¡Bienvenido, %s!\n", currentUser.ProfileName)
 	utils.WaitForEnter()
 }
 
@@ -185,7 +250,7 @@ func register() {
 		return
 	}
 
-	_, err = userService.Register(name, age, email, password, false)
+	_, err = userService.Register(name, age, email, password)
 	if err != nil {
 		fmt.Printf("Error en el registro: %v\n", err)
 	} else {
@@ -195,17 +260,21 @@ func register() {
 }
 
 func showMainMenu() {
+	if !checkSession() {
+		return
+	}
+
 	utils.ClearScreen()
 	fmt.Println("Menú Principal")
 	fmt.Println("══════════════")
-	fmt.Printf("Hola, %s (%s)\n", currentUser.Name, currentUser.PlanName)
+	fmt.Printf("Hola, %s (%s)\n", currentUser.ProfileName, currentUser.PlanName)
 	fmt.Println()
 	fmt.Println("1. Inicio")
 	fmt.Println("2. Tendencias")
 	fmt.Println("3. Explorar Contenido")
 	fmt.Println("4. Mi Lista")
 	fmt.Println("5. Perfil y Cuenta")
-	if currentUser.IsAdmin {
+	if currentUser.actor().IsStaff() {
 		fmt.Println("6. Panel de Administración")
 		fmt.Println("7. Cerrar Sesión")
 	} else {
@@ -226,13 +295,13 @@ func showMainMenu() {
 	case "5":
 		showProfileMenu()
 	case "6":
-		if currentUser.IsAdmin {
+		if currentUser.actor().IsStaff() {
 			showAdminPanel()
 		} else {
 			logout()
 		}
 	case "7":
-		if currentUser.IsAdmin {
+		if currentUser.actor().IsStaff() {
 			logout()
 		}
 	default:
@@ -245,32 +314,36 @@ func showHome() {
 	utils.ClearScreen()
 	fmt.Println("Inicio")
 	fmt.Println("══════")
-	fmt.Println("¡Bienvenido a tu página de inicio!\n")
+	fmt.Println("¡Bienvenido a tu página de inicio!")
+	fmt.Println()
 
 	fmt.Println("► Continuar viendo:")
-	continueWatching, _ := playbackService.GetContinueWatching(currentUser.ID)
+	continueWatching, _ := playbackService.GetContinueWatching(currentUser.ProfileID)
 	if len(continueWatching) == 0 {
 		fmt.Println("  No tienes nada en progreso.")
 	} else {
 		for _, entry := range continueWatching {
-			var title string
-			if entry.ContentType == "audiovisual" {
-				content, _ := contentService.GetAudiovisualByID(entry.ContentID)
-				if content != nil {
-					title = content.Title
-				}
-			} else {
-				content, _ := contentService.GetAudioByID(entry.ContentID)
-				if content != nil {
-					title = content.Title
-				}
-			}
-			if title != "" {
-				fmt.Printf("  * %s (ID: %d)\n", title, entry.ContentID)
+			if entry.Item != nil {
+				fmt.Printf("  * %s%s (ID: %d) — quedó en %s de %s\n", entry.Item.Title, episodeSuffix(entry.EpisodeID), entry.ContentID, models.FormatPosition(entry.Position), models.FormatPosition(entry.Duration))
 			}
 		}
 	}
 
+	fmt.Println("\n► En tendencia esta semana:")
+	printTrending(models.TrendingWeek, 5)
+
+	fmt.Println("\n► Recomendado para ti:")
+	recommendations, err := recommendationService.GetRecommendations(currentUser.profile(), 5, time.Now())
+	if err != nil {
+		fmt.Printf("  Error al cargar recomendaciones: %v\n", err)
+	} else if len(recommendations) == 0 {
+		fmt.Println("  Aún no tenemos recomendaciones para ti.")
+	} else {
+		for _, rec := range recommendations {
+			fmt.Printf("  * %s (%s, ID: %d) — %s\n", rec.Title, rec.Kind, rec.ContentID, rec.Explanation)
+		}
+	}
+
 	fmt.Println()
 	utils.WaitForEnter()
 }
@@ -280,33 +353,39 @@ func showTrending() {
 	fmt.Println("Tendencias")
 	fmt.Println("══════════")
 
-	fmt.Println("\n🎬 Contenido Audiovisual Popular:")
-	audiovisuals, err := contentService.GetAllAudiovisual()
-	if err != nil {
-		fmt.Printf("Error al cargar contenido: %v\n", err)
-	} else {
-		for i, av := range audiovisuals {
-			if i >= 3 {
-				break
-			}
-			fmt.Printf("  %d. %s (%.1f⭐)\n", i+1, av.Title, av.AverageRating)
-		}
+	titles := map[string]string{
+		models.TrendingDay:   "🔥 Últimas 24 horas",
+		models.TrendingWeek:  "📅 Esta semana",
+		models.TrendingMonth: "🗓️  Este mes",
+	}
+	for _, window := range models.TrendingWindows {
+		fmt.Printf("\n%s:\n", titles[window.Name])
+		printTrending(window.Name, 5)
 	}
 
-	fmt.Println("\n🎵 Contenido de Audio Popular:")
-	audios, err := contentService.GetAllAudio()
+	utils.WaitForEnter()
+}
+
+// printTrending lista los primeros limit contenidos en tendencia en la
+// ventana, con las interacciones que tuvieron.
+func printTrending(window string, limit int) {
+	trending, err := trendingService.GetTrending(currentUser.profile(), window, limit, time.Now())
 	if err != nil {
-		fmt.Printf("Error al cargar contenido: %v\n", err)
-	} else {
-		for i, a := range audios {
-			if i >= 3 {
-				break
-			}
-			fmt.Printf("  %d. %s - %s (%.1f⭐)\n", i+1, a.Artist, a.Title, a.AverageRating)
+		fmt.Printf("  Error al cargar tendencias: %v\n", err)
+		return
+	}
+	if len(trending) == 0 {
+		fmt.Println("  Todavía no hay actividad en este período.")
+		return
+	}
+	for _, t := range trending {
+		title := t.Title
+		if t.Credit != "" && t.ContentType == models.ContentTypeAudio {
+			title = t.Credit + " - " + t.Title
 		}
+		fmt.Printf("  %d. %s (%s) — %d reproducciones, %d completas, %d en Mi Lista, %d calificaciones\n",
+			t.Rank, title, t.Kind, t.Plays, t.Completions, t.Favorites, t.Ratings)
 	}
-
-	utils.WaitForEnter()
 }
 
 func browseContent(isGuest bool) {
@@ -316,7 +395,10 @@ func browseContent(isGuest bool) {
 		fmt.Println("══════════════════")
 		fmt.Println("1. Contenido Audiovisual")
 		fmt.Println("2. Contenido de Audio")
-		fmt.Println("3. Volver")
+		fmt.Println("3. Artistas y Álbumes")
+		fmt.Println("4. Podcasts")
+		fmt.Println("5. Buscar")
+		fmt.Println("6. Volver")
 		fmt.Print("\nSeleccione una opción: ")
 
 		option := utils.ReadLine("")
@@ -326,6 +408,12 @@ func browseContent(isGuest bool) {
 		case "2":
 			browseAudio(isGuest)
 		case "3":
+			browseArtists(isGuest)
+		case "4":
+			browseShows(isGuest)
+		case "5":
+			searchContent(isGuest)
+		case "6":
 			return
 		default:
 			fmt.Println("Opción inválida.")
@@ -334,165 +422,12 @@ func browseContent(isGuest bool) {
 	}
 }
 
-func browseAudiovisual(isGuest bool) {
-	contents, err := contentService.GetAllAudiovisualForUser(currentUser.AgeRating)
-	if err != nil {
-		fmt.Printf("Error al cargar contenido: %v\n", err)
-		utils.WaitForEnter()
-		return
-	}
-
-	if len(contents) == 0 {
-		fmt.Println("No hay contenido audiovisual disponible para tu clasificación de edad.")
-		utils.WaitForEnter()
-		return
-	}
-
-	utils.ClearScreen()
-	fmt.Println("\n🎬 Contenido Audiovisual Disponible:")
-	for _, c := range contents {
-		fmt.Printf("ID: %d | %s (%s)\n", c.ID, c.Title, c.Type)
-		fmt.Printf("   Género: %s | Duración: %d min | Clasificación: %s\n", c.Genre, c.Duration, c.AgeRating)
-		fmt.Printf("   Promedio: %.1f⭐\n", c.AverageRating)
-		fmt.Println("────────────────────────────────────────")
-	}
-
-	if !isGuest {
-		contentIDStr := utils.ReadLine("\nIngrese el ID del contenido para ver detalles (0 para volver): ")
-		if contentIDStr == "0" {
-			return
-		}
-		contentID, err := utils.ToInt(contentIDStr)
-		if err != nil {
-			fmt.Println("ID inválido.")
-			utils.WaitForEnter()
-			return
-		}
-
-		content, err := contentService.GetAudiovisualByID(contentID)
-		if err != nil {
-			fmt.Println("Contenido no encontrado.")
-			utils.WaitForEnter()
-			return
-		}
-
-		utils.ClearScreen()
-		fmt.Printf("═══ %s ═══\n", content.Title)
-		fmt.Printf("Tipo: %s\n", content.Type)
-		fmt.Printf("Género: %s\n", content.Genre)
-		fmt.Printf("Sinopsis: %s\n", content.Synopsis)
-		fmt.Printf("Director: %s\n", content.Director)
-		fmt.Printf("Año: %d\n", content.ReleaseYear)
-		fmt.Printf("Duración: %d minutos\n", content.Duration)
-		fmt.Printf("Clasificación: %s\n", content.AgeRating)
-		fmt.Printf("Promedio de calificación: %.1f⭐\n", content.AverageRating)
-		fmt.Println("\n1. Reproducir")
-		fmt.Println("2. Marcar como favorito")
-		fmt.Println("3. Calificar")
-		fmt.Println("4. Volver")
-		action := utils.ReadLine("Seleccione una acción: ")
-
-		switch action {
-		case "1":
-			playAudiovisual(contentID)
-		case "2":
-			err = playbackService.AddFavorite(currentUser.ID, contentID, "audiovisual")
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
-			} else {
-				fmt.Println("¡Agregado a Mi Lista!")
-			}
-			utils.WaitForEnter()
-		case "3":
-			rateContent(contentID, "audiovisual")
-		case "4":
-			return
-		}
-	}
-}
-
-func browseAudio(isGuest bool) {
-	contents, err := contentService.GetAllAudioForUser(currentUser.AgeRating)
-	if err != nil {
-		fmt.Printf("Error al cargar contenido: %v\n", err)
-		utils.WaitForEnter()
-		return
-	}
-
-	if len(contents) == 0 {
-		fmt.Println("No hay contenido de audio disponible para tu clasificación de edad.")
-		utils.WaitForEnter()
-		return
-	}
-
-	utils.ClearScreen()
-	fmt.Println("\n🎵 Contenido de Audio Disponible:")
-	for _, c := range contents {
-		fmt.Printf("ID: %d | %s - %s\n", c.ID, c.Artist, c.Title)
-		fmt.Printf("   Tipo: %s | Género: %s | Álbum: %s\n", c.Type, c.Genre, c.Album)
-		fmt.Printf("   Duración: %d min | Clasificación: %s\n", c.Duration, c.AgeRating)
-		fmt.Printf("   Promedio: %.1f⭐\n", c.AverageRating)
-		fmt.Println("────────────────────────────────────────")
-	}
-
-	if !isGuest {
-		contentIDStr := utils.ReadLine("\nIngrese el ID del contenido para ver detalles (0 para volver): ")
-		if contentIDStr == "0" {
-			return
-		}
-		contentID, err := utils.ToInt(contentIDStr)
-		if err != nil {
-			fmt.Println("ID inválido.")
-			utils.WaitForEnter()
-			return
-		}
-
-		content, err := contentService.GetAudioByID(contentID)
-		if err != nil {
-			fmt.Println("Contenido no encontrado.")
-			utils.WaitForEnter()
-			return
-		}
-
-		utils.ClearScreen()
-		fmt.Printf("═══ %s ═══\n", content.Title)
-		fmt.Printf("Artista: %s\n", content.Artist)
-		fmt.Printf("Álbum: %s\n", content.Album)
-		fmt.Printf("Género: %s\n", content.Genre)
-		fmt.Printf("Duración: %d minutos\n", content.Duration)
-		fmt.Printf("Clasificación: %s\n", content.AgeRating)
-		fmt.Printf("Promedio de calificación: %.1f⭐\n", content.AverageRating)
-		fmt.Println("\n1. Reproducir")
-		fmt.Println("2. Marcar como favorito")
-		fmt.Println("3. Calificar")
-		fmt.Println("4. Volver")
-		action := utils.ReadLine("Seleccione una acción: ")
-
-		switch action {
-		case "1":
-			playAudio(contentID)
-		case "2":
-			err = playbackService.AddFavorite(currentUser.ID, contentID, "audio")
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
-			} else {
-				fmt.Println("¡Agregado a Mi Lista!")
-			}
-			utils.WaitForEnter()
-		case "3":
-			rateContent(contentID, "audio")
-		case "4":
-			return
-		}
-	}
-}
-
 func showMyList() {
 	utils.ClearScreen()
 	fmt.Println("Mi Lista")
 	fmt.Println("════════")
 
-	favorites, err := playbackService.GetFavorites(currentUser.ID)
+	favorites, err := playbackService.GetFavorites(currentUser.ProfileID)
 	if err != nil {
 		fmt.Printf("Error al cargar favoritos: %v\n", err)
 		utils.WaitForEnter()
@@ -507,25 +442,16 @@ func showMyList() {
 
 	fmt.Println("Contenido en tu lista:")
 	for _, fav := range favorites {
-		var title, details string
-		if fav.ContentType == "audiovisual" {
-			content, _ := contentService.GetAudiovisualByID(fav.ContentID)
-			if content != nil {
-				title = content.Title
-				details = fmt.Sprintf("[%s] %s", content.Type, content.Genre)
-			}
-		} else {
-			content, _ := contentService.GetAudioByID(fav.ContentID)
-			if content != nil {
-				title = fmt.Sprintf("%s - %s", content.Artist, content.Title)
-				details = fmt.Sprintf("[%s] %s", content.Type, content.Genre)
-			}
+		if fav.Item == nil {
+			continue
 		}
-		if title != "" {
-			fmt.Printf("* %s\n", title)
-			fmt.Printf("  %s\n", details)
-			fmt.Println("────────────────────────────────────────")
+		title := fav.Item.Title
+		if fav.Item.Credit != "" {
+			title = fmt.Sprintf("%s - %s", title, fav.Item.Credit)
 		}
+		fmt.Printf("* %s\n", title)
+		fmt.Printf("  [%s] %s\n", fav.Item.Kind, fav.Item.Genre)
+		fmt.Println("────────────────────────────────────────")
 	}
 	utils.WaitForEnter()
 }
@@ -538,13 +464,22 @@ func showProfileMenu() {
 		fmt.Printf("Nombre: %s\n", currentUser.Name)
 		fmt.Printf("Email: %s\n", currentUser.Email)
 		fmt.Printf("Plan actual: %s\n", currentUser.PlanName)
+		printSubscriptionStatus()
 		fmt.Printf("Edad: %d\n", currentUser.Age)
 		fmt.Printf("Clasificación: %s\n", currentUser.AgeRating)
+		fmt.Printf("País: %s\n", currentUser.Country)
+		fmt.Printf("Perfil en uso: %s (%s)\n", currentUser.ProfileName, currentUser.ProfileAgeRating)
 		fmt.Println()
 		fmt.Println("1. Cambiar Plan de Suscripción")
 		fmt.Println("2. Ver Métodos de Pago")
 		fmt.Println("3. Ver Historial de Reproducción")
-		fmt.Println("4. Volver al Menú Principal")
+		fmt.Println("4. Ver Facturas")
+		fmt.Println("5. Ver Dispositivos Activos")
+		fmt.Println("6. Cambiar de Perfil")
+		fmt.Println("7. Gestionar Perfiles")
+		fmt.Println("8. Cerrar Sesión en Todos los Dispositivos")
+		fmt.Println("9. Mis Calificaciones")
+		fmt.Println("10. Volver al Menú Principal")
 		fmt.Print("\nSeleccione una opción: ")
 
 		option := utils.ReadLine("")
@@ -556,6 +491,19 @@ func showProfileMenu() {
 		case "3":
 			viewPlaybackHistory()
 		case "4":
+			viewInvoices()
+		case "5":
+			viewActiveStreams()
+		case "6":
+			switchProfile()
+		case "7":
+			manageProfiles()
+		case "8":
+			revokeAllSessions()
+			return
+		case "9":
+			viewMyRatings()
+		case "10":
 			return
 		default:
 			fmt.Println("Opción inválida.")
@@ -569,7 +517,7 @@ func viewPlaybackHistory() {
 	fmt.Println("Historial de Reproducción")
 	fmt.Println("══════════════════════════")
 
-	history, err := playbackService.GetHistory(currentUser.ID)
+	history, err := playbackService.GetHistory(currentUser.ProfileID)
 	if err != nil {
 		fmt.Printf("Error al cargar el historial: %v\n", err)
 		utils.WaitForEnter()
@@ -584,20 +532,8 @@ func viewPlaybackHistory() {
 
 	fmt.Println("Tus últimas reproducciones:")
 	for _, entry := range history {
-		var title string
-		if entry.ContentType == "audiovisual" {
-			content, _ := contentService.GetAudiovisualByID(entry.ContentID)
-			if content != nil {
-				title = content.Title
-			}
-		} else {
-			content, _ := contentService.GetAudioByID(entry.ContentID)
-			if content != nil {
-				title = content.Title
-			}
-		}
-		if title != "" {
-			fmt.Printf("* %s (%s)\n", title, entry.ContentType)
+		if entry.Item != nil {
+			fmt.Printf("* %s%s (%s)\n", entry.Item.Title, episodeSuffix(entry.EpisodeID), entry.ContentType)
 		}
 	}
 	utils.WaitForEnter()
@@ -617,8 +553,8 @@ func upgradePlan() {
 
 	fmt.Println("Planes disponibles:")
 	for _, p := range plans {
-		fmt.Printf("%d. %s - $%.2f/mes | Calidad: %s | Dispositivos: %d\n",
-			p.ID, p.Name, p.Price, p.MaxQuality, p.MaxDevices)
+		fmt.Printf("%d. %s - $%.2f/mes | Calidad: %s | Dispositivos: %d | Perfiles: %d\n",
+			p.ID, p.Name, p.Price, p.MaxQuality, p.MaxDevices, p.MaxProfiles)
 	}
 
 	planIDStr := utils.ReadLine("\nSeleccione el número del plan deseado (0 para cancelar): ")
@@ -632,31 +568,31 @@ func upgradePlan() {
 		return
 	}
 
-	if planID == currentUser.PlanID {
-		fmt.Println("Ya está suscrito a este plan.")
+	change, err := subscriptionService.PreviewPlanChange(currentUser.ID, planID, time.Now())
+	if err != nil {
+		fmt.Printf("No se puede cambiar al plan: %v\n", err)
 		utils.WaitForEnter()
 		return
 	}
 
-	if planID == 1 {
-		err = userService.UpdateUserPlan(currentUser.ID, 1)
-		if err != nil {
-			fmt.Printf("Error al actualizar el plan: %v\n", err)
-			utils.WaitForEnter()
-			return
-		}
-		currentUser.PlanID = 1
-		currentUser.PlanName = "Free"
-		fmt.Println("Su plan ha sido cambiado a Free.")
-		utils.WaitForEnter()
+	// Los cambios sin cobro (a un plan inferior o a Free) se programan para
+	// el final del período en curso.
+	if change.AmountCents == 0 {
+		applyPlanChange(planID)
 		return
 	}
 
+	if change.Kind == models.PlanChangeUpgrade {
+		fmt.Printf("\nCrédito por lo no usado del plan %s: %s\n", currentUser.PlanName, utils.FormatMoney(change.CreditCents))
+		fmt.Printf("Diferencia prorrateada hasta el %s: %s\n",
+			change.PeriodEnd.Local().Format("02/01/2006"), utils.FormatMoney(change.AmountCents))
+	}
+
 	fmt.Println("\n--- Información de Pago ---")
 	cardHolder := utils.ReadLine("Nombre del titular de la tarjeta: ")
 	cardNumber := utils.ReadLine("Número de tarjeta (16 dígitos): ")
 	expiry := utils.ReadLine("Fecha de vencimiento (MM/AAAA): ")
-	cvvStr := utils.ReadLine("CVV (3 dígitos): ")
+	cvvStr := utils.ReadLine("CVV (3 o 4 dígitos): ")
 
 	var expiryMonth, expiryYear int
 	if len(expiry) == 7 && expiry[2] == '/' {
@@ -675,6 +611,7 @@ func upgradePlan() {
 		return
 	}
 
+	fmt.Printf("Procesando pago de %s para el plan '%s'...\n", utils.FormatMoney(change.AmountCents), getPlanName(planID))
 	err = subscriptionService.ProcessPayment(currentUser.ID, planID, cardHolder, cardNumber, expiryMonth, expiryYear, cvv)
 	if err != nil {
 		fmt.Printf("Error en el pago: %v\n", err)
@@ -684,10 +621,53 @@ func upgradePlan() {
 
 	currentUser.PlanID = planID
 	currentUser.PlanName = getPlanName(planID)
+	fmt.Println("¡Pago aprobado!")
 	fmt.Println("¡Su plan ha sido actualizado exitosamente!")
 	utils.WaitForEnter()
 }
 
+// applyPlanChange aplica un cambio de plan que no requiere pago.
+func applyPlanChange(planID int) {
+	change, err := subscriptionService.ChangePlan(currentUser.ID, planID, time.Now())
+	if err != nil {
+		fmt.Printf("Error al cambiar de plan: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	switch {
+	case change.CurrentPlanID == planID:
+		fmt.Println("Se anuló el cambio de plan programado.")
+	case change.Kind == models.PlanChangeDowngrade && planID == services.FreePlanID:
+		fmt.Printf("Suscripción cancelada. Conservará el plan %s hasta el %s y luego pasará a Free.\n",
+			currentUser.PlanName, change.EffectiveAt.Local().Format("02/01/2006"))
+	case change.Kind == models.PlanChangeDowngrade:
+		fmt.Printf("Cambio programado: conservará el plan %s hasta el %s y luego pasará al plan %s.\n",
+			currentUser.PlanName, change.EffectiveAt.Local().Format("02/01/2006"), getPlanName(planID))
+	default:
+		currentUser.PlanID = planID
+		currentUser.PlanName = getPlanName(planID)
+		fmt.Println("¡Su plan ha sido actualizado exitosamente!")
+	}
+	utils.WaitForEnter()
+}
+
+// printSubscriptionStatus muestra la fecha de renovación o vencimiento del
+// período en curso, si el usuario tiene uno.
+func printSubscriptionStatus() {
+	sub, err := subscriptionService.GetActiveSubscription(currentUser.ID)
+	if err != nil || sub == nil {
+		return
+	}
+	if sub.AutoRenew && sub.PendingPlanID != nil {
+		fmt.Printf("Cambio programado: pasa al plan %s el %s\n", getPlanName(*sub.PendingPlanID), sub.EndDate.Local().Format("02/01/2006"))
+	} else if sub.AutoRenew {
+		fmt.Printf("Se renueva el: %s\n", sub.EndDate.Local().Format("02/01/2006"))
+	} else {
+		fmt.Printf("Cancelada, vence el: %s (luego pasa a Free)\n", sub.EndDate.Local().Format("02/01/2006"))
+	}
+}
+
 func viewPaymentMethods() {
 	utils.ClearScreen()
 	fmt.Println("Métodos de Pago")
@@ -696,13 +676,81 @@ func viewPaymentMethods() {
 	if err != nil {
 		fmt.Println("No tiene métodos de pago guardados.")
 	} else {
-		fmt.Printf("Tarjeta predeterminada: **** **** **** %s\n", method.Last4)
+		fmt.Printf("Tarjeta predeterminada: %s **** **** **** %s\n", method.Brand, method.Last4)
 		fmt.Printf("Titular: %s\n", method.CardHolder)
 		fmt.Printf("Vence: %02d/%d\n", method.ExpiryMonth, method.ExpiryYear)
 	}
 	utils.WaitForEnter()
 }
 
+// viewActiveStreams lista las reproducciones en curso del usuario en todos
+// sus dispositivos y permite cerrar una para liberar su lugar.
+func viewActiveStreams() {
+	utils.ClearScreen()
+	fmt.Println("Dispositivos Activos")
+	fmt.Println("════════════════════")
+
+	sessions, err := streamService.GetActiveSessions(currentUser.ID, time.Now())
+	if err != nil {
+		fmt.Printf("Error al cargar las sesiones: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No hay reproducciones en curso.")
+		utils.WaitForEnter()
+		return
+	}
+
+	for i, session := range sessions {
+		fmt.Printf("%d. %s | %s #%d | desde %s\n", i+1, session.Device, session.ContentType,
+			session.ContentID, session.StartedAt.Local().Format("02/01/2006 15:04"))
+	}
+
+	option := utils.ReadLine("\nNúmero de la sesión a cerrar (0 para volver): ")
+	index, err := utils.ToInt(option)
+	if err != nil || index < 1 || index > len(sessions) {
+		return
+	}
+	if err := streamService.StopStream(currentUser.ID, sessions[index-1].ID, time.Now()); err != nil {
+		fmt.Printf("Error al cerrar la sesión: %v\n", err)
+	} else {
+		fmt.Println("Sesión cerrada.")
+	}
+	utils.WaitForEnter()
+}
+
+func viewInvoices() {
+	utils.ClearScreen()
+	fmt.Println("Mis Facturas")
+	fmt.Println("════════════")
+
+	invoices, err := billingService.GetInvoices(currentUser.ID)
+	if err != nil {
+		fmt.Printf("Error al cargar las facturas: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	if len(invoices) == 0 {
+		fmt.Println("No tiene facturas.")
+		utils.WaitForEnter()
+		return
+	}
+
+	for _, inv := range invoices {
+		fmt.Printf("%s | %s | %s\n", inv.Number, inv.IssuedAt.Local().Format("02/01/2006"), inv.Description)
+		fmt.Printf("   Monto: %s %s", utils.FormatMoney(inv.AmountCents), inv.Currency)
+		if inv.RefundedCents > 0 {
+			fmt.Printf(" | Reembolsado: %s", utils.FormatMoney(inv.RefundedCents))
+		}
+		fmt.Println()
+		fmt.Println("────────────────────────────────────────")
+	}
+	utils.WaitForEnter()
+}
+
 func showAdminPanel() {
 	utils.ClearScreen()
 	fmt.Println("Panel de Administración")
@@ -710,7 +758,9 @@ func showAdminPanel() {
 	fmt.Println("1. Gestionar Usuarios")
 	fmt.Println("2. Gestionar Contenido")
 	fmt.Println("3. Generar Reportes")
-	fmt.Println("4. Volver")
+	fmt.Println("4. Exportar Facturas (CSV)")
+	fmt.Println("5. Moderar Reseñas")
+	fmt.Println("6. Volver")
 	fmt.Print("\nSeleccione una opción: ")
 
 	option := utils.ReadLine("")
@@ -722,6 +772,10 @@ func showAdminPanel() {
 	case "3":
 		generateReports()
 	case "4":
+		exportInvoices()
+	case "5":
+		moderateReviews()
+	case "6":
 		return
 	default:
 		fmt.Println("Opción inválida.")
@@ -733,18 +787,55 @@ func manageUsers() {
 	utils.ClearScreen()
 	fmt.Println("Gestión de Usuarios")
 	fmt.Println("════════════════════")
-	users, err := userService.GetAllUsers()
+	users, err := userService.GetAllUsers(currentUser.actor())
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		utils.WaitForEnter()
 		return
 	}
-	for _, u := range users {
-		adminTag := ""
-		if u.IsAdmin {
-			adminTag = " [ADMIN]"
+	for i, u := range users {
+		roleTag := ""
+		if u.Role != models.RoleViewer {
+			roleTag = " [" + u.Role + "]"
 		}
-		fmt.Printf("- %s%s (%s) | Edad: %d | Clasificación: %s\n", u.Name, adminTag, u.Email, u.Age, u.AgeRating)
+		fmt.Printf("%d. %s%s (%s) | Edad: %d | Clasificación: %s | País: %s\n", i+1, u.Name, roleTag, u.Email, u.Age, u.AgeRating, u.Country)
+	}
+
+	option := utils.ReadLine("\nNúmero del usuario (0 para volver): ")
+	index, err := utils.ToInt(option)
+	if err != nil || index < 1 || index > len(users) {
+		return
+	}
+	fmt.Println("1. Cambiar rol")
+	fmt.Println("2. Cambiar país")
+	switch utils.ReadLine("Seleccione una opción: ") {
+	case "1":
+		changeUserRole(users[index-1])
+	case "2":
+		changeUserCountry(users[index-1])
+	}
+}
+
+// changeUserRole asigna un rol nuevo a un usuario (solo super_admin).
+func changeUserRole(user models.User) {
+	roles := models.Roles()
+	fmt.Printf("\nRol actual de %s: %s\n", user.Name, user.Role)
+	for i, role := range roles {
+		fmt.Printf("%d. %s\n", i+1, role)
+	}
+
+	option := utils.ReadLine("Seleccione el nuevo rol: ")
+	index, err := utils.ToInt(option)
+	if err != nil || index < 1 || index > len(roles) {
+		fmt.Println("Selección inválida.")
+		utils.WaitForEnter()
+		return
+	}
+
+	if _, err := userService.SetRole(currentUser.actor(), user.ID, roles[index-1]); err != nil {
+		fmt.Printf("Error al cambiar el rol: %v\n", err)
+	} else {
+		fmt.Printf("%s ahora tiene el rol %s.\n", user.Name, roles[index-1])
 	}
 	utils.WaitForEnter()
 }
@@ -758,7 +849,10 @@ func manageContent() {
 		fmt.Println("2. Agregar Contenido de Audio")
 		fmt.Println("3. Listar Contenido Audiovisual")
 		fmt.Println("4. Listar Contenido de Audio")
-		fmt.Println("5. Volver")
+		fmt.Println("5. Sistemas de Clasificación")
+		fmt.Println("6. Licencia y Regiones")
+		fmt.Println("7. Temporadas y Episodios")
+		fmt.Println("8. Volver")
 		fmt.Print("\nSeleccione una opción: ")
 
 		option := utils.ReadLine("")
@@ -772,6 +866,12 @@ func manageContent() {
 		case "4":
 			listAudioAdmin()
 		case "5":
+			manageRatingSystems()
+		case "6":
+			manageLicense()
+		case "7":
+			manageSeries()
+		case "8":
 			return
 		default:
 			fmt.Println("Opción inválida.")
@@ -796,7 +896,7 @@ func addAudiovisualContent() {
 		return
 	}
 
-	ageRating := utils.ReadLine("Clasificación (G/PG/PG-13/R): ")
+	ratingSystem, ageRating := readAgeRating(models.ContentTypeAudiovisual)
 	synopsis := utils.ReadLine("Sinopsis: ")
 	yearStr := utils.ReadLine("Año de lanzamiento: ")
 	year, err := utils.ToInt(yearStr)
@@ -806,8 +906,14 @@ func addAudiovisualContent() {
 		return
 	}
 	director := utils.ReadLine("Director: ")
+	qualities := utils.ReadLine("Calidades disponibles (ej. SD,HD,4K; Enter para SD,HD): ")
+
+	var renditions []string
+	if !utils.IsEmpty(qualities) {
+		renditions = strings.Split(qualities, ",")
+	}
 
-	err = contentService.CreateAudiovisual(title, contentType, genre, duration, ageRating, synopsis, year, director)
+	_, err = contentService.CreateAudiovisual(currentUser.actor(), title, contentType, genre, duration, ageRating, ratingSystem, synopsis, year, director, renditions)
 	if err != nil {
 		fmt.Printf("Error al agregar contenido: %v\n", err)
 	} else {
@@ -832,7 +938,7 @@ func addAudioContent() {
 		return
 	}
 
-	ageRating := utils.ReadLine("Clasificación (General/Explicit): ")
+	ratingSystem, ageRating := readAgeRating(models.ContentTypeAudio)
 	artist := utils.ReadLine("Artista: ")
 	album := utils.ReadLine("Álbum: ")
 	trackStr := utils.ReadLine("Número de pista: ")
@@ -841,7 +947,7 @@ func addAudioContent() {
 		trackNumber = 1
 	}
 
-	err = contentService.CreateAudio(title, contentType, genre, duration, ageRating, artist, album, trackNumber)
+	_, err = contentService.CreateAudio(currentUser.actor(), title, contentType, genre, duration, ageRating, ratingSystem, artist, album, trackNumber)
 	if err != nil {
 		fmt.Printf("Error al agregar contenido: %v\n", err)
 	} else {
@@ -886,11 +992,31 @@ func listAudioAdmin() {
 	utils.WaitForEnter()
 }
 
+func exportInvoices() {
+	utils.ClearScreen()
+	fmt.Println("Exportar Facturas")
+	fmt.Println("═════════════════")
+
+	var buf bytes.Buffer
+	count, err := billingService.ExportInvoicesCSV(currentUser.actor(), &buf)
+	if err != nil {
+		fmt.Printf("Error al exportar las facturas: %v\n", err)
+		return
+	}
+
+	fileName := fmt.Sprintf("facturas_%s.csv", time.Now().Format("20060102_150405"))
+	if err := os.WriteFile(fileName, buf.Bytes(), 0o644); err != nil {
+		fmt.Printf("Error al crear el archivo: %v\n", err)
+		return
+	}
+	fmt.Printf("Se exportaron %d facturas a %s\n", count, fileName)
+}
+
 func generateReports() {
 	utils.ClearScreen()
 	fmt.Println("Generación de Reportes")
 	fmt.Println("═══════════════════════")
-	users, err := userService.GetAllUsers()
+	users, err := userService.GetAllUsers(currentUser.actor())
 	if err != nil {
 		fmt.Printf("Error al cargar usuarios: %v\n", err)
 	} else {
@@ -903,7 +1029,7 @@ func generateReports() {
 	} else {
 		fmt.Printf("* Total de Contenido Audiovisual: %d\n", len(audiovisuals))
 		if len(audiovisuals) > 0 {
-			fmt.Printf("* Contenido más popular: '%s' (%.1f⭐)\n", audiovisuals[0].Title, audiovisuals[0].AverageRating)
+			fmt.Printf("* Contenido más popular: '%s' (%.1f⭐, votos: %d)\n", audiovisuals[0].Title, audiovisuals[0].AverageRating, audiovisuals[0].RatingCount)
 		}
 	}
 
@@ -913,7 +1039,7 @@ func generateReports() {
 	} else {
 		fmt.Printf("* Total de Contenido de Audio: %d\n", len(audios))
 		if len(audios) > 0 {
-			fmt.Printf("* Audio más popular: '%s - %s' (%.1f⭐)\n", audios[0].Artist, audios[0].Title, audios[0].AverageRating)
+			fmt.Printf("* Audio más popular: '%s - %s' (%.1f⭐, votos: %d)\n", audios[0].Artist, audios[0].Title, audios[0].AverageRating, audios[0].RatingCount)
 		}
 	}
 
@@ -921,7 +1047,11 @@ func generateReports() {
 }
 
 func logout() {
+	if err := authService.Logout(currentUser.ID, cliTokens.SessionID, time.Now()); err != nil {
+		fmt.Printf("No se pudo cerrar la sesión en el servidor: %v\n", err)
+	}
 	currentUser = nil
+	clearSession()
 	fmt.Println("Sesión cerrada correctamente.")
 	utils.WaitForEnter()
 }
@@ -939,71 +1069,127 @@ func getPlanName(planID int) string {
 	}
 }
 
+// cliDevice identifica esta terminal en el registro de sesiones de
+// reproducción.
+func cliDevice() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "Terminal"
+	}
+	return "Terminal (" + host + ")"
+}
+
+// playAudiovisual reproduce una película o documental; en una serie, el
+// episodio que le toca al perfil.
 func playAudiovisual(contentID int) {
-	content, err := contentService.GetAudiovisualByID(contentID)
+	content, err := contentService.GetAudiovisualByID(currentUser.profile(), contentID, time.Now())
 	if err != nil {
 		fmt.Println("Error al cargar contenido.")
 		utils.WaitForEnter()
 		return
 	}
+	if content.Type == models.TypeSeries {
+		playNextEpisode(content)
+		return
+	}
+
+	start := askResume(content.Ref(), nil)
+	rendition, session, pin, ok := startAudiovisualStream(content)
+	if !ok {
+		return
+	}
+	defer stopStream(session)
 
 	utils.ClearScreen()
 	fmt.Printf("▶ Reproduciendo: %s\n", content.Title)
 	fmt.Println("══════════════════════════════════════")
-	fmt.Println("Simulando reproducción...")
-	fmt.Println("[████████████████████] 100%")
+	fmt.Printf("Calidad: %s\n", rendition.Quality)
 	fmt.Printf("Duración total: %d minutos\n", content.Duration)
 	fmt.Println("══════════════════════════════════════")
 
-	// Registrar en historial
-	if err := playbackService.AddToHistory(currentUser.ID, contentID, "audiovisual"); err != nil {
+	entry, err := playbackService.AddToHistory(currentUser.profile(), content.Ref(), pin, time.Now())
+	if err != nil {
 		fmt.Printf("No se pudo registrar en historial: %v\n", err)
+		utils.WaitForEnter()
+		return
 	}
+	printPlaybackEnd(runPlayback(entry, session, start, content.Duration*60), "Reproducción finalizada")
+	utils.WaitForEnter()
+}
 
-	// Simular progreso (50% visto)
-	progressSeconds := (content.Duration * 60) / 2
-	if err := playbackService.UpdateProgress(currentUser.ID, contentID, "audiovisual", progressSeconds); err != nil {
-		fmt.Printf("No se pudo actualizar progreso: %v\n", err)
+// startAudiovisualStream elige la calidad e inicia la sesión de reproducción,
+// pidiendo el PIN parental si hace falta; devuelve también ese PIN, que se
+// vuelve a presentar al registrar la reproducción en el historial. Si algo
+// falla muestra el error y devuelve ok en false.
+func startAudiovisualStream(content *models.AudiovisualContent) (rendition *models.Rendition, session *models.StreamSession, pin string, ok bool) {
+	fmt.Printf("Calidades disponibles: %s\n", strings.Join(content.Renditions, ", "))
+	quality := utils.ReadLine("Calidad (Enter para la mejor que permita su plan): ")
+	rendition, err := playbackService.SelectRendition(currentUser.profile(), content.ID, quality, "", time.Now())
+	if pinRequired(err) {
+		pin = askParentalPIN(err)
+		rendition, err = playbackService.SelectRendition(currentUser.profile(), content.ID, quality, pin, time.Now())
+	}
+	if err != nil {
+		fmt.Printf("No se puede reproducir: %v\n", err)
+		utils.WaitForEnter()
+		return nil, nil, "", false
 	}
 
-	fmt.Println("\n✓ Reproducción finalizada")
-	fmt.Println("Se ha guardado tu progreso.")
-	utils.WaitForEnter()
+	session, err = streamService.StartStream(currentUser.profile(), cliDevice(), content.ID, models.ContentTypeAudiovisual, pin, time.Now())
+	if err != nil {
+		fmt.Printf("No se puede iniciar la reproducción: %v\n", err)
+		utils.WaitForEnter()
+		return nil, nil, "", false
+	}
+	return rendition, session, pin, true
+}
+
+// stopStream cierra la sesión de reproducción al terminar.
+func stopStream(session *models.StreamSession) {
+	if err := streamService.StopStream(currentUser.ID, session.ID, time.Now()); err != nil {
+		fmt.Printf("No se pudo cerrar la sesión de reproducción: %v\n", err)
+	}
 }
 
 func playAudio(contentID int) {
-	content, err := contentService.GetAudioByID(contentID)
+	content, err := contentService.GetAudioByID(currentUser.profile(), contentID, time.Now())
 	if err != nil {
 		fmt.Println("Error al cargar contenido.")
 		utils.WaitForEnter()
 		return
 	}
 
+	start := askResume(content.Ref(), nil)
+	pin := ""
+	session, err := streamService.StartStream(currentUser.profile(), cliDevice(), contentID, models.ContentTypeAudio, pin, time.Now())
+	if pinRequired(err) {
+		pin = askParentalPIN(err)
+		session, err = streamService.StartStream(currentUser.profile(), cliDevice(), contentID, models.ContentTypeAudio, pin, time.Now())
+	}
+	if err != nil {
+		fmt.Printf("No se puede iniciar la reproducción: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	defer stopStream(session)
+
 	utils.ClearScreen()
 	fmt.Printf("♪ Reproduciendo: %s - %s\n", content.Artist, content.Title)
 	fmt.Println("══════════════════════════════════════")
-	fmt.Println("Simulando reproducción...")
-	fmt.Println("[████████████████████] 100%")
 	fmt.Printf("Duración total: %d minutos\n", content.Duration)
 	fmt.Println("══════════════════════════════════════")
 
-	// Registrar en historial
-	if err := playbackService.AddToHistory(currentUser.ID, contentID, "audio"); err != nil {
+	entry, err := playbackService.AddToHistory(currentUser.profile(), content.Ref(), pin, time.Now())
+	if err != nil {
 		fmt.Printf("No se pudo registrar en historial: %v\n", err)
+		utils.WaitForEnter()
+		return
 	}
-
-	// Simular progreso (70% escuchado)
-	progressSeconds := (content.Duration * 60) * 7 / 10
-	if err := playbackService.UpdateProgress(currentUser.ID, contentID, "audio", progressSeconds); err != nil {
-		fmt.Printf("No se pudo actualizar progreso: %v\n", err)
-	}
-
-	fmt.Println("\n✓ Reproducción finalizada")
-	fmt.Println("Se ha guardado tu progreso.")
+	printPlaybackEnd(runPlayback(entry, session, start, content.Duration*60), "Reproducción finalizada")
 	utils.WaitForEnter()
 }
 
-func rateContent(contentID int, contentType string) {
+func rateContent(ref models.ContentRef) {
 	utils.ClearScreen()
 	fmt.Println("Calificar Contenido")
 	fmt.Println("════════════════════")
@@ -1018,11 +1204,15 @@ func rateContent(contentID int, contentType string) {
 	}
 
 	// Guardar calificación
-	err = contentService.RateContent(currentUser.ID, contentID, contentType, rating)
+	err = contentService.RateContent(currentUser.profile(), ref, rating, time.Now())
 	if err != nil {
 		fmt.Printf("Error al calificar: %v\n", err)
 	} else {
 		fmt.Printf("¡Gracias! Has calificado este contenido con %.1f⭐\n", rating)
+		if strings.EqualFold(utils.ReadLine("¿Desea escribir una reseña? (s/n): "), "s") {
+			writeReview(ref)
+			return
+		}
 	}
 	utils.WaitForEnter()
 }
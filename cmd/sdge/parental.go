@@ -0,0 +1,172 @@
+// cmd/sdge/parental.go
+// Control parental en el menú interactivo: PIN de la cuenta, nivel máximo de
+// madurez y bloqueos por perfil.
+package main
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/services"
+	"SDGEStreaming/internal/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pinRequired indica si la reproducción espera el PIN parental.
+func pinRequired(err error) bool {
+	var appErr *apperrors.AppError
+	return errors.As(err, &appErr) && appErr.Code == services.CodePINRequired
+}
+
+// askParentalPIN muestra el aviso del control parental y pide el PIN.
+func askParentalPIN(err error) string {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		fmt.Println(appErr.Message)
+	}
+	return utils.ReadLine("PIN parental: ")
+}
+
+func setParentalPIN() {
+	fmt.Println("\nEl PIN parental protege el control parental y permite ver contenido")
+	fmt.Println("por encima del límite de un perfil.")
+	password := utils.ReadLine("Contraseña de la cuenta: ")
+	pin := utils.ReadLine("Nuevo PIN (4 dígitos): ")
+
+	if err := parentalService.SetPIN(currentUser.ID, password, pin, time.Now()); err != nil {
+		fmt.Printf("Error al guardar el PIN: %v\n", err)
+	} else {
+		fmt.Println("PIN parental guardado.")
+	}
+	utils.WaitForEnter()
+}
+
+// manageParentalControls edita el control parental de un perfil de la cuenta.
+func manageParentalControls(profiles []models.Profile) {
+	index, err := utils.ToInt(utils.ReadLine("\nNúmero del perfil (0 para cancelar): "))
+	if err != nil || index < 0 || index > len(profiles) {
+		fmt.Println("Selección inválida.")
+		utils.WaitForEnter()
+		return
+	}
+	if index == 0 {
+		return
+	}
+	profile := profiles[index-1]
+
+	controls, err := parentalService.GetControls(currentUser.ID, profile.ID)
+	if err != nil {
+		fmt.Printf("Error al cargar el control parental: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("Control Parental: %s (%s)\n", profile.Name, profile.AgeRating)
+	fmt.Println("══════════════════════════════════════")
+	printParentalControls(&profile, controls)
+
+	fmt.Println("\nNiveles de madurez:")
+	for level, name := range models.MaturityLevels {
+		fmt.Printf("  %d. %s\n", level, name)
+	}
+	fmt.Println("Enter mantiene el valor actual; '-' lo quita.")
+
+	current := ""
+	if controls.MaxLevel != nil {
+		current = fmt.Sprint(*controls.MaxLevel)
+	}
+	controls.MaxLevel = nil
+	if value := editValue("Nivel máximo: ", current); value != "" {
+		level, err := utils.ToInt(value)
+		if err != nil {
+			fmt.Println("Nivel inválido.")
+			utils.WaitForEnter()
+			return
+		}
+		controls.MaxLevel = &level
+	}
+	controls.BlockedGenres = splitList(editValue("Géneros bloqueados (separados por coma): ", strings.Join(controls.BlockedGenres, ", ")))
+
+	blocked, err := editBlockedContent(controls.BlockedContent)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	controls.BlockedContent = blocked
+
+	pin := utils.ReadLine("PIN parental: ")
+	if err := parentalService.UpdateControls(currentUser.ID, pin, controls, time.Now()); err != nil {
+		fmt.Printf("Error al guardar el control parental: %v\n", err)
+	} else {
+		fmt.Println("Control parental guardado.")
+	}
+	utils.WaitForEnter()
+}
+
+func printParentalControls(profile *models.Profile, controls *models.ParentalControls) {
+	if controls.MaxLevel != nil && *controls.MaxLevel >= 0 && *controls.MaxLevel <= models.MaxMaturityLevel {
+		fmt.Printf("Nivel máximo: %d (%s)\n", *controls.MaxLevel, models.MaturityLevels[*controls.MaxLevel])
+	} else {
+		level := models.ProfileMaxLevel(profile.AgeRating)
+		fmt.Printf("Nivel máximo: %d (%s, según clasificación)\n", level, models.MaturityLevels[level])
+	}
+	if len(controls.BlockedGenres) > 0 {
+		fmt.Printf("Géneros bloqueados: %s\n", strings.Join(controls.BlockedGenres, ", "))
+	}
+	for _, b := range controls.BlockedContent {
+		fmt.Printf("Título bloqueado: %s #%d\n", b.ContentType, b.ContentID)
+	}
+}
+
+// editBlockedContent pide los IDs de títulos bloqueados de cada catálogo.
+func editBlockedContent(current []models.BlockedContent) ([]models.BlockedContent, error) {
+	var result []models.BlockedContent
+	for _, catalog := range models.Catalogs {
+		contentType := catalog.ContentType
+		var ids []string
+		for _, b := range current {
+			if b.ContentType == contentType {
+				ids = append(ids, fmt.Sprint(b.ContentID))
+			}
+		}
+		value := editValue(fmt.Sprintf("IDs de %s bloqueados (separados por coma): ", contentType), strings.Join(ids, ", "))
+		for _, raw := range splitList(value) {
+			id, err := utils.ToInt(raw)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("ID '%s' no válido", raw)
+			}
+			result = append(result, models.BlockedContent{ContentID: id, ContentType: contentType})
+		}
+	}
+	return result, nil
+}
+
+// editValue pide un valor mostrando el actual: Enter lo mantiene y '-' lo
+// borra.
+func editValue(prompt, current string) string {
+	if current != "" {
+		prompt = strings.TrimSuffix(prompt, ": ") + " [" + current + "]: "
+	}
+	value := utils.ReadLine(prompt)
+	switch value {
+	case "":
+		return current
+	case "-":
+		return ""
+	}
+	return value
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,92 @@
+// cmd/sdge/player.go
+// Reproducción simulada con latidos de progreso y reanudación.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// playbackSteps es en cuántos pasos se recorre la duración completa.
+const playbackSteps = 10
+
+// askResume ofrece seguir desde donde el perfil dejó el título (en una serie,
+// solo si dejó a medias ese mismo episodio). Devuelve la posición desde la
+// que empezar, en segundos.
+func askResume(ref models.ContentRef, episodeID *int) int {
+	point, err := playbackService.GetResumePoint(currentUser.ProfileID, ref)
+	if err != nil || !point.Resumable() {
+		return 0
+	}
+	if (episodeID == nil) != (point.EpisodeID == nil) || (episodeID != nil && *episodeID != *point.EpisodeID) {
+		return 0
+	}
+
+	answer := utils.ReadLine(fmt.Sprintf("¿Reanudar desde %s? (s/n): ", models.FormatPosition(point.Position)))
+	if strings.EqualFold(answer, "s") {
+		return point.Position
+	}
+	return 0
+}
+
+// runPlayback simula la reproducción de entry desde start hasta duration
+// (segundos): cada Enter avanza una décima parte, F salta al final y D
+// detiene. Cada avance es un latido: informa la posición a PlaybackService
+// y mantiene viva la sesión de streaming. Devuelve el último punto de
+// reanudación, o nil si no se pudo informar ninguno.
+func runPlayback(entry *models.PlaybackHistory, session *models.StreamSession, start, duration int) *models.ResumePoint {
+	step := max(duration/playbackSteps, 1)
+	position := start
+	var point *models.ResumePoint
+
+	for {
+		reported, err := playbackService.ReportProgress(currentUser.ProfileID, entry.ID, position, time.Now())
+		if err != nil {
+			fmt.Printf("No se pudo guardar el progreso: %v\n", err)
+		} else {
+			point = reported
+		}
+		if _, err := streamService.Heartbeat(currentUser.ID, session.ID, time.Now()); err != nil {
+			fmt.Printf("Se perdió la sesión de reproducción: %v\n", err)
+			return point
+		}
+
+		fmt.Printf("%s %s / %s\n", progressBar(position, duration), models.FormatPosition(position), models.FormatPosition(duration))
+		if position >= duration {
+			return point
+		}
+
+		switch strings.ToUpper(utils.ReadLine("Enter: avanzar | F: hasta el final | D: detener: ")) {
+		case "D":
+			return point
+		case "F":
+			position = duration
+		default:
+			position = min(position+step, duration)
+		}
+	}
+}
+
+// progressBar dibuja la parte reproducida en 20 casillas.
+func progressBar(position, duration int) string {
+	filled := 20
+	if duration > 0 {
+		filled = position * 20 / duration
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", 20-filled) + "]"
+}
+
+// printPlaybackEnd cuenta cómo terminó la reproducción: finished si llegó al
+// umbral de terminado o, si no, desde dónde se podrá reanudar.
+func printPlaybackEnd(point *models.ResumePoint, finished string) {
+	switch {
+	case point == nil:
+	case point.Completed:
+		fmt.Println("\n✓ " + finished)
+	default:
+		fmt.Printf("\nSe guardó tu progreso: podrás reanudar desde %s.\n", models.FormatPosition(point.Position))
+	}
+}
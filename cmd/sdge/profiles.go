@@ -0,0 +1,223 @@
+// cmd/sdge/profiles.go
+// Selector y gestión de los perfiles del hogar en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+)
+
+// setProfile carga el perfil como perfil actual.
+func setProfile(profile *models.Profile) {
+	currentUser.ProfileID = profile.ID
+	currentUser.ProfileName = profile.Name
+	currentUser.ProfileAgeRating = profile.AgeRating
+}
+
+// loadProfile carga el perfil elegido en la sesión; si todavía no se eligió
+// ninguno, muestra el selector.
+func loadProfile(session *models.AuthSession) {
+	if session.ProfileID == nil {
+		pickProfile()
+		return
+	}
+
+	profile, err := profileService.ActiveProfile(session)
+	if err != nil {
+		fmt.Printf("No se pudo cargar el perfil: %v\n", err)
+		return
+	}
+	setProfile(profile)
+}
+
+// pickProfile muestra el selector "¿Quién está viendo?". Si la cuenta tiene
+// un solo perfil disponible, lo elige sin preguntar.
+func pickProfile() {
+	profiles, err := profileService.GetProfiles(currentUser.ID)
+	if err != nil {
+		fmt.Printf("No se pudieron cargar los perfiles: %v\n", err)
+		return
+	}
+
+	var available []models.Profile
+	for _, p := range profiles {
+		if !p.Locked {
+			available = append(available, p)
+		}
+	}
+
+	choice := 0
+	if len(available) > 1 {
+		utils.ClearScreen()
+		fmt.Println("¿Quién está viendo?")
+		fmt.Println("═══════════════════")
+		for i, p := range available {
+			fmt.Printf("%d. %s (%s)\n", i+1, p.Name, p.AgeRating)
+		}
+		for {
+			index, err := utils.ToInt(utils.ReadLine("\nSeleccione un perfil: "))
+			if err == nil && index >= 1 && index <= len(available) {
+				choice = index - 1
+				break
+			}
+			fmt.Println("Selección inválida.")
+		}
+	}
+	if len(available) == 0 {
+		return
+	}
+
+	profile, err := profileService.SelectProfile(currentUser.ID, cliTokens.SessionID, available[choice].ID)
+	if err != nil {
+		fmt.Printf("No se pudo elegir el perfil: %v\n", err)
+		return
+	}
+	setProfile(profile)
+}
+
+func manageProfiles() {
+	for {
+		utils.ClearScreen()
+		fmt.Println("Perfiles del Hogar")
+		fmt.Println("══════════════════")
+
+		profiles, err := profileService.GetProfiles(currentUser.ID)
+		if err != nil {
+			fmt.Printf("Error al cargar los perfiles: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+		printProfiles(profiles)
+
+		fmt.Println()
+		fmt.Println("1. Agregar Perfil")
+		fmt.Println("2. Eliminar Perfil")
+		fmt.Println("3. Control Parental")
+		fmt.Println("4. Configurar PIN Parental")
+		fmt.Println("5. Volver")
+		fmt.Print("\nSeleccione una opción: ")
+
+		switch utils.ReadLine("") {
+		case "1":
+			addProfile()
+		case "2":
+			deleteProfile(profiles)
+		case "3":
+			manageParentalControls(profiles)
+		case "4":
+			setParentalPIN()
+		case "5":
+			return
+		default:
+			fmt.Println("Opción inválida.")
+			utils.WaitForEnter()
+		}
+	}
+}
+
+func printProfiles(profiles []models.Profile) {
+	for i, p := range profiles {
+		var tags string
+		if i == 0 {
+			tags += " [principal]"
+		}
+		if p.ID == currentUser.ProfileID {
+			tags += " [actual]"
+		}
+		if p.Locked {
+			tags += " [bloqueado por el plan]"
+		}
+		fmt.Printf("%d. %s (%s)%s\n", i+1, p.Name, p.AgeRating, tags)
+	}
+}
+
+func addProfile() {
+	name := utils.ReadLine("\nNombre del perfil: ")
+
+	ratings := models.ProfileAgeRatings()
+	fmt.Println("Clasificación:")
+	for i, r := range ratings {
+		fmt.Printf("%d. %s\n", i+1, r)
+	}
+	index, err := utils.ToInt(utils.ReadLine("Seleccione la clasificación: "))
+	if err != nil || index < 1 || index > len(ratings) {
+		fmt.Println("Selección inválida.")
+		utils.WaitForEnter()
+		return
+	}
+
+	profile, err := profileService.CreateProfile(currentUser.ID, name, ratings[index-1])
+	if err != nil {
+		fmt.Printf("Error al crear el perfil: %v\n", err)
+	} else {
+		fmt.Printf("Perfil %s creado.\n", profile.Name)
+	}
+	utils.WaitForEnter()
+}
+
+func deleteProfile(profiles []models.Profile) {
+	index, err := utils.ToInt(utils.ReadLine("\nNúmero del perfil a eliminar (0 para cancelar): "))
+	if err != nil || index < 0 || index > len(profiles) {
+		fmt.Println("Selección inválida.")
+		utils.WaitForEnter()
+		return
+	}
+	if index == 0 {
+		return
+	}
+
+	profile := profiles[index-1]
+	confirm := utils.ReadLine(fmt.Sprintf("Se borrarán la lista, el historial y las calificaciones de %s. ¿Continuar? (s/n): ", profile.Name))
+	if confirm != "s" && confirm != "S" {
+		return
+	}
+
+	if err := profileService.DeleteProfile(currentUser.ID, profile.ID); err != nil {
+		fmt.Printf("Error al eliminar el perfil: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	fmt.Printf("Perfil %s eliminado.\n", profile.Name)
+
+	// Si era el perfil en uso, la sesión vuelve al perfil principal.
+	if profile.ID == currentUser.ProfileID {
+		setProfile(&profiles[0])
+	}
+	utils.WaitForEnter()
+}
+
+// switchProfile vuelve a mostrar el selector de perfiles.
+func switchProfile() {
+	profiles, err := profileService.GetProfiles(currentUser.ID)
+	if err != nil {
+		fmt.Printf("Error al cargar los perfiles: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Println("Cambiar de Perfil")
+	fmt.Println("═════════════════")
+	printProfiles(profiles)
+
+	index, err := utils.ToInt(utils.ReadLine("\nSeleccione un perfil (0 para cancelar): "))
+	if err != nil || index < 0 || index > len(profiles) {
+		fmt.Println("Selección inválida.")
+		utils.WaitForEnter()
+		return
+	}
+	if index == 0 {
+		return
+	}
+
+	profile, err := profileService.SelectProfile(currentUser.ID, cliTokens.SessionID, profiles[index-1].ID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	setProfile(profile)
+	fmt.Printf("Ahora está usando el perfil %s.\n", profile.Name)
+	utils.WaitForEnter()
+}
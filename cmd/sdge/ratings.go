@@ -0,0 +1,118 @@
+// cmd/sdge/ratings.go
+// Sistemas de clasificación por edad en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+)
+
+// readAgeRating pide el sistema de clasificación y la clasificación de un
+// contenido nuevo del catálogo indicado.
+func readAgeRating(contentType string) (system, rating string) {
+	systems, err := contentService.GetRatingSystems()
+	if err != nil {
+		fmt.Printf("Error al cargar los sistemas de clasificación: %v\n", err)
+		return "", ""
+	}
+
+	defaultSystem := models.DefaultRatingSystem(contentType)
+	var codes []string
+	for _, s := range systems {
+		if s.ContentType == contentType {
+			codes = append(codes, s.Code)
+		}
+	}
+	system = strings.ToUpper(utils.ReadLine(fmt.Sprintf("Sistema de clasificación (%s; Enter para %s): ", strings.Join(codes, "/"), defaultSystem)))
+	if system == "" {
+		system = defaultSystem
+	}
+
+	var ratings []string
+	for _, s := range systems {
+		if s.Code == system {
+			for _, r := range s.Ratings {
+				ratings = append(ratings, r.Code)
+			}
+		}
+	}
+	rating = utils.ReadLine(fmt.Sprintf("Clasificación (%s): ", strings.Join(ratings, "/")))
+	return system, rating
+}
+
+func manageRatingSystems() {
+	for {
+		utils.ClearScreen()
+		fmt.Println("Sistemas de Clasificación")
+		fmt.Println("═════════════════════════")
+
+		systems, err := contentService.GetRatingSystems()
+		if err != nil {
+			fmt.Printf("Error al cargar los sistemas: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+		for _, s := range systems {
+			country := ""
+			if s.Country != "" {
+				country = ", " + s.Country
+			}
+			fmt.Printf("%s - %s (%s%s)\n", s.Code, s.Name, s.ContentType, country)
+			for _, r := range s.Ratings {
+				fmt.Printf("   %-10s nivel %d (%s) %s\n", r.Code, r.Level, models.MaturityLevels[r.Level], r.Description)
+			}
+		}
+
+		fmt.Println()
+		fmt.Println("1. Agregar Sistema")
+		fmt.Println("2. Volver")
+		fmt.Print("\nSeleccione una opción: ")
+
+		switch utils.ReadLine("") {
+		case "1":
+			addRatingSystem()
+		case "2":
+			return
+		default:
+			fmt.Println("Opción inválida.")
+			utils.WaitForEnter()
+		}
+	}
+}
+
+func addRatingSystem() {
+	system := &models.RatingSystem{
+		Code:        utils.ReadLine("\nCódigo (ej. PEGI): "),
+		Name:        utils.ReadLine("Nombre: "),
+		ContentType: utils.ReadLine("Catálogo (audiovisual/audio): "),
+		Country:     utils.ReadLine("País (código ISO; Enter si es internacional): "),
+	}
+
+	fmt.Println("Niveles de madurez:")
+	for level, name := range models.MaturityLevels {
+		fmt.Printf("  %d. %s\n", level, name)
+	}
+	fmt.Println("Ingrese las clasificaciones de menor a mayor (Enter en el código para terminar).")
+	for {
+		code := utils.ReadLine("Código: ")
+		if code == "" {
+			break
+		}
+		level, err := utils.ToInt(utils.ReadLine("Nivel: "))
+		if err != nil {
+			fmt.Println("Nivel inválido.")
+			continue
+		}
+		description := utils.ReadLine("Descripción: ")
+		system.Ratings = append(system.Ratings, models.MaturityRating{Code: code, Level: level, Description: description})
+	}
+
+	if err := contentService.CreateRatingSystem(currentUser.actor(), system); err != nil {
+		fmt.Printf("Error al agregar el sistema: %v\n", err)
+	} else {
+		fmt.Printf("Sistema %s agregado.\n", system.Code)
+	}
+	utils.WaitForEnter()
+}
@@ -0,0 +1,206 @@
+// cmd/sdge/reviews.go
+// Reseñas en las fichas de contenido y su moderación en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// detailReviewLimit es cuántas reseñas se muestran en la ficha del contenido.
+const detailReviewLimit = 3
+
+// reviewStatusLabels describe el estado de moderación de una reseña propia.
+var reviewStatusLabels = map[string]string{
+	models.ReviewPending:  "pendiente de moderación",
+	models.ReviewApproved: "publicada",
+	models.ReviewHidden:   "oculta por moderación",
+}
+
+// printTopReviews muestra las reseñas más útiles del contenido, sin revelar
+// las que tienen spoilers.
+func printTopReviews(ref models.ContentRef) {
+	reviews, err := reviewService.GetReviews(currentUser.profile(), ref, time.Now())
+	if err != nil {
+		fmt.Printf("Error al cargar reseñas: %v\n", err)
+		return
+	}
+	if len(reviews) == 0 {
+		return
+	}
+
+	fmt.Printf("\nReseñas (%d):\n", len(reviews))
+	for i, rv := range reviews {
+		if i == detailReviewLimit {
+			fmt.Println("  … más en la opción Reseñas")
+			break
+		}
+		printReview("  ", rv, false)
+	}
+}
+
+// printReview muestra una reseña después de prefix; si tiene spoilers, el
+// texto solo aparece con reveal.
+func printReview(prefix string, rv models.Review, reveal bool) {
+	fmt.Printf("%s%s — %.1f⭐ | 👍 %d\n", prefix, rv.ProfileName, rv.Rating, rv.Helpful)
+	if rv.Spoiler && !reveal {
+		fmt.Println("    ⚠ Contiene spoilers")
+		return
+	}
+	for _, line := range strings.Split(rv.Body, "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+}
+
+// showReviews lista las reseñas del contenido y permite votarlas, denunciarlas
+// y escribir o borrar la propia.
+func showReviews(ref models.ContentRef) {
+	reveal := false
+	for {
+		reviews, err := reviewService.GetReviews(currentUser.profile(), ref, time.Now())
+		if err != nil {
+			fmt.Printf("Error al cargar reseñas: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Println("Reseñas")
+		fmt.Println("═══════")
+		if mine, err := reviewService.GetMyReview(currentUser.ProfileID, ref); err == nil {
+			fmt.Printf("Su reseña está %s.\n\n", reviewStatusLabels[mine.Status])
+		}
+		if len(reviews) == 0 {
+			fmt.Println("Todavía no hay reseñas publicadas.")
+		}
+		for i, rv := range reviews {
+			printReview(fmt.Sprintf("%d. ", i+1), rv, reveal)
+			if rv.VotedHelpful {
+				fmt.Println("    (le resultó útil)")
+			}
+		}
+
+		fmt.Println("\nU. Marcar como útil | D. Denunciar | V. Mostrar/ocultar spoilers")
+		fmt.Println("E. Escribir o editar mi reseña | B. Borrar mi reseña")
+		switch strings.ToUpper(utils.ReadLine("Seleccione una opción (0 para volver): ")) {
+		case "U":
+			if rv, ok := chooseReview(reviews); ok {
+				err = reviewService.VoteHelpful(rv.ID, currentUser.ProfileID, !rv.VotedHelpful, time.Now())
+				if rv.VotedHelpful {
+					reportResult(err, "Voto quitado.")
+				} else {
+					reportResult(err, "¡Gracias por su voto!")
+				}
+			}
+		case "D":
+			if rv, ok := chooseReview(reviews); ok {
+				reason := utils.ReadLine("Motivo de la denuncia: ")
+				err = reviewService.ReportReview(rv.ID, currentUser.ProfileID, reason, time.Now())
+				reportResult(err, "Denuncia enviada. Un moderador la revisará.")
+			}
+		case "V":
+			reveal = !reveal
+		case "E":
+			writeReview(ref)
+		case "B":
+			err = reviewService.DeleteMyReview(currentUser.ProfileID, ref)
+			reportResult(err, "Reseña borrada.")
+		default:
+			return
+		}
+	}
+}
+
+// chooseReview pide el número de una reseña del listado.
+func chooseReview(reviews []models.Review) (models.Review, bool) {
+	n, err := utils.ToInt(utils.ReadLine("Número de la reseña: "))
+	if err != nil || n < 1 || n > len(reviews) {
+		fmt.Println("Número inválido.")
+		utils.WaitForEnter()
+		return models.Review{}, false
+	}
+	return reviews[n-1], true
+}
+
+// reportResult muestra el error o el mensaje de éxito y espera Enter.
+func reportResult(err error, success string) {
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Println(success)
+	}
+	utils.WaitForEnter()
+}
+
+// writeReview pide el texto de la reseña del perfil sobre el contenido, que
+// ya tiene que estar calificado.
+func writeReview(ref models.ContentRef) {
+	if mine, err := reviewService.GetMyReview(currentUser.ProfileID, ref); err == nil {
+		fmt.Printf("\nSu reseña actual (%s):\n%s\n", reviewStatusLabels[mine.Status], mine.Body)
+	}
+	body := utils.ReadLine(fmt.Sprintf("\nEscriba su reseña (hasta %d caracteres, vacío para cancelar): ", models.MaxReviewLength))
+	if body == "" {
+		return
+	}
+	spoiler := strings.EqualFold(utils.ReadLine("¿Contiene spoilers? (s/n): "), "s")
+
+	_, err := reviewService.WriteReview(currentUser.profile(), ref, body, spoiler, time.Now())
+	reportResult(err, "¡Gracias! Su reseña se publicará cuando la apruebe un moderador.")
+}
+
+// moderateReviews muestra la cola de moderación: reseñas nuevas o editadas y
+// reseñas publicadas con denuncias.
+func moderateReviews() {
+	for {
+		queue, err := reviewService.GetModerationQueue(currentUser.actor())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Println("Moderación de Reseñas")
+		fmt.Println("═════════════════════")
+		if len(queue) == 0 {
+			fmt.Println("No hay reseñas para moderar.")
+			return
+		}
+		for i, rv := range queue {
+			title := fmt.Sprintf("contenido %d", rv.ContentID)
+			if rv.Item != nil {
+				title = rv.Item.Title
+			}
+			fmt.Printf("%d. [%s] %s (%s)\n", i+1, reviewStatusLabels[rv.Status], title, rv.ContentType)
+			printReview("    ", rv, true)
+			if rv.Spoiler {
+				fmt.Println("    (marcada con spoilers)")
+			}
+			for _, rr := range rv.Reports {
+				fmt.Printf("    🚩 %s (%s)\n", rr.Reason, rr.ReportedAt.Local().Format("02/01/2006 15:04"))
+			}
+			fmt.Println("────────────────────────────────────────")
+		}
+
+		n, err := utils.ToInt(utils.ReadLine("\nNúmero de la reseña a moderar (0 para volver): "))
+		if err != nil || n < 1 || n > len(queue) {
+			return
+		}
+		fmt.Println("1. Aprobar")
+		fmt.Println("2. Ocultar")
+		fmt.Println("3. Eliminar")
+		actions := map[string]string{
+			"1": models.ModerationApprove,
+			"2": models.ModerationHide,
+			"3": models.ModerationDelete,
+		}
+		action, ok := actions[utils.ReadLine("Seleccione una acción: ")]
+		if !ok {
+			continue
+		}
+		err = reviewService.ModerateReview(currentUser.actor(), queue[n-1].ID, action, time.Now())
+		reportResult(err, "Reseña moderada.")
+	}
+}
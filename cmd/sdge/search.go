@@ -0,0 +1,55 @@
+// cmd/sdge/search.go
+// Buscador del catálogo en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"time"
+)
+
+// searchContent busca en todos los catálogos y abre el resultado elegido.
+func searchContent(isGuest bool) {
+	text := utils.ReadLine("Buscar (título, sinopsis, director, artista, álbum o género): ")
+	if text == "" {
+		return
+	}
+	results, err := searchService.Search(currentUser.profile(), text, 0, time.Now())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("🔎 Resultados para \"%s\":\n", text)
+	if len(results) == 0 {
+		fmt.Println("No se encontró contenido.")
+		utils.WaitForEnter()
+		return
+	}
+	for i, r := range results {
+		credit := ""
+		if r.Credit != "" {
+			credit = " - " + r.Credit
+		}
+		fmt.Printf("%d. %s%s (%s, %s)\n", i+1, r.Title, credit, r.Kind, r.Genre)
+	}
+	if isGuest {
+		utils.WaitForEnter()
+		return
+	}
+
+	choice := utils.ReadLine("\nNúmero del resultado para reproducirlo (0 para volver): ")
+	n, err := utils.ToInt(choice)
+	if err != nil || n < 1 || n > len(results) {
+		return
+	}
+	switch r := results[n-1]; r.ContentType {
+	case models.ContentTypeAudiovisual:
+		playAudiovisual(r.ContentID)
+	case models.ContentTypeAudio:
+		playAudio(r.ContentID)
+	}
+}
@@ -0,0 +1,228 @@
+// cmd/sdge/series.go
+// Temporadas y episodios de las series en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// showSeasons lista las temporadas de la serie y permite elegir un episodio.
+func showSeasons(content *models.AudiovisualContent) {
+	series, err := contentService.GetSeries(currentUser.profile(), content.ID, time.Now())
+	if err != nil {
+		fmt.Printf("Error al cargar la serie: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("═══ %s ═══\n", series.Title)
+	if len(series.Seasons) == 0 {
+		fmt.Println("La serie todavía no tiene episodios.")
+		utils.WaitForEnter()
+		return
+	}
+	printSeasons(series.Seasons)
+
+	episodeIDStr := utils.ReadLine("\nIngrese el ID del episodio para reproducirlo (0 para volver): ")
+	if episodeIDStr == "0" || episodeIDStr == "" {
+		return
+	}
+	episodeID, err := utils.ToInt(episodeIDStr)
+	if err != nil {
+		fmt.Println("ID inválido.")
+		utils.WaitForEnter()
+		return
+	}
+	for _, season := range series.Seasons {
+		for i := range season.Episodes {
+			if season.Episodes[i].ID == episodeID {
+				playEpisode(content, &season.Episodes[i])
+				return
+			}
+		}
+	}
+	fmt.Println("El episodio no es de esta serie.")
+	utils.WaitForEnter()
+}
+
+func printSeasons(seasons []models.Season) {
+	for _, season := range seasons {
+		fmt.Printf("\nTemporada %d: %s (ID: %d)\n", season.Number, season.Title, season.ID)
+		for _, e := range season.Episodes {
+			fmt.Printf("   ID: %d | %s - %s (%d min)\n", e.ID, e.Code(), e.Title, e.Duration)
+		}
+	}
+}
+
+// playNextEpisode reproduce el episodio que le toca al perfil.
+func playNextEpisode(content *models.AudiovisualContent) {
+	episode, err := playbackService.NextEpisode(currentUser.ProfileID, content.ID)
+	if err != nil {
+		fmt.Printf("No se puede reproducir: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+	fmt.Printf("Episodio: %s - %s\n", episode.Code(), episode.Title)
+	playEpisode(content, episode)
+}
+
+// playEpisode reproduce un episodio y, si se terminó, ofrece el siguiente.
+func playEpisode(content *models.AudiovisualContent, episode *models.Episode) {
+	start := askResume(content.Ref(), &episode.ID)
+	rendition, session, pin, ok := startAudiovisualStream(content)
+	if !ok {
+		return
+	}
+
+	utils.ClearScreen()
+	fmt.Printf("▶ Reproduciendo: %s - %s %s\n", content.Title, episode.Code(), episode.Title)
+	fmt.Println("══════════════════════════════════════")
+	fmt.Printf("Calidad: %s\n", rendition.Quality)
+	fmt.Printf("Duración del episodio: %d minutos\n", episode.Duration)
+	fmt.Println("══════════════════════════════════════")
+
+	entry, err := playbackService.AddEpisodeToHistory(currentUser.profile(), episode.ID, pin, time.Now())
+	if err != nil {
+		fmt.Printf("No se pudo registrar en historial: %v\n", err)
+		stopStream(session)
+		utils.WaitForEnter()
+		return
+	}
+	point := runPlayback(entry, session, start, episode.Duration*60)
+	stopStream(session)
+
+	printPlaybackEnd(point, "Episodio finalizado")
+	if point == nil || !point.Completed {
+		utils.WaitForEnter()
+		return
+	}
+	next, err := playbackService.NextEpisode(currentUser.ProfileID, content.ID)
+	if err != nil {
+		fmt.Println("Ya viste todos los episodios disponibles.")
+		utils.WaitForEnter()
+		return
+	}
+	answer := utils.ReadLine(fmt.Sprintf("¿Ver el siguiente episodio, %s - %s? (s/n): ", next.Code(), next.Title))
+	if strings.EqualFold(answer, "s") {
+		playEpisode(content, next)
+	}
+}
+
+// manageSeries agrega temporadas y episodios a una serie del catálogo.
+func manageSeries() {
+	seriesID, err := utils.ToInt(utils.ReadLine("ID de la serie: "))
+	if err != nil {
+		fmt.Println("ID inválido.")
+		utils.WaitForEnter()
+		return
+	}
+
+	for {
+		series, err := contentService.GetManagedSeries(currentUser.actor(), seriesID)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+
+		utils.ClearScreen()
+		fmt.Printf("Temporadas y Episodios: %s\n", series.Title)
+		fmt.Println("══════════════════════════")
+		printSeasons(series.Seasons)
+		fmt.Println()
+		fmt.Println("1. Agregar Temporada")
+		fmt.Println("2. Agregar Episodio")
+		fmt.Println("3. Volver")
+		fmt.Print("\nSeleccione una opción: ")
+
+		switch utils.ReadLine("") {
+		case "1":
+			addSeason(series)
+		case "2":
+			addEpisode(series)
+		case "3":
+			return
+		default:
+			fmt.Println("Opción inválida.")
+			utils.WaitForEnter()
+		}
+	}
+}
+
+func addSeason(series *models.Series) {
+	number, err := utils.ToInt(utils.ReadLine(fmt.Sprintf("Número de temporada (Enter para %d): ", len(series.Seasons)+1)))
+	if err != nil {
+		number = len(series.Seasons) + 1
+	}
+	title := utils.ReadLine("Título (Enter para \"Temporada N\"): ")
+
+	if _, err := contentService.AddSeason(currentUser.actor(), series.ID, number, title); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Println("¡Temporada agregada!")
+	}
+	utils.WaitForEnter()
+}
+
+func addEpisode(series *models.Series) {
+	if len(series.Seasons) == 0 {
+		fmt.Println("Agregue primero una temporada.")
+		utils.WaitForEnter()
+		return
+	}
+
+	seasonNumber, err := utils.ToInt(utils.ReadLine("Número de temporada: "))
+	if err != nil {
+		fmt.Println("Número inválido.")
+		utils.WaitForEnter()
+		return
+	}
+	var season *models.Season
+	for i := range series.Seasons {
+		if series.Seasons[i].Number == seasonNumber {
+			season = &series.Seasons[i]
+		}
+	}
+	if season == nil {
+		fmt.Println("La serie no tiene esa temporada.")
+		utils.WaitForEnter()
+		return
+	}
+
+	number, err := utils.ToInt(utils.ReadLine(fmt.Sprintf("Número de episodio (Enter para %d): ", len(season.Episodes)+1)))
+	if err != nil {
+		number = len(season.Episodes) + 1
+	}
+	title := utils.ReadLine("Título: ")
+	duration, err := utils.ToInt(utils.ReadLine("Duración (minutos): "))
+	if err != nil {
+		fmt.Println("Duración inválida.")
+		utils.WaitForEnter()
+		return
+	}
+	synopsis := utils.ReadLine("Sinopsis: ")
+
+	if _, err := contentService.AddEpisode(currentUser.actor(), season.ID, number, title, duration, synopsis); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Println("¡Episodio agregado!")
+	}
+	utils.WaitForEnter()
+}
+
+// episodeSuffix devuelve " - T1E2" si hay un episodio, o nada.
+func episodeSuffix(episodeID *int) string {
+	if episodeID == nil {
+		return ""
+	}
+	episode, err := contentService.GetEpisode(currentUser.profile(), *episodeID, time.Now())
+	if err != nil {
+		return ""
+	}
+	return " - " + episode.Code()
+}
@@ -0,0 +1,129 @@
+// cmd/sdge/session.go
+// Sesión del menú interactivo: los tokens se guardan en un archivo junto a la
+// base de datos para que el inicio de sesión sobreviva a reinicios.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// cliClient identifica en auth_sessions las sesiones abiertas desde el menú.
+const cliClient = "cli"
+
+const sessionFile = ".sdge_session"
+
+// cliTokens son los tokens de la sesión en curso del menú.
+var cliTokens *models.AuthTokens
+
+// resumeSession retoma la sesión guardada, si sigue vigente.
+func resumeSession() {
+	data, err := os.ReadFile(sessionFile)
+	if err != nil {
+		return
+	}
+
+	var saved models.AuthTokens
+	if err := json.Unmarshal(data, &saved); err != nil {
+		clearSession()
+		return
+	}
+
+	user, tokens, err := authService.Refresh(saved.RefreshToken, time.Now())
+	if err != nil {
+		clearSession()
+		return
+	}
+	startSession(user, tokens)
+}
+
+// startSession guarda los tokens, carga el usuario como usuario actual y
+// luego el perfil de la sesión (o el selector de perfiles si no hay uno).
+func startSession(user *models.User, tokens *models.AuthTokens) {
+	cliTokens = tokens
+	if data, err := json.Marshal(tokens); err == nil {
+		if err := os.WriteFile(sessionFile, data, 0o600); err != nil {
+			fmt.Printf("No se pudo guardar la sesión: %v\n", err)
+		}
+	}
+
+	// Si el período de suscripción venció, renovarlo o volver a Free antes de
+	// cargar el plan del usuario.
+	if err := subscriptionService.RefreshSubscription(user.ID, time.Now()); err != nil {
+		fmt.Printf("No se pudo actualizar la suscripción: %v\n", err)
+	} else if refreshed, err := userService.GetByID(user.ID); err == nil {
+		user = refreshed
+	}
+
+	currentUser = &CurrentUser{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		PlanID:    user.PlanID,
+		PlanName:  getPlanName(user.PlanID),
+		Age:       user.Age,
+		AgeRating: user.AgeRating,
+		Country:   user.Country,
+		Role:      user.Role,
+	}
+
+	_, session, err := authService.Authenticate(tokens.AccessToken, time.Now())
+	if err != nil {
+		fmt.Printf("No se pudo cargar la sesión: %v\n", err)
+		return
+	}
+	loadProfile(session)
+}
+
+// checkSession confirma que la sesión sigue abierta (no fue revocada desde
+// otro dispositivo) y renueva el token de acceso si venció. Si la sesión ya
+// no es válida, vuelve al menú de inicio.
+func checkSession() bool {
+	now := time.Now()
+	if _, session, err := authService.Authenticate(cliTokens.AccessToken, now); err == nil {
+		// El perfil pudo borrarse o quedar fuera del plan desde otro dispositivo.
+		if profile, err := profileService.ActiveProfile(session); err == nil {
+			setProfile(profile)
+		}
+		return true
+	}
+
+	user, tokens, err := authService.Refresh(cliTokens.RefreshToken, now)
+	if err == nil {
+		startSession(user, tokens)
+		return true
+	}
+
+	currentUser = nil
+	clearSession()
+	fmt.Println("Su sesión venció o fue cerrada. Inicie sesión nuevamente.")
+	utils.WaitForEnter()
+	return false
+}
+
+// clearSession olvida la sesión local sin revocarla.
+func clearSession() {
+	cliTokens = nil
+	if err := os.Remove(sessionFile); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("No se pudo borrar la sesión guardada: %v\n", err)
+	}
+}
+
+// revokeAllSessions cierra la sesión en todos los dispositivos, incluido este.
+func revokeAllSessions() {
+	n, err := authService.RevokeAll(currentUser.ID, time.Now())
+	if err != nil {
+		fmt.Printf("Error al cerrar las sesiones: %v\n", err)
+		utils.WaitForEnter()
+		return
+	}
+
+	currentUser = nil
+	clearSession()
+	fmt.Printf("Se cerraron %d sesiones. Inicie sesión nuevamente.\n", n)
+	utils.WaitForEnter()
+}
@@ -0,0 +1,77 @@
+// cmd/sdge/user_ratings.go
+// Calificaciones de los perfiles en el menú interactivo.
+package main
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// printRatingSummary muestra el promedio, los votos, el puntaje con el que
+// se ordena el catálogo y cuántos votos hay en cada punto de la escala.
+func printRatingSummary(ref models.ContentRef) {
+	summary, err := contentService.GetRatingSummary(currentUser.profile(), ref, time.Now())
+	if err != nil {
+		fmt.Printf("Error al cargar calificaciones: %v\n", err)
+		return
+	}
+	if summary.Count == 0 {
+		fmt.Println("Promedio de calificación: sin votos todavía")
+		return
+	}
+	fmt.Printf("Promedio de calificación: %.1f⭐ | Votos: %d | Puntaje: %.1f\n", summary.Average, summary.Count, summary.Weighted)
+
+	most := 0
+	for _, votes := range summary.Histogram {
+		most = max(most, votes)
+	}
+	for i := len(summary.Histogram) - 1; i >= 0; i-- {
+		votes := summary.Histogram[i]
+		bar := strings.Repeat("█", (votes*20+most-1)/most)
+		fmt.Printf("  %2d⭐ %-20s %d\n", i+1, bar, votes)
+	}
+}
+
+// viewMyRatings lista las calificaciones del perfil y permite borrarlas.
+func viewMyRatings() {
+	for {
+		utils.ClearScreen()
+		fmt.Println("Mis Calificaciones")
+		fmt.Println("══════════════════")
+
+		ratings, err := contentService.GetProfileRatings(currentUser.ProfileID)
+		if err != nil {
+			fmt.Printf("Error al cargar calificaciones: %v\n", err)
+			utils.WaitForEnter()
+			return
+		}
+		if len(ratings) == 0 {
+			fmt.Println("Todavía no has calificado ningún contenido.")
+			utils.WaitForEnter()
+			return
+		}
+
+		for i, r := range ratings {
+			title := fmt.Sprintf("(contenido %d ya no disponible)", r.ContentID)
+			if r.Item != nil {
+				title = r.Item.Title
+			}
+			fmt.Printf("%d. %s (%s) — %.1f⭐ el %s\n", i+1, title, r.ContentType, r.Rating, r.RatedAt.Local().Format("02/01/2006"))
+		}
+
+		choice := utils.ReadLine("\nNúmero de la calificación a borrar (0 para volver): ")
+		n, err := utils.ToInt(choice)
+		if err != nil || n < 1 || n > len(ratings) {
+			return
+		}
+		if err := contentService.DeleteRating(currentUser.ProfileID, ratings[n-1].ContentRef); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			fmt.Println("Calificación borrada.")
+		}
+		utils.WaitForEnter()
+	}
+}
@@ -3,6 +3,6 @@ module SDGEStreaming
 go 1.25.4
 
 require (
-	github.com/mattn/go-sqlite3 v1.14.32 // indirect
-	golang.org/x/crypto v0.45.0 // indirect
+	github.com/mattn/go-sqlite3 v1.14.32
+	golang.org/x/crypto v0.45.0
 )
@@ -0,0 +1,68 @@
+// internal/api/auth.go
+package api
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type contextKey int
+
+const (
+	userKey contextKey = iota
+	sessionKey
+	profileKey
+)
+
+// requireUser autentica la petición con el token de acceso de la cabecera
+// "Authorization: Bearer <token>" y deja el usuario, la sesión y el perfil
+// activo en el contexto para el handler.
+func (s *Server) requireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeError(w, apperrors.ErrUnauthorized())
+			return
+		}
+
+		user, session, err := s.authService.Authenticate(token, time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		profile, err := s.profileService.ActiveProfile(session)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userKey, user)
+		ctx = context.WithValue(ctx, sessionKey, session)
+		ctx = context.WithValue(ctx, profileKey, profile)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// currentUser devuelve el usuario autenticado por requireUser.
+func currentUser(r *http.Request) *models.User {
+	user, _ := r.Context().Value(userKey).(*models.User)
+	return user
+}
+
+// currentSession devuelve la sesión con la que se autenticó la petición.
+func currentSession(r *http.Request) *models.AuthSession {
+	session, _ := r.Context().Value(sessionKey).(*models.AuthSession)
+	return session
+}
+
+// currentProfile devuelve el perfil elegido en la sesión (o el principal de
+// la cuenta si todavía no se eligió ninguno).
+func currentProfile(r *http.Request) *models.Profile {
+	profile, _ := r.Context().Value(profileKey).(*models.Profile)
+	return profile
+}
@@ -0,0 +1,335 @@
+// internal/api/content.go
+package api
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type audiovisualRequest struct {
+	Title        string   `json:"title"`
+	Type         string   `json:"type"`
+	Genre        string   `json:"genre"`
+	Duration     int      `json:"duration"`
+	AgeRating    string   `json:"age_rating"`
+	RatingSystem string   `json:"rating_system"`
+	Synopsis     string   `json:"synopsis"`
+	ReleaseYear  int      `json:"release_year"`
+	Director     string   `json:"director"`
+	Renditions   []string `json:"renditions"`
+}
+
+type audioRequest struct {
+	Title        string `json:"title"`
+	Type         string `json:"type"`
+	Genre        string `json:"genre"`
+	Duration     int    `json:"duration"`
+	AgeRating    string `json:"age_rating"`
+	RatingSystem string `json:"rating_system"`
+	Artist       string `json:"artist"`
+	Album        string `json:"album"`
+	TrackNumber  int    `json:"track_number"`
+}
+
+type ratingRequest struct {
+	Rating float64 `json:"rating"`
+}
+
+// licenseRequest usa fechas RFC 3339; null deja la ventana abierta por ese lado.
+type licenseRequest struct {
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+	Countries      []string   `json:"countries"`
+}
+
+// catalogQuery lee de la query string los filtros, el orden y la página de
+// un listado de catálogo.
+func catalogQuery(r *http.Request) (models.CatalogQuery, error) {
+	values := r.URL.Query()
+	q := models.CatalogQuery{
+		Genre:     values.Get("genre"),
+		Kind:      values.Get("type"),
+		AgeRating: values.Get("age_rating"),
+		Sort:      values.Get("sort"),
+	}
+
+	ints := []struct {
+		name string
+		dest *int
+	}{
+		{"year_from", &q.YearFrom},
+		{"year_to", &q.YearTo},
+		{"min_duration", &q.MinDuration},
+		{"max_duration", &q.MaxDuration},
+		{"offset", &q.Offset},
+		{"limit", &q.Limit},
+	}
+	for _, field := range ints {
+		n, err := queryInt(r, field.name)
+		if err != nil {
+			return q, err
+		}
+		*field.dest = n
+	}
+
+	if value := values.Get("min_rating"); value != "" {
+		rating, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return q, apperrors.ErrInvalidInput("min_rating")
+		}
+		q.MinRating = rating
+	}
+	return q, nil
+}
+
+// --- AUDIOVISUAL ---
+
+func (s *Server) handleListAudiovisual(w http.ResponseWriter, r *http.Request) {
+	q, err := catalogQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	page, err := s.contentService.QueryAudiovisual(currentProfile(r), q, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleGetAudiovisual(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	content, err := s.contentService.GetAudiovisualByID(currentProfile(r), id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, content)
+}
+
+func (s *Server) handleCreateAudiovisual(w http.ResponseWriter, r *http.Request) {
+	var req audiovisualRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	content, err := s.contentService.CreateAudiovisual(currentUser(r), req.Title, req.Type, req.Genre, req.Duration, req.AgeRating, req.RatingSystem, req.Synopsis, req.ReleaseYear, req.Director, req.Renditions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, content)
+}
+
+// --- AUDIO ---
+
+func (s *Server) handleListAudio(w http.ResponseWriter, r *http.Request) {
+	q, err := catalogQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	page, err := s.contentService.QueryAudio(currentProfile(r), q, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleGetAudio(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	content, err := s.contentService.GetAudioByID(currentProfile(r), id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, content)
+}
+
+func (s *Server) handleCreateAudio(w http.ResponseWriter, r *http.Request) {
+	var req audioRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	content, err := s.contentService.CreateAudio(currentUser(r), req.Title, req.Type, req.Genre, req.Duration, req.AgeRating, req.RatingSystem, req.Artist, req.Album, req.TrackNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, content)
+}
+
+// --- SISTEMAS DE CLASIFICACIÓN ---
+
+type ratingSystemRequest struct {
+	Code        string                  `json:"code"`
+	Name        string                  `json:"name"`
+	ContentType string                  `json:"content_type"`
+	Country     string                  `json:"country"`
+	Ratings     []models.MaturityRating `json:"ratings"`
+}
+
+func (s *Server) handleListRatingSystems(w http.ResponseWriter, r *http.Request) {
+	systems, err := s.contentService.GetRatingSystems()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, systems)
+}
+
+func (s *Server) handleCreateRatingSystem(w http.ResponseWriter, r *http.Request) {
+	var req ratingSystemRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	system := &models.RatingSystem{
+		Code:        req.Code,
+		Name:        req.Name,
+		ContentType: req.ContentType,
+		Country:     req.Country,
+		Ratings:     req.Ratings,
+	}
+	if err := s.contentService.CreateRatingSystem(currentUser(r), system); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, system)
+}
+
+// --- CALIFICACIONES ---
+
+func (s *Server) handleRateContent(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var req ratingRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.contentService.RateContent(currentProfile(r), models.ContentRef{ContentID: id, ContentType: contentType}, req.Rating, time.Now()); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// handleRatingSummary devuelve el promedio, los votos, el puntaje bayesiano
+// y el histograma de calificaciones del contenido.
+func (s *Server) handleRatingSummary(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		summary, err := s.contentService.GetRatingSummary(currentProfile(r), models.ContentRef{ContentID: id, ContentType: contentType}, time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, summary)
+	}
+}
+
+// handleListMyRatings devuelve las calificaciones del perfil actual.
+func (s *Server) handleListMyRatings(w http.ResponseWriter, r *http.Request) {
+	ratings, err := s.contentService.GetProfileRatings(currentProfile(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ratings)
+}
+
+func (s *Server) handleDeleteMyRating(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.contentService.DeleteRating(currentProfile(r).ID, models.ContentRef{ContentID: id, ContentType: r.PathValue("type")}); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// --- LICENCIAS ---
+
+func (s *Server) handleGetLicense(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		license, err := s.contentService.GetLicense(id, contentType)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, license)
+	}
+}
+
+func (s *Server) handleSetLicense(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var req licenseRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		license := &models.ContentLicense{
+			ContentID:      id,
+			ContentType:    contentType,
+			AvailableFrom:  req.AvailableFrom,
+			AvailableUntil: req.AvailableUntil,
+			Countries:      req.Countries,
+		}
+		if err := s.contentService.SetLicense(currentUser(r), license); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, license)
+	}
+}
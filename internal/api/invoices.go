@@ -0,0 +1,30 @@
+// internal/api/invoices.go
+package api
+
+import (
+	"bytes"
+	"net/http"
+)
+
+func (s *Server) handleListInvoices(w http.ResponseWriter, r *http.Request) {
+	invoices, err := s.billingService.GetInvoices(currentUser(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, invoices)
+}
+
+// handleExportInvoices descarga todas las facturas como CSV.
+func (s *Server) handleExportInvoices(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if _, err := s.billingService.ExportInvoicesCSV(currentUser(r), &buf); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="facturas.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,111 @@
+// internal/api/library.go
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+func (s *Server) handleListArtists(w http.ResponseWriter, r *http.Request) {
+	artists, err := s.libraryService.GetArtists()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, artists)
+}
+
+// handleGetArtist devuelve la discografía del artista.
+func (s *Server) handleGetArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	artist, err := s.libraryService.GetArtist(currentProfile(r), id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, artist)
+}
+
+func (s *Server) handleListFollowedArtists(w http.ResponseWriter, r *http.Request) {
+	artists, err := s.libraryService.GetFollowedArtists(currentProfile(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, artists)
+}
+
+func (s *Server) handleFollowArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.libraryService.FollowArtist(currentProfile(r).ID, id, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleUnfollowArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.libraryService.UnfollowArtist(currentProfile(r).ID, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// handleGetAlbum devuelve el álbum con sus pistas en orden.
+func (s *Server) handleGetAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	album, err := s.libraryService.GetAlbum(currentProfile(r), id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, album)
+}
+
+func (s *Server) handleListShows(w http.ResponseWriter, r *http.Request) {
+	shows, err := s.libraryService.GetShows()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, shows)
+}
+
+// handleGetShow devuelve el programa con sus episodios, del más reciente al
+// más antiguo.
+func (s *Server) handleGetShow(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	show, err := s.libraryService.GetShow(currentProfile(r), id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, show)
+}
@@ -0,0 +1,89 @@
+// internal/api/parental.go
+package api
+
+import (
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// parentalPINHeader lleva el PIN parental en las peticiones que lo requieren
+// (cambiar el control parental o reproducir algo por encima del límite).
+const parentalPINHeader = "X-Parental-PIN"
+
+type parentalPINRequest struct {
+	Password string `json:"password"`
+	PIN      string `json:"pin"`
+}
+
+type parentalControlsRequest struct {
+	MaxLevel       *int                    `json:"max_level"`
+	BlockedGenres  []string                `json:"blocked_genres"`
+	BlockedContent []models.BlockedContent `json:"blocked_content"`
+}
+
+// parentalPIN devuelve el PIN parental enviado en la petición, si lo hay.
+func parentalPIN(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(parentalPINHeader))
+}
+
+// handleSetParentalPIN crea o cambia el PIN parental de la cuenta.
+func (s *Server) handleSetParentalPIN(w http.ResponseWriter, r *http.Request) {
+	var req parentalPINRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.parentalService.SetPIN(currentUser(r).ID, req.Password, req.PIN, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleGetParentalControls(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	controls, err := s.parentalService.GetControls(currentUser(r).ID, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, controls)
+}
+
+// handleUpdateParentalControls reemplaza el control parental de un perfil;
+// requiere el PIN parental en la cabecera X-Parental-PIN.
+func (s *Server) handleUpdateParentalControls(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req parentalControlsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	controls := &models.ParentalControls{
+		ProfileID:      id,
+		MaxLevel:       req.MaxLevel,
+		BlockedGenres:  req.BlockedGenres,
+		BlockedContent: req.BlockedContent,
+	}
+	if err := s.parentalService.UpdateControls(currentUser(r).ID, parentalPIN(r), controls, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	if controls.BlockedContent == nil {
+		controls.BlockedContent = []models.BlockedContent{}
+	}
+	writeJSON(w, http.StatusOK, controls)
+}
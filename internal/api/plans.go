@@ -0,0 +1,87 @@
+// internal/api/plans.go
+package api
+
+import (
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"time"
+)
+
+type subscribeRequest struct {
+	CardHolder  string `json:"card_holder"`
+	CardNumber  string `json:"card_number"`
+	ExpiryMonth int    `json:"expiry_month"`
+	ExpiryYear  int    `json:"expiry_year"`
+	CVV         int    `json:"cvv"`
+}
+
+type subscribeResponse struct {
+	User   *models.User       `json:"user"`
+	Change *models.PlanChange `json:"change"`
+}
+
+func (s *Server) handleListPlans(w http.ResponseWriter, r *http.Request) {
+	plans, err := s.subscriptionService.GetAvailablePlans()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, plans)
+}
+
+// handlePreviewPlanChange muestra cuánto costaría pasar al plan y desde
+// cuándo rige, sin aplicar nada.
+func (s *Server) handlePreviewPlanChange(w http.ResponseWriter, r *http.Request) {
+	planID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	change, err := s.subscriptionService.PreviewPlanChange(currentUser(r).ID, planID, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, change)
+}
+
+// handleSubscribe cambia de plan. Las altas y los planes superiores se cobran
+// con la tarjeta del cuerpo; los planes inferiores se programan para el final
+// del período y no llevan cuerpo.
+func (s *Server) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	planID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	userID := currentUser(r).ID
+	change, err := s.subscriptionService.PreviewPlanChange(userID, planID, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if change.AmountCents > 0 {
+		var req subscribeRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := s.subscriptionService.ProcessPayment(userID, planID, req.CardHolder, req.CardNumber, req.ExpiryMonth, req.ExpiryYear, req.CVV); err != nil {
+			writeError(w, err)
+			return
+		}
+	} else if change, err = s.subscriptionService.ChangePlan(userID, planID, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := s.userService.GetByID(userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subscribeResponse{User: user, Change: change})
+}
@@ -0,0 +1,154 @@
+// internal/api/playback.go
+package api
+
+import (
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"time"
+)
+
+// historyRequest registra un contenido o, para las series, un episodio.
+type historyRequest struct {
+	ContentID   int    `json:"content_id,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	EpisodeID   int    `json:"episode_id,omitempty"`
+}
+
+// progressRequest es un latido de progreso: hasta dónde llegó la reproducción.
+type progressRequest struct {
+	PositionSeconds int `json:"position_seconds"`
+}
+
+func (s *Server) handleListFavorites(w http.ResponseWriter, r *http.Request) {
+	favorites, err := s.playbackService.GetFavorites(currentProfile(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, favorites)
+}
+
+func (s *Server) handleAddFavorite(w http.ResponseWriter, r *http.Request) {
+	var req models.ContentRef
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.playbackService.AddFavorite(currentProfile(r), req, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, req)
+}
+
+func (s *Server) handleRemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.playbackService.RemoveFavorite(currentProfile(r).ID, models.ContentRef{ContentID: id, ContentType: r.PathValue("type")}); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+func (s *Server) handleListHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := s.playbackService.GetHistory(currentProfile(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (s *Server) handleAddHistory(w http.ResponseWriter, r *http.Request) {
+	var req historyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var entry *models.PlaybackHistory
+	var err error
+	profile := currentProfile(r)
+	if req.EpisodeID != 0 {
+		entry, err = s.playbackService.AddEpisodeToHistory(profile, req.EpisodeID, parentalPIN(r), time.Now())
+	} else {
+		entry, err = s.playbackService.AddToHistory(profile, models.ContentRef{ContentID: req.ContentID, ContentType: req.ContentType}, parentalPIN(r), time.Now())
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// handleReportProgress recibe un latido de progreso de una reproducción del
+// historial y devuelve el punto de reanudación del título.
+func (s *Server) handleReportProgress(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req progressRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	point, err := s.playbackService.ReportProgress(currentProfile(r).ID, id, req.PositionSeconds, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, point)
+}
+
+// handleContinueWatching devuelve los títulos que el perfil dejó a medias.
+func (s *Server) handleContinueWatching(w http.ResponseWriter, r *http.Request) {
+	points, err := s.playbackService.GetContinueWatching(currentProfile(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, points)
+}
+
+// handleGetResumePoint devuelve dónde dejó el perfil un título.
+func (s *Server) handleGetResumePoint(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	point, err := s.playbackService.GetResumePoint(currentProfile(r).ID, models.ContentRef{ContentID: id, ContentType: r.PathValue("type")})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, point)
+}
+
+// handleSelectRendition devuelve la versión a reproducir de un contenido
+// audiovisual según el plan del usuario (?quality= para pedir una calidad).
+func (s *Server) handleSelectRendition(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rendition, err := s.playbackService.SelectRendition(currentProfile(r), id, r.URL.Query().Get("quality"), parentalPIN(r), time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rendition)
+}
@@ -0,0 +1,71 @@
+// internal/api/profiles.go
+package api
+
+import (
+	"net/http"
+)
+
+type profileRequest struct {
+	Name      string `json:"name"`
+	AgeRating string `json:"age_rating"`
+}
+
+func (s *Server) handleListProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := s.profileService.GetProfiles(currentUser(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, profiles)
+}
+
+func (s *Server) handleCreateProfile(w http.ResponseWriter, r *http.Request) {
+	var req profileRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	profile, err := s.profileService.CreateProfile(currentUser(r).ID, req.Name, req.AgeRating)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, profile)
+}
+
+// handleCurrentProfile devuelve el perfil con el que se usa la sesión.
+func (s *Server) handleCurrentProfile(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, currentProfile(r))
+}
+
+// handleSelectProfile elige el perfil de la sesión actual; las demás
+// sesiones de la cuenta conservan el suyo.
+func (s *Server) handleSelectProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	profile, err := s.profileService.SelectProfile(currentUser(r).ID, currentSession(r).ID, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func (s *Server) handleDeleteProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.profileService.DeleteProfile(currentUser(r).ID, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
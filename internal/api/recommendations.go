@@ -0,0 +1,23 @@
+// internal/api/recommendations.go
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// handleRecommendations recomienda contenido al perfil actual (?limit=10).
+func (s *Server) handleRecommendations(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	recommendations, err := s.recommendationService.GetRecommendations(currentProfile(r), limit, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, recommendations)
+}
@@ -0,0 +1,117 @@
+// internal/api/respond.go
+package api
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/payments"
+	"SDGEStreaming/internal/services"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// errorBody es el cuerpo JSON de todas las respuestas de error.
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// statusByCode traduce los códigos de AppError a estados HTTP.
+var statusByCode = map[string]int{
+	"NOT_FOUND":      http.StatusNotFound,
+	"INVALID_INPUT":  http.StatusBadRequest,
+	"UNAUTHORIZED":   http.StatusUnauthorized,
+	"FORBIDDEN":      http.StatusForbidden,
+	"CONFLICT":       http.StatusConflict,
+	"INTERNAL_ERROR": http.StatusInternalServerError,
+	"DATABASE_ERROR": http.StatusInternalServerError,
+
+	payments.CodeDeclined:     http.StatusPaymentRequired,
+	payments.CodeUnavailable:  http.StatusServiceUnavailable,
+	payments.CodeTimeout:      http.StatusGatewayTimeout,
+	services.CodeInvalidToken: http.StatusUnauthorized,
+	services.CodeStreamLimit:  http.StatusTooManyRequests,
+	services.CodePINRequired:  http.StatusForbidden,
+	services.CodePINLocked:    http.StatusTooManyRequests,
+	services.CodeNotLicensed:  http.StatusUnavailableForLegalReasons,
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("api: error al codificar respuesta: %v", err)
+	}
+}
+
+// writeError responde con el código y mensaje del AppError. Cualquier otro
+// error se registra en el log y se expone como INTERNAL_ERROR para no filtrar
+// detalles de la base de datos al cliente.
+func writeError(w http.ResponseWriter, err error) {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		appErr = apperrors.ErrInternal(err)
+	}
+
+	status, ok := statusByCode[appErr.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	if status == http.StatusInternalServerError {
+		log.Printf("api: %v", err)
+	}
+	if status == http.StatusUnauthorized {
+		// La API usa tokens Bearer (RFC 6750); un token vencido o revocado se
+		// distingue de la falta de credenciales para que el cliente sepa que
+		// debe renovarlo.
+		challenge := `Bearer realm="sdge"`
+		if appErr.Code == services.CodeInvalidToken {
+			challenge += `, error="invalid_token"`
+		}
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+
+	writeJSON(w, status, errorBody{Error: errorDetail{Code: appErr.Code, Message: appErr.Message}})
+}
+
+// decodeJSON lee el cuerpo de la petición en v, rechazando campos desconocidos.
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return apperrors.New("INVALID_INPUT", "cuerpo JSON inválido")
+	}
+	return nil
+}
+
+// pathID extrae un identificador numérico de la ruta.
+func pathID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, apperrors.ErrInvalidInput(name)
+	}
+	return id, nil
+}
+
+// queryInt lee un parámetro numérico opcional de la consulta; si falta
+// devuelve 0.
+func queryInt(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, apperrors.ErrInvalidInput(name)
+	}
+	return n, nil
+}
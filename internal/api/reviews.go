@@ -0,0 +1,165 @@
+// internal/api/reviews.go
+package api
+
+import (
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"time"
+)
+
+type reviewRequest struct {
+	Body    string `json:"body"`
+	Spoiler bool   `json:"spoiler"`
+}
+
+type reportRequest struct {
+	Reason string `json:"reason"`
+}
+
+type moderationRequest struct {
+	Action string `json:"action"`
+}
+
+// handleListReviews devuelve las reseñas aprobadas del contenido.
+func (s *Server) handleListReviews(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		reviews, err := s.reviewService.GetReviews(currentProfile(r), models.ContentRef{ContentID: id, ContentType: contentType}, time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, reviews)
+	}
+}
+
+// handleGetMyReview devuelve la reseña del perfil actual sobre el contenido,
+// con su estado de moderación.
+func (s *Server) handleGetMyReview(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		review, err := s.reviewService.GetMyReview(currentProfile(r).ID, models.ContentRef{ContentID: id, ContentType: contentType})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, review)
+	}
+}
+
+func (s *Server) handleWriteReview(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var req reviewRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		review, err := s.reviewService.WriteReview(currentProfile(r), models.ContentRef{ContentID: id, ContentType: contentType}, req.Body, req.Spoiler, time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, review)
+	}
+}
+
+func (s *Server) handleDeleteMyReview(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.reviewService.DeleteMyReview(currentProfile(r).ID, models.ContentRef{ContentID: id, ContentType: contentType}); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// handleVoteHelpful marca la reseña como útil (POST) o quita la marca
+// (DELETE).
+func (s *Server) handleVoteHelpful(helpful bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r, "id")
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.reviewService.VoteHelpful(id, currentProfile(r).ID, helpful, time.Now()); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusNoContent, nil)
+	}
+}
+
+func (s *Server) handleReportReview(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req reportRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.reviewService.ReportReview(id, currentProfile(r).ID, req.Reason, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// handleModerationQueue devuelve las reseñas que esperan moderación.
+func (s *Server) handleModerationQueue(w http.ResponseWriter, r *http.Request) {
+	queue, err := s.reviewService.GetModerationQueue(currentUser(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, queue)
+}
+
+func (s *Server) handleModerateReview(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req moderationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.reviewService.ModerateReview(currentUser(r), id, req.Action, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,23 @@
+// internal/api/search.go
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// handleSearch busca en todos los catálogos (?q=texto&limit=20).
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	results, err := s.searchService.Search(currentProfile(r), r.URL.Query().Get("q"), limit, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
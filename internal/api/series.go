@@ -0,0 +1,92 @@
+// internal/api/series.go
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+type seasonRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+type episodeRequest struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	Duration int    `json:"duration"`
+	Synopsis string `json:"synopsis"`
+}
+
+func (s *Server) handleGetSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	series, err := s.contentService.GetSeries(currentProfile(r), id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, series)
+}
+
+// handleNextEpisode devuelve el episodio que le toca ver al perfil.
+func (s *Server) handleNextEpisode(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	episode, err := s.playbackService.NextEpisode(currentProfile(r).ID, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, episode)
+}
+
+func (s *Server) handleAddSeason(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req seasonRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	season, err := s.contentService.AddSeason(currentUser(r), id, req.Number, req.Title)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, season)
+}
+
+func (s *Server) handleAddEpisode(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var req episodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	episode, err := s.contentService.AddEpisode(currentUser(r), id, req.Number, req.Title, req.Duration, req.Synopsis)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, episode)
+}
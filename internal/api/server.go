@@ -0,0 +1,185 @@
+// internal/api/server.go
+// Expone los servicios de SDGEStreaming como una API REST JSON versionada.
+package api
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/services"
+	"net/http"
+)
+
+// Server agrupa los servicios que atiende la API HTTP.
+type Server struct {
+	userService           *services.UserService
+	contentService        *services.ContentService
+	subscriptionService   *services.SubscriptionService
+	playbackService       *services.PlaybackService
+	billingService        *services.BillingService
+	streamService         *services.StreamService
+	authService           *services.AuthService
+	profileService        *services.ProfileService
+	parentalService       *services.ParentalService
+	libraryService        *services.LibraryService
+	searchService         *services.SearchService
+	recommendationService *services.RecommendationService
+	trendingService       *services.TrendingService
+	reviewService         *services.ReviewService
+}
+
+// NewServer crea el servidor HTTP a partir de los servicios ya inicializados.
+func NewServer(userService *services.UserService, contentService *services.ContentService, subscriptionService *services.SubscriptionService, playbackService *services.PlaybackService, billingService *services.BillingService, streamService *services.StreamService, authService *services.AuthService, profileService *services.ProfileService, parentalService *services.ParentalService, libraryService *services.LibraryService, searchService *services.SearchService, recommendationService *services.RecommendationService, trendingService *services.TrendingService, reviewService *services.ReviewService) *Server {
+	return &Server{
+		userService:           userService,
+		contentService:        contentService,
+		subscriptionService:   subscriptionService,
+		playbackService:       playbackService,
+		billingService:        billingService,
+		streamService:         streamService,
+		authService:           authService,
+		profileService:        profileService,
+		parentalService:       parentalService,
+		libraryService:        libraryService,
+		searchService:         searchService,
+		recommendationService: recommendationService,
+		trendingService:       trendingService,
+		reviewService:         reviewService,
+	}
+}
+
+// Handler devuelve el enrutador con todas las rutas bajo /api/v1.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	// Sesiones
+	mux.HandleFunc("POST /api/v1/auth/login", s.handleLogin)
+	mux.HandleFunc("POST /api/v1/auth/refresh", s.handleRefresh)
+	mux.HandleFunc("POST /api/v1/auth/logout", s.requireUser(s.handleLogout))
+	mux.HandleFunc("POST /api/v1/auth/logout-all", s.requireUser(s.handleLogoutAll))
+	mux.HandleFunc("GET /api/v1/auth/sessions", s.requireUser(s.handleListSessions))
+	mux.HandleFunc("DELETE /api/v1/auth/sessions/{id}", s.requireUser(s.handleRevokeSession))
+
+	// Perfiles del hogar
+	mux.HandleFunc("GET /api/v1/profiles", s.requireUser(s.handleListProfiles))
+	mux.HandleFunc("POST /api/v1/profiles", s.requireUser(s.handleCreateProfile))
+	mux.HandleFunc("GET /api/v1/profiles/current", s.requireUser(s.handleCurrentProfile))
+	mux.HandleFunc("POST /api/v1/profiles/{id}/select", s.requireUser(s.handleSelectProfile))
+	mux.HandleFunc("DELETE /api/v1/profiles/{id}", s.requireUser(s.handleDeleteProfile))
+
+	// Control parental
+	mux.HandleFunc("PUT /api/v1/parental/pin", s.requireUser(s.handleSetParentalPIN))
+	mux.HandleFunc("GET /api/v1/profiles/{id}/parental-controls", s.requireUser(s.handleGetParentalControls))
+	mux.HandleFunc("PUT /api/v1/profiles/{id}/parental-controls", s.requireUser(s.handleUpdateParentalControls))
+
+	// Usuarios
+	mux.HandleFunc("POST /api/v1/users", s.handleRegister)
+	mux.HandleFunc("GET /api/v1/users", s.requireUser(s.handleListUsers))
+	mux.HandleFunc("GET /api/v1/users/me", s.requireUser(s.handleGetMe))
+	mux.HandleFunc("GET /api/v1/users/{id}", s.requireUser(s.handleGetUser))
+	mux.HandleFunc("PUT /api/v1/users/{id}/role", s.requireUser(s.handleSetRole))
+	mux.HandleFunc("PUT /api/v1/users/{id}/country", s.requireUser(s.handleSetCountry))
+	mux.HandleFunc("POST /api/v1/users/{id}/logout-all", s.requireUser(s.handleRevokeUserSessions))
+
+	// Sistemas de clasificación por edad
+	mux.HandleFunc("GET /api/v1/rating-systems", s.requireUser(s.handleListRatingSystems))
+	mux.HandleFunc("POST /api/v1/rating-systems", s.requireUser(s.handleCreateRatingSystem))
+
+	// Contenido audiovisual
+	mux.HandleFunc("GET /api/v1/content/audiovisual", s.requireUser(s.handleListAudiovisual))
+	mux.HandleFunc("POST /api/v1/content/audiovisual", s.requireUser(s.handleCreateAudiovisual))
+	mux.HandleFunc("GET /api/v1/content/audiovisual/{id}", s.requireUser(s.handleGetAudiovisual))
+	mux.HandleFunc("GET /api/v1/content/audiovisual/{id}/ratings", s.requireUser(s.handleRatingSummary("audiovisual")))
+	mux.HandleFunc("POST /api/v1/content/audiovisual/{id}/ratings", s.requireUser(s.handleRateContent("audiovisual")))
+	mux.HandleFunc("GET /api/v1/content/audiovisual/{id}/reviews", s.requireUser(s.handleListReviews("audiovisual")))
+	mux.HandleFunc("GET /api/v1/content/audiovisual/{id}/review", s.requireUser(s.handleGetMyReview("audiovisual")))
+	mux.HandleFunc("PUT /api/v1/content/audiovisual/{id}/review", s.requireUser(s.handleWriteReview("audiovisual")))
+	mux.HandleFunc("DELETE /api/v1/content/audiovisual/{id}/review", s.requireUser(s.handleDeleteMyReview("audiovisual")))
+	mux.HandleFunc("GET /api/v1/content/audiovisual/{id}/playback", s.requireUser(s.handleSelectRendition))
+	mux.HandleFunc("GET /api/v1/content/audiovisual/{id}/license", s.requireUser(s.handleGetLicense("audiovisual")))
+	mux.HandleFunc("PUT /api/v1/content/audiovisual/{id}/license", s.requireUser(s.handleSetLicense("audiovisual")))
+
+	// Buscador de todo el catálogo
+	mux.HandleFunc("GET /api/v1/search", s.requireUser(s.handleSearch))
+
+	// Recomendaciones del perfil
+	mux.HandleFunc("GET /api/v1/recommendations", s.requireUser(s.handleRecommendations))
+
+	// Tendencias
+	mux.HandleFunc("GET /api/v1/trending", s.requireUser(s.handleTrending))
+	mux.HandleFunc("POST /api/v1/trending/refresh", s.requireUser(s.handleRefreshTrending))
+
+	// Series: temporadas y episodios
+	mux.HandleFunc("GET /api/v1/series/{id}", s.requireUser(s.handleGetSeries))
+	mux.HandleFunc("GET /api/v1/series/{id}/next-episode", s.requireUser(s.handleNextEpisode))
+	mux.HandleFunc("POST /api/v1/series/{id}/seasons", s.requireUser(s.handleAddSeason))
+	mux.HandleFunc("POST /api/v1/seasons/{id}/episodes", s.requireUser(s.handleAddEpisode))
+
+	// Contenido de audio
+	mux.HandleFunc("GET /api/v1/content/audio", s.requireUser(s.handleListAudio))
+	mux.HandleFunc("POST /api/v1/content/audio", s.requireUser(s.handleCreateAudio))
+	mux.HandleFunc("GET /api/v1/content/audio/{id}", s.requireUser(s.handleGetAudio))
+	mux.HandleFunc("GET /api/v1/content/audio/{id}/ratings", s.requireUser(s.handleRatingSummary("audio")))
+	mux.HandleFunc("POST /api/v1/content/audio/{id}/ratings", s.requireUser(s.handleRateContent("audio")))
+	mux.HandleFunc("GET /api/v1/content/audio/{id}/reviews", s.requireUser(s.handleListReviews("audio")))
+	mux.HandleFunc("GET /api/v1/content/audio/{id}/review", s.requireUser(s.handleGetMyReview("audio")))
+	mux.HandleFunc("PUT /api/v1/content/audio/{id}/review", s.requireUser(s.handleWriteReview("audio")))
+	mux.HandleFunc("DELETE /api/v1/content/audio/{id}/review", s.requireUser(s.handleDeleteMyReview("audio")))
+	mux.HandleFunc("GET /api/v1/content/audio/{id}/license", s.requireUser(s.handleGetLicense("audio")))
+	mux.HandleFunc("PUT /api/v1/content/audio/{id}/license", s.requireUser(s.handleSetLicense("audio")))
+
+	// Artistas, álbumes y podcasts
+	mux.HandleFunc("GET /api/v1/artists", s.requireUser(s.handleListArtists))
+	mux.HandleFunc("GET /api/v1/artists/following", s.requireUser(s.handleListFollowedArtists))
+	mux.HandleFunc("GET /api/v1/artists/{id}", s.requireUser(s.handleGetArtist))
+	mux.HandleFunc("POST /api/v1/artists/{id}/follow", s.requireUser(s.handleFollowArtist))
+	mux.HandleFunc("DELETE /api/v1/artists/{id}/follow", s.requireUser(s.handleUnfollowArtist))
+	mux.HandleFunc("GET /api/v1/albums/{id}", s.requireUser(s.handleGetAlbum))
+	mux.HandleFunc("GET /api/v1/shows", s.requireUser(s.handleListShows))
+	mux.HandleFunc("GET /api/v1/shows/{id}", s.requireUser(s.handleGetShow))
+
+	// Favoritos e historial
+	mux.HandleFunc("GET /api/v1/favorites", s.requireUser(s.handleListFavorites))
+	mux.HandleFunc("POST /api/v1/favorites", s.requireUser(s.handleAddFavorite))
+	mux.HandleFunc("DELETE /api/v1/favorites/{type}/{id}", s.requireUser(s.handleRemoveFavorite))
+	mux.HandleFunc("GET /api/v1/history", s.requireUser(s.handleListHistory))
+	mux.HandleFunc("POST /api/v1/history", s.requireUser(s.handleAddHistory))
+	mux.HandleFunc("POST /api/v1/history/{id}/progress", s.requireUser(s.handleReportProgress))
+	mux.HandleFunc("GET /api/v1/continue-watching", s.requireUser(s.handleContinueWatching))
+	mux.HandleFunc("GET /api/v1/resume/{type}/{id}", s.requireUser(s.handleGetResumePoint))
+
+	// Calificaciones del perfil
+	mux.HandleFunc("GET /api/v1/ratings", s.requireUser(s.handleListMyRatings))
+	mux.HandleFunc("DELETE /api/v1/ratings/{type}/{id}", s.requireUser(s.handleDeleteMyRating))
+
+	// Reseñas: votos, denuncias y moderación
+	mux.HandleFunc("POST /api/v1/reviews/{id}/helpful", s.requireUser(s.handleVoteHelpful(true)))
+	mux.HandleFunc("DELETE /api/v1/reviews/{id}/helpful", s.requireUser(s.handleVoteHelpful(false)))
+	mux.HandleFunc("POST /api/v1/reviews/{id}/reports", s.requireUser(s.handleReportReview))
+	mux.HandleFunc("GET /api/v1/reviews/moderation", s.requireUser(s.handleModerationQueue))
+	mux.HandleFunc("POST /api/v1/reviews/{id}/moderation", s.requireUser(s.handleModerateReview))
+
+	// Planes
+	mux.HandleFunc("GET /api/v1/plans", s.handleListPlans)
+	mux.HandleFunc("GET /api/v1/plans/{id}/preview", s.requireUser(s.handlePreviewPlanChange))
+	mux.HandleFunc("POST /api/v1/plans/{id}/subscribe", s.requireUser(s.handleSubscribe))
+
+	// Suscripción del usuario
+	mux.HandleFunc("GET /api/v1/subscription", s.requireUser(s.handleGetSubscription))
+	mux.HandleFunc("DELETE /api/v1/subscription", s.requireUser(s.handleCancelSubscription))
+	mux.HandleFunc("GET /api/v1/subscription/history", s.requireUser(s.handleSubscriptionHistory))
+
+	// Sesiones de reproducción (límite de dispositivos)
+	mux.HandleFunc("GET /api/v1/streams", s.requireUser(s.handleListStreams))
+	mux.HandleFunc("POST /api/v1/streams", s.requireUser(s.handleStartStream))
+	mux.HandleFunc("POST /api/v1/streams/{id}/heartbeat", s.requireUser(s.handleStreamHeartbeat))
+	mux.HandleFunc("DELETE /api/v1/streams/{id}", s.requireUser(s.handleStopStream))
+
+	// Facturas
+	mux.HandleFunc("GET /api/v1/invoices", s.requireUser(s.handleListInvoices))
+	mux.HandleFunc("GET /api/v1/invoices/export", s.requireUser(s.handleExportInvoices))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, apperrors.ErrNotFound("recurso"))
+	})
+
+	return mux
+}
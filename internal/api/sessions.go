@@ -0,0 +1,106 @@
+// internal/api/sessions.go
+package api
+
+import (
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"time"
+)
+
+// apiClient identifica en auth_sessions las sesiones abiertas por la API.
+const apiClient = "api"
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type sessionResponse struct {
+	User   *models.User       `json:"user"`
+	Tokens *models.AuthTokens `json:"tokens"`
+	// Profiles acompaña al inicio de sesión para que el cliente muestre el
+	// selector de perfiles.
+	Profiles []models.Profile `json:"profiles,omitempty"`
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, tokens, err := s.authService.Login(req.Email, req.Password, apiClient, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	profiles, err := s.profileService.GetProfiles(user.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessionResponse{User: user, Tokens: tokens, Profiles: profiles})
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, tokens, err := s.authService.Refresh(req.RefreshToken, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessionResponse{User: user, Tokens: tokens})
+}
+
+// handleLogout cierra la sesión con la que se hizo la petición.
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if err := s.authService.Logout(currentUser(r).ID, currentSession(r).ID, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
+
+// handleLogoutAll cierra todas las sesiones del usuario, incluida la actual.
+func (s *Server) handleLogoutAll(w http.ResponseWriter, r *http.Request) {
+	n, err := s.authService.RevokeAll(currentUser(r).ID, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"revoked": n})
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.authService.GetSessions(currentUser(r).ID, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+func (s *Server) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.authService.Logout(currentUser(r).ID, id, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
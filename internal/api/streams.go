@@ -0,0 +1,69 @@
+// internal/api/streams.go
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+type startStreamRequest struct {
+	ContentID   int    `json:"content_id"`
+	ContentType string `json:"content_type"`
+	Device      string `json:"device"`
+}
+
+// handleStartStream abre una sesión de reproducción. El cliente debe enviar
+// señales a /heartbeat mientras reproduce y cerrarla con DELETE al terminar.
+func (s *Server) handleStartStream(w http.ResponseWriter, r *http.Request) {
+	var req startStreamRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	session, err := s.streamService.StartStream(currentProfile(r), req.Device, req.ContentID, req.ContentType, parentalPIN(r), time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, session)
+}
+
+func (s *Server) handleListStreams(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.streamService.GetActiveSessions(currentUser(r).ID, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+func (s *Server) handleStreamHeartbeat(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	session, err := s.streamService.Heartbeat(currentUser(r).ID, id, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// handleStopStream termina una sesión propia o expulsa la de otro dispositivo.
+func (s *Server) handleStopStream(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := s.streamService.StopStream(currentUser(r).ID, id, time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
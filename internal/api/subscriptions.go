@@ -0,0 +1,38 @@
+// internal/api/subscriptions.go
+package api
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"net/http"
+)
+
+func (s *Server) handleGetSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := s.subscriptionService.GetActiveSubscription(currentUser(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if sub == nil {
+		writeError(w, apperrors.New("NOT_FOUND", "no tiene una suscripción activa"))
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (s *Server) handleCancelSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, err := s.subscriptionService.CancelSubscription(currentUser(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (s *Server) handleSubscriptionHistory(w http.ResponseWriter, r *http.Request) {
+	list, err := s.subscriptionService.GetSubscriptionHistory(currentUser(r).ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
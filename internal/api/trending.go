@@ -0,0 +1,38 @@
+// internal/api/trending.go
+package api
+
+import (
+	"SDGEStreaming/internal/models"
+	"net/http"
+	"time"
+)
+
+// handleTrending devuelve el contenido en tendencia (?window=24h|7d|30d,
+// 7d por defecto; ?limit=10).
+func (s *Server) handleTrending(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = models.TrendingWeek
+	}
+
+	trending, err := s.trendingService.GetTrending(currentProfile(r), window, limit, time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, trending)
+}
+
+// handleRefreshTrending rehace las tendencias en el momento.
+func (s *Server) handleRefreshTrending(w http.ResponseWriter, r *http.Request) {
+	if err := s.trendingService.RefreshNow(currentUser(r), time.Now()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusNoContent, nil)
+}
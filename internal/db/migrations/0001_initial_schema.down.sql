@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS playback_history;
+DROP TABLE IF EXISTS user_ratings;
+DROP TABLE IF EXISTS audio_content;
+DROP TABLE IF EXISTS audiovisual_content;
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS plans;
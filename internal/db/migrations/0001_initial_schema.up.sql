@@ -1,9 +1,11 @@
+-- Esquema inicial. Usa IF NOT EXISTS para poder adoptar bases de datos
+-- creadas antes de existir el sistema de migraciones.
 
 CREATE TABLE IF NOT EXISTS plans (
     id INTEGER PRIMARY KEY,
     name TEXT NOT NULL,
     price REAL NOT NULL,
-    max_quality TEXT DEFAULT 'HD',          
+    max_quality TEXT DEFAULT 'HD',
     max_devices INTEGER DEFAULT 1
 );
 
@@ -13,7 +15,7 @@ CREATE TABLE IF NOT EXISTS users (
     email TEXT UNIQUE NOT NULL,
     password_hash TEXT NOT NULL,
     age INTEGER NOT NULL,
-    plan_id INTEGER NOT NULL,
+    plan_id INTEGER NOT NULL DEFAULT 1,
     age_rating TEXT NOT NULL,
     is_admin BOOLEAN NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
@@ -85,7 +87,6 @@ CREATE TABLE IF NOT EXISTS playback_history (
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
-
 CREATE TABLE IF NOT EXISTS favorites (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL,
@@ -0,0 +1,2 @@
+-- La reconstrucción no elimina columnas: no hay nada que revertir.
+SELECT 1;
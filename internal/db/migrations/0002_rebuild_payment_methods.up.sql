@@ -0,0 +1,25 @@
+-- Algunas copias de sdgestreaming.db se crearon con una versión de
+-- payment_methods sin card_number, expiration_date ni cvv. Se reconstruye la
+-- tabla copiando solo las columnas comunes a ambas versiones.
+
+CREATE TABLE payment_methods_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    card_holder_name TEXT NOT NULL,
+    card_number TEXT NOT NULL DEFAULT '',
+    expiration_date TEXT NOT NULL DEFAULT '',
+    cvv TEXT NOT NULL DEFAULT '',
+    card_number_last4 TEXT NOT NULL,
+    expiry_month INTEGER NOT NULL,
+    expiry_year INTEGER NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO payment_methods_new (id, user_id, card_holder_name, card_number_last4, expiry_month, expiry_year, is_default, created_at)
+SELECT id, user_id, card_holder_name, card_number_last4, expiry_month, expiry_year, is_default, created_at
+FROM payment_methods;
+
+DROP TABLE payment_methods;
+ALTER TABLE payment_methods_new RENAME TO payment_methods;
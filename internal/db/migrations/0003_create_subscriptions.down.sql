@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_subscriptions_user;
+DROP TABLE IF EXISTS subscriptions;
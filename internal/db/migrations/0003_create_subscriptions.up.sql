@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    plan_id INTEGER NOT NULL,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT 1,
+    auto_renew BOOLEAN NOT NULL DEFAULT 1,  -- 0 tras cancelar: vence al final del período
+    canceled_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (plan_id) REFERENCES plans(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user ON subscriptions(user_id, is_active);
+
+-- Los usuarios que ya estaban en un plan de pago reciben un período de un
+-- mes a partir de la migración.
+INSERT INTO subscriptions (user_id, plan_id, start_date, end_date)
+SELECT id, plan_id, datetime('now'), datetime('now', '+1 month')
+FROM users
+WHERE plan_id <> 1;
@@ -0,0 +1,41 @@
+-- Los métodos de pago dejan de guardar el número completo y el CVV: solo el
+-- token emitido por la pasarela, la marca, los últimos 4 dígitos y el
+-- vencimiento. Las tarjetas guardadas antes de este cambio no tienen token
+-- real; se marcan como 'legacy_<id>' y el usuario deberá ingresarlas de nuevo
+-- para futuros cobros.
+
+CREATE TABLE payment_methods_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    card_holder_name TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    brand TEXT NOT NULL,
+    card_number_last4 TEXT NOT NULL,
+    expiry_month INTEGER NOT NULL,
+    expiry_year INTEGER NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO payment_methods_new (id, user_id, card_holder_name, token, brand, card_number_last4, expiry_month, expiry_year, is_default, created_at)
+SELECT
+    id,
+    user_id,
+    card_holder_name,
+    'legacy_' || id,
+    CASE
+        WHEN card_number LIKE '4%' THEN 'Visa'
+        WHEN substr(card_number, 1, 2) BETWEEN '51' AND '55' THEN 'Mastercard'
+        WHEN substr(card_number, 1, 2) IN ('34', '37') THEN 'American Express'
+        ELSE 'Desconocida'
+    END,
+    card_number_last4,
+    expiry_month,
+    expiry_year,
+    is_default,
+    created_at
+FROM payment_methods;
+
+DROP TABLE payment_methods;
+ALTER TABLE payment_methods_new RENAME TO payment_methods;
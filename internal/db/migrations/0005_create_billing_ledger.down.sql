@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS payment_transactions_immutable_delete;
+DROP TRIGGER IF EXISTS payment_transactions_immutable_update;
+DROP TRIGGER IF EXISTS invoices_immutable_delete;
+DROP TRIGGER IF EXISTS invoices_immutable_update;
+DROP INDEX IF EXISTS idx_payment_transactions_invoice;
+DROP INDEX IF EXISTS idx_invoices_user;
+DROP TABLE IF EXISTS payment_transactions;
+DROP TABLE IF EXISTS invoices;
@@ -0,0 +1,58 @@
+-- Facturas y libro de transacciones de pago. Ambas tablas son de solo
+-- inserción: las correcciones se registran como transacciones nuevas
+-- (p. ej. una devolución), nunca modificando o borrando filas. Por eso no
+-- tienen ON DELETE CASCADE hacia users.
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    plan_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    amount_cents INTEGER NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'USD',
+    period_start DATETIME NOT NULL,
+    period_end DATETIME NOT NULL,
+    issued_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (plan_id) REFERENCES plans(id)
+);
+
+CREATE TABLE IF NOT EXISTS payment_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    invoice_id INTEGER NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('charge', 'refund', 'proration')),
+    amount_cents INTEGER NOT NULL,          -- negativo para devoluciones y créditos
+    gateway_ref TEXT NOT NULL DEFAULT '',   -- id del cobro/devolución en la pasarela
+    description TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_user ON invoices(user_id, issued_at);
+CREATE INDEX IF NOT EXISTS idx_payment_transactions_invoice ON payment_transactions(invoice_id);
+
+CREATE TRIGGER IF NOT EXISTS invoices_immutable_update
+BEFORE UPDATE ON invoices
+BEGIN
+    SELECT RAISE(ABORT, 'las facturas no se pueden modificar');
+END;
+
+CREATE TRIGGER IF NOT EXISTS invoices_immutable_delete
+BEFORE DELETE ON invoices
+BEGIN
+    SELECT RAISE(ABORT, 'las facturas no se pueden eliminar');
+END;
+
+CREATE TRIGGER IF NOT EXISTS payment_transactions_immutable_update
+BEFORE UPDATE ON payment_transactions
+BEGIN
+    SELECT RAISE(ABORT, 'el libro de pagos no se puede modificar');
+END;
+
+CREATE TRIGGER IF NOT EXISTS payment_transactions_immutable_delete
+BEFORE DELETE ON payment_transactions
+BEGIN
+    SELECT RAISE(ABORT, 'el libro de pagos no se puede modificar');
+END;
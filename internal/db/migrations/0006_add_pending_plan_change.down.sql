@@ -0,0 +1 @@
+ALTER TABLE subscriptions DROP COLUMN pending_plan_id;
@@ -0,0 +1,3 @@
+-- Plan al que pasará la suscripción al terminar el período en curso
+-- (cambios a un plan inferior). NULL si no hay cambio programado.
+ALTER TABLE subscriptions ADD COLUMN pending_plan_id INTEGER;
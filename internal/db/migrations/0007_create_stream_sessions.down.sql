@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stream_sessions_user;
+DROP TABLE IF EXISTS stream_sessions;
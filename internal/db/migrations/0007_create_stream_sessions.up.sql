@@ -0,0 +1,16 @@
+-- Reproducciones en curso por usuario y dispositivo, para aplicar el límite
+-- plans.max_devices. Una sesión está activa mientras ended_at sea NULL y el
+-- cliente siga enviando señales (last_seen_at reciente).
+CREATE TABLE IF NOT EXISTS stream_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    device TEXT NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL CHECK (content_type IN ('audio', 'audiovisual')),
+    started_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    ended_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stream_sessions_user ON stream_sessions(user_id, ended_at);
@@ -0,0 +1,20 @@
+-- Versiones (calidades) en las que está disponible cada contenido
+-- audiovisual. La reproducción elige la mejor que permita el plan.
+CREATE TABLE IF NOT EXISTS audiovisual_renditions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_id INTEGER NOT NULL,
+    quality TEXT NOT NULL CHECK (quality IN ('SD', 'HD', '4K')),
+    manifest_path TEXT NOT NULL,
+    FOREIGN KEY (content_id) REFERENCES audiovisual_content(id) ON DELETE CASCADE,
+    UNIQUE (content_id, quality)
+);
+
+-- El catálogo existente queda en SD y HD; las películas también en 4K.
+INSERT INTO audiovisual_renditions (content_id, quality, manifest_path)
+SELECT id, 'SD', 'av/' || id || '/sd.m3u8' FROM audiovisual_content;
+
+INSERT INTO audiovisual_renditions (content_id, quality, manifest_path)
+SELECT id, 'HD', 'av/' || id || '/hd.m3u8' FROM audiovisual_content;
+
+INSERT INTO audiovisual_renditions (content_id, quality, manifest_path)
+SELECT id, '4K', 'av/' || id || '/4k.m3u8' FROM audiovisual_content WHERE type = 'movie';
@@ -0,0 +1,17 @@
+-- Sesiones de inicio de sesión. Solo se guardan hashes SHA-256 de los tokens
+-- de acceso y de renovación; los tokens en claro los tiene el cliente.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    client TEXT NOT NULL,                   -- cli, api, ...
+    access_token_hash TEXT NOT NULL UNIQUE,
+    refresh_token_hash TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL,
+    last_used_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,           -- vencimiento del token de acceso
+    refresh_expires_at DATETIME NOT NULL,   -- vencimiento de la sesión
+    revoked_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user ON auth_sessions(user_id, revoked_at);
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT 0;
+
+UPDATE users SET is_admin = 1 WHERE role = 'super_admin';
+
+ALTER TABLE users DROP COLUMN role;
@@ -0,0 +1,8 @@
+-- Roles en lugar del indicador is_admin. Los administradores existentes
+-- pasan a super_admin; el resto a viewer.
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'viewer'
+    CHECK (role IN ('viewer', 'content_editor', 'billing_admin', 'support', 'super_admin'));
+
+UPDATE users SET role = 'super_admin' WHERE is_admin = 1;
+
+ALTER TABLE users DROP COLUMN is_admin;
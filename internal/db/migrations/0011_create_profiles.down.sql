@@ -0,0 +1,66 @@
+-- Vuelve a una sola identidad por cuenta. Si varios perfiles de la misma
+-- cuenta tenían el mismo contenido en favoritos o calificado, se conserva el
+-- primero.
+
+ALTER TABLE auth_sessions DROP COLUMN profile_id;
+
+CREATE TABLE user_ratings_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,         -- audiovisual | audio
+    rating REAL NOT NULL,
+    rated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, content_id, content_type)
+);
+
+INSERT OR IGNORE INTO user_ratings_old (id, user_id, content_id, content_type, rating, rated_at)
+SELECT r.id, p.user_id, r.content_id, r.content_type, r.rating, r.rated_at
+FROM user_ratings r
+JOIN profiles p ON p.id = r.profile_id
+ORDER BY r.id;
+
+DROP TABLE user_ratings;
+ALTER TABLE user_ratings_old RENAME TO user_ratings;
+
+CREATE TABLE playback_history_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,            -- audiovisual | audio
+    progress_seconds INTEGER DEFAULT 0,
+    watched_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO playback_history_old (id, user_id, content_id, content_type, progress_seconds, watched_at)
+SELECT h.id, p.user_id, h.content_id, h.content_type, h.progress_seconds, h.watched_at
+FROM playback_history h
+JOIN profiles p ON p.id = h.profile_id;
+
+DROP TABLE playback_history;
+ALTER TABLE playback_history_old RENAME TO playback_history;
+
+CREATE TABLE favorites_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,
+    added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, content_id, content_type)
+);
+
+INSERT OR IGNORE INTO favorites_old (id, user_id, content_id, content_type, added_at)
+SELECT f.id, p.user_id, f.content_id, f.content_type, f.added_at
+FROM favorites f
+JOIN profiles p ON p.id = f.profile_id
+ORDER BY f.id;
+
+DROP TABLE favorites;
+ALTER TABLE favorites_old RENAME TO favorites;
+
+DROP TABLE profiles;
+
+ALTER TABLE plans DROP COLUMN max_profiles;
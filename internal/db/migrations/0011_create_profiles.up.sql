@@ -0,0 +1,83 @@
+-- Perfiles del hogar: una cuenta (users) tiene uno o más perfiles, cada uno
+-- con su nombre, clasificación por edad, favoritos, historial y
+-- calificaciones. Cada plan limita cuántos perfiles se pueden crear.
+
+ALTER TABLE plans ADD COLUMN max_profiles INTEGER NOT NULL DEFAULT 1;
+UPDATE plans SET max_profiles = 3 WHERE id = 2;
+UPDATE plans SET max_profiles = 5 WHERE id = 3;
+
+CREATE TABLE profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    age_rating TEXT NOT NULL CHECK (age_rating IN ('Niño', 'Adolescente', 'Adulto')),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (user_id, name)
+);
+
+CREATE INDEX idx_profiles_user ON profiles(user_id);
+
+-- Cada cuenta existente recibe un perfil principal con su nombre y su
+-- clasificación, al que pasan sus favoritos, historial y calificaciones.
+INSERT INTO profiles (user_id, name, age_rating, created_at)
+SELECT id, name, age_rating, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM users;
+
+CREATE TABLE favorites_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,
+    added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    UNIQUE(profile_id, content_id, content_type)
+);
+
+INSERT INTO favorites_new (id, profile_id, content_id, content_type, added_at)
+SELECT f.id, p.id, f.content_id, f.content_type, f.added_at
+FROM favorites f
+JOIN profiles p ON p.user_id = f.user_id;
+
+DROP TABLE favorites;
+ALTER TABLE favorites_new RENAME TO favorites;
+
+CREATE TABLE playback_history_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,            -- audiovisual | audio
+    progress_seconds INTEGER DEFAULT 0,
+    watched_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+
+INSERT INTO playback_history_new (id, profile_id, content_id, content_type, progress_seconds, watched_at)
+SELECT h.id, p.id, h.content_id, h.content_type, h.progress_seconds, h.watched_at
+FROM playback_history h
+JOIN profiles p ON p.user_id = h.user_id;
+
+DROP TABLE playback_history;
+ALTER TABLE playback_history_new RENAME TO playback_history;
+
+CREATE TABLE user_ratings_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,         -- audiovisual | audio
+    rating REAL NOT NULL,
+    rated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    UNIQUE(profile_id, content_id, content_type)
+);
+
+INSERT INTO user_ratings_new (id, profile_id, content_id, content_type, rating, rated_at)
+SELECT r.id, p.id, r.content_id, r.content_type, r.rating, r.rated_at
+FROM user_ratings r
+JOIN profiles p ON p.user_id = r.user_id;
+
+DROP TABLE user_ratings;
+ALTER TABLE user_ratings_new RENAME TO user_ratings;
+
+-- Perfil elegido en cada sesión (NULL hasta que se elige uno).
+ALTER TABLE auth_sessions ADD COLUMN profile_id INTEGER;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS parental_blocked_content;
+DROP TABLE IF EXISTS parental_blocked_genres;
+DROP TABLE IF EXISTS parental_controls;
+DROP TABLE IF EXISTS parental_pins;
@@ -0,0 +1,32 @@
+-- Control parental por perfil: techo de madurez de cada catálogo y géneros o
+-- títulos bloqueados. El PIN es de la cuenta: protege estos ajustes y
+-- permite ver, una reproducción a la vez, contenido por encima del techo.
+
+CREATE TABLE parental_pins (
+    user_id INTEGER PRIMARY KEY,
+    pin_hash TEXT NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE parental_controls (
+    profile_id INTEGER PRIMARY KEY,
+    max_audiovisual_rating TEXT,            -- NULL: según la clasificación del perfil
+    max_audio_rating TEXT,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+
+CREATE TABLE parental_blocked_genres (
+    profile_id INTEGER NOT NULL,
+    genre TEXT NOT NULL COLLATE NOCASE,
+    PRIMARY KEY (profile_id, genre),
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+
+CREATE TABLE parental_blocked_content (
+    profile_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL CHECK (content_type IN ('audiovisual', 'audio')),
+    PRIMARY KEY (profile_id, content_id, content_type),
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
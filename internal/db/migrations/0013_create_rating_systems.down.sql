@@ -0,0 +1,25 @@
+-- Vuelve a los techos por catálogo con las escalas fijas: se elige la
+-- clasificación más alta de MPAA y de Parental Advisory que no supere el
+-- nivel guardado. El contenido de otros sistemas conserva su código.
+
+CREATE TABLE parental_controls_old (
+    profile_id INTEGER PRIMARY KEY,
+    max_audiovisual_rating TEXT,            -- NULL: según la clasificación del perfil
+    max_audio_rating TEXT,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+
+INSERT INTO parental_controls_old (profile_id, max_audiovisual_rating, max_audio_rating)
+SELECT pc.profile_id,
+    (SELECT code FROM maturity_ratings WHERE system_code = 'MPAA' AND level <= pc.max_level ORDER BY level DESC LIMIT 1),
+    (SELECT code FROM maturity_ratings WHERE system_code = 'PA' AND level <= pc.max_level ORDER BY level DESC LIMIT 1)
+FROM parental_controls pc;
+
+DROP TABLE parental_controls;
+ALTER TABLE parental_controls_old RENAME TO parental_controls;
+
+ALTER TABLE audio_content DROP COLUMN rating_system;
+ALTER TABLE audiovisual_content DROP COLUMN rating_system;
+
+DROP TABLE IF EXISTS maturity_ratings;
+DROP TABLE IF EXISTS rating_systems;
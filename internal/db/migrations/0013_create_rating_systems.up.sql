@@ -0,0 +1,65 @@
+-- Registro de sistemas de clasificación por edad. Cada clasificación tiene un
+-- nivel de madurez en una escala común a todos los sistemas (0 = todo
+-- público … 4 = adultos), así que filtrar el catálogo es comparar niveles.
+
+CREATE TABLE rating_systems (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    content_type TEXT NOT NULL CHECK (content_type IN ('audiovisual', 'audio')),
+    country TEXT,                           -- NULL: sistema internacional
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE maturity_ratings (
+    system_code TEXT NOT NULL,
+    code TEXT NOT NULL,
+    level INTEGER NOT NULL CHECK (level BETWEEN 0 AND 4),
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (system_code, code),
+    FOREIGN KEY (system_code) REFERENCES rating_systems(code) ON DELETE CASCADE
+);
+
+INSERT INTO rating_systems (code, name, content_type, country) VALUES
+    ('MPAA', 'Motion Picture Association', 'audiovisual', 'US'),
+    ('PEGI', 'Pan European Game Information', 'audiovisual', NULL),
+    ('ICAA', 'Instituto de la Cinematografía', 'audiovisual', 'ES'),
+    ('PA', 'Parental Advisory', 'audio', NULL);
+
+INSERT INTO maturity_ratings (system_code, code, level, description) VALUES
+    ('MPAA', 'G', 0, 'Todo público'),
+    ('MPAA', 'PG', 1, 'Se sugiere guía parental'),
+    ('MPAA', 'PG-13', 2, 'No recomendada para menores de 13'),
+    ('MPAA', 'R', 3, 'Menores de 17 acompañados'),
+    ('MPAA', 'NC-17', 4, 'Solo adultos'),
+    ('PEGI', 'PEGI 3', 0, 'Todo público'),
+    ('PEGI', 'PEGI 7', 1, 'Mayores de 7'),
+    ('PEGI', 'PEGI 12', 2, 'Mayores de 12'),
+    ('PEGI', 'PEGI 16', 3, 'Mayores de 16'),
+    ('PEGI', 'PEGI 18', 4, 'Mayores de 18'),
+    ('ICAA', 'TP', 0, 'Todos los públicos'),
+    ('ICAA', '7', 1, 'No recomendada para menores de 7'),
+    ('ICAA', '12', 2, 'No recomendada para menores de 12'),
+    ('ICAA', '16', 3, 'No recomendada para menores de 16'),
+    ('ICAA', '18', 4, 'No recomendada para menores de 18'),
+    ('PA', 'General', 0, 'Sin advertencias'),
+    ('PA', 'Explicit', 4, 'Contenido explícito');
+
+ALTER TABLE audiovisual_content ADD COLUMN rating_system TEXT NOT NULL DEFAULT 'MPAA';
+ALTER TABLE audio_content ADD COLUMN rating_system TEXT NOT NULL DEFAULT 'PA';
+
+-- El control parental pasa de un techo por catálogo a un nivel máximo. Se
+-- conserva el techo audiovisual si había uno; si no, el de audio.
+CREATE TABLE parental_controls_new (
+    profile_id INTEGER PRIMARY KEY,
+    max_level INTEGER CHECK (max_level BETWEEN 0 AND 4),   -- NULL: según la clasificación del perfil
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+
+INSERT INTO parental_controls_new (profile_id, max_level)
+SELECT pc.profile_id, COALESCE(
+    (SELECT level FROM maturity_ratings WHERE system_code = 'MPAA' AND code = pc.max_audiovisual_rating),
+    (SELECT level FROM maturity_ratings WHERE system_code = 'PA' AND code = pc.max_audio_rating))
+FROM parental_controls pc;
+
+DROP TABLE parental_controls;
+ALTER TABLE parental_controls_new RENAME TO parental_controls;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS content_territories;
+
+ALTER TABLE audio_content DROP COLUMN available_until;
+ALTER TABLE audio_content DROP COLUMN available_from;
+ALTER TABLE audiovisual_content DROP COLUMN available_until;
+ALTER TABLE audiovisual_content DROP COLUMN available_from;
+
+ALTER TABLE users DROP COLUMN country;
@@ -0,0 +1,18 @@
+-- Licencias de contenido: ventana de disponibilidad (NULL = sin límite) y
+-- países donde se puede ver. Un contenido sin países en content_territories
+-- está licenciado en todo el mundo. La región del usuario decide qué ve; las
+-- cuentas existentes quedan en Ecuador.
+
+ALTER TABLE users ADD COLUMN country TEXT NOT NULL DEFAULT 'EC';
+
+ALTER TABLE audiovisual_content ADD COLUMN available_from DATETIME;
+ALTER TABLE audiovisual_content ADD COLUMN available_until DATETIME;
+ALTER TABLE audio_content ADD COLUMN available_from DATETIME;
+ALTER TABLE audio_content ADD COLUMN available_until DATETIME;
+
+CREATE TABLE content_territories (
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL CHECK (content_type IN ('audiovisual', 'audio')),
+    country TEXT NOT NULL,
+    PRIMARY KEY (content_id, content_type, country)
+);
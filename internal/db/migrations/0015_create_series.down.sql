@@ -0,0 +1,4 @@
+ALTER TABLE playback_history DROP COLUMN episode_id;
+
+DROP TABLE IF EXISTS episodes;
+DROP TABLE IF EXISTS seasons;
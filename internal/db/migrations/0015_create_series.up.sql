@@ -0,0 +1,48 @@
+-- Series, temporadas y episodios. Una serie sigue siendo una fila de
+-- audiovisual_content de tipo 'series' (con su clasificación, licencia y
+-- calidades); sus episodios cuelgan de las temporadas. Cada serie existente
+-- queda con una temporada de un episodio con su duración, y su historial
+-- apunta a ese episodio.
+
+CREATE TABLE seasons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    series_id INTEGER NOT NULL,
+    number INTEGER NOT NULL CHECK (number > 0),
+    title TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (series_id) REFERENCES audiovisual_content(id) ON DELETE CASCADE,
+    UNIQUE(series_id, number)
+);
+
+CREATE TABLE episodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    season_id INTEGER NOT NULL,
+    number INTEGER NOT NULL CHECK (number > 0),
+    title TEXT NOT NULL,
+    duration INTEGER NOT NULL CHECK (duration > 0),   -- minutos
+    synopsis TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE CASCADE,
+    UNIQUE(season_id, number)
+);
+
+-- Episodio visto cuando el contenido es una serie; NULL en el resto.
+ALTER TABLE playback_history ADD COLUMN episode_id INTEGER;
+
+INSERT INTO seasons (series_id, number, title)
+SELECT id, 1, 'Temporada 1'
+FROM audiovisual_content
+WHERE type = 'series';
+
+INSERT INTO episodes (season_id, number, title, duration, synopsis)
+SELECT s.id, 1, c.title, c.duration, COALESCE(c.synopsis, '')
+FROM seasons s
+JOIN audiovisual_content c ON c.id = s.series_id;
+
+UPDATE playback_history
+SET episode_id = (
+    SELECT e.id
+    FROM episodes e
+    JOIN seasons s ON s.id = e.season_id
+    WHERE s.series_id = playback_history.content_id
+)
+WHERE content_type = 'audiovisual'
+  AND content_id IN (SELECT id FROM audiovisual_content WHERE type = 'series');
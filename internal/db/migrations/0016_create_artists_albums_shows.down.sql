@@ -0,0 +1,8 @@
+ALTER TABLE audio_content DROP COLUMN show_id;
+ALTER TABLE audio_content DROP COLUMN album_id;
+ALTER TABLE audio_content DROP COLUMN artist_id;
+
+DROP TABLE IF EXISTS artist_follows;
+DROP TABLE IF EXISTS shows;
+DROP TABLE IF EXISTS albums;
+DROP TABLE IF EXISTS artists;
@@ -0,0 +1,68 @@
+-- Artistas, álbumes y programas de podcast como entidades propias, enlazadas
+-- desde audio_content. Las columnas de texto artist y album se conservan
+-- para mostrar. En un podcast, artist es el nombre del programa y
+-- track_number el número de episodio. Los perfiles pueden seguir artistas.
+
+CREATE TABLE artists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE albums (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    artist_id INTEGER NOT NULL,
+    title TEXT NOT NULL COLLATE NOCASE,
+    FOREIGN KEY (artist_id) REFERENCES artists(id) ON DELETE CASCADE,
+    UNIQUE(artist_id, title)
+);
+
+CREATE TABLE shows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE artist_follows (
+    profile_id INTEGER NOT NULL,
+    artist_id INTEGER NOT NULL,
+    followed_at DATETIME NOT NULL,
+    PRIMARY KEY (profile_id, artist_id),
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    FOREIGN KEY (artist_id) REFERENCES artists(id) ON DELETE CASCADE
+);
+
+ALTER TABLE audio_content ADD COLUMN artist_id INTEGER;
+ALTER TABLE audio_content ADD COLUMN album_id INTEGER;
+ALTER TABLE audio_content ADD COLUMN show_id INTEGER;
+
+INSERT OR IGNORE INTO artists (name)
+SELECT DISTINCT TRIM(artist)
+FROM audio_content
+WHERE type <> 'podcast' AND TRIM(COALESCE(artist, '')) <> '';
+
+INSERT OR IGNORE INTO albums (artist_id, title)
+SELECT DISTINCT a.id, TRIM(c.album)
+FROM audio_content c
+JOIN artists a ON a.name = TRIM(c.artist)
+WHERE c.type <> 'podcast' AND TRIM(COALESCE(c.album, '')) <> '';
+
+INSERT OR IGNORE INTO shows (title)
+SELECT DISTINCT TRIM(artist)
+FROM audio_content
+WHERE type = 'podcast' AND TRIM(COALESCE(artist, '')) <> '';
+
+UPDATE audio_content
+SET artist_id = (SELECT id FROM artists WHERE name = TRIM(audio_content.artist))
+WHERE type <> 'podcast';
+
+UPDATE audio_content
+SET album_id = (
+    SELECT al.id FROM albums al
+    WHERE al.artist_id = audio_content.artist_id AND al.title = TRIM(audio_content.album)
+)
+WHERE artist_id IS NOT NULL;
+
+UPDATE audio_content
+SET show_id = (SELECT id FROM shows WHERE title = TRIM(audio_content.artist))
+WHERE type = 'podcast';
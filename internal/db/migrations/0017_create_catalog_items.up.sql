@@ -0,0 +1,29 @@
+-- Ficha común de todo el contenido, sin importar el catálogo. Favoritos,
+-- historial y calificaciones la consultan por (content_type, content_id).
+-- Un catálogo nuevo suma aquí su tabla con un UNION ALL.
+CREATE VIEW catalog_items AS
+SELECT 'audiovisual' AS content_type,
+       id AS content_id,
+       title,
+       type AS kind,
+       genre,
+       duration,
+       age_rating,
+       rating_system,
+       director AS credit,
+       average_rating,
+       is_available
+FROM audiovisual_content
+UNION ALL
+SELECT 'audio' AS content_type,
+       id AS content_id,
+       title,
+       type AS kind,
+       genre,
+       duration,
+       age_rating,
+       rating_system,
+       artist AS credit,
+       average_rating,
+       is_available
+FROM audio_content;
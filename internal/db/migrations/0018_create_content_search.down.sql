@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS audio_content_search_delete;
+DROP TRIGGER IF EXISTS audio_content_search_update;
+DROP TRIGGER IF EXISTS audio_content_search_insert;
+DROP TRIGGER IF EXISTS audiovisual_content_search_delete;
+DROP TRIGGER IF EXISTS audiovisual_content_search_update;
+DROP TRIGGER IF EXISTS audiovisual_content_search_insert;
+DROP TABLE IF EXISTS content_search;
@@ -0,0 +1,55 @@
+-- Índice de texto completo del catálogo (FTS4, que go-sqlite3 incluye sin
+-- etiquetas de compilación). unicode61 con remove_diacritics hace que
+-- "cancion" encuentre "Canción".
+CREATE VIRTUAL TABLE content_search USING fts4(
+    title,
+    synopsis,
+    director,
+    artist,
+    album,
+    genre,
+    content_type,
+    content_id,
+    notindexed=content_type,
+    notindexed=content_id,
+    tokenize=unicode61 "remove_diacritics=2"
+);
+
+INSERT INTO content_search (title, synopsis, director, artist, album, genre, content_type, content_id)
+SELECT title, COALESCE(synopsis, ''), COALESCE(director, ''), '', '', genre, 'audiovisual', id
+FROM audiovisual_content;
+
+INSERT INTO content_search (title, synopsis, director, artist, album, genre, content_type, content_id)
+SELECT title, '', '', COALESCE(artist, ''), COALESCE(album, ''), genre, 'audio', id
+FROM audio_content;
+
+-- Los triggers mantienen el índice al día con cada catálogo.
+CREATE TRIGGER audiovisual_content_search_insert AFTER INSERT ON audiovisual_content BEGIN
+    INSERT INTO content_search (title, synopsis, director, artist, album, genre, content_type, content_id)
+    VALUES (NEW.title, COALESCE(NEW.synopsis, ''), COALESCE(NEW.director, ''), '', '', NEW.genre, 'audiovisual', NEW.id);
+END;
+
+CREATE TRIGGER audiovisual_content_search_update AFTER UPDATE OF title, synopsis, director, genre ON audiovisual_content BEGIN
+    UPDATE content_search
+    SET title = NEW.title, synopsis = COALESCE(NEW.synopsis, ''), director = COALESCE(NEW.director, ''), genre = NEW.genre
+    WHERE content_type = 'audiovisual' AND content_id = OLD.id;
+END;
+
+CREATE TRIGGER audiovisual_content_search_delete AFTER DELETE ON audiovisual_content BEGIN
+    DELETE FROM content_search WHERE content_type = 'audiovisual' AND content_id = OLD.id;
+END;
+
+CREATE TRIGGER audio_content_search_insert AFTER INSERT ON audio_content BEGIN
+    INSERT INTO content_search (title, synopsis, director, artist, album, genre, content_type, content_id)
+    VALUES (NEW.title, '', '', COALESCE(NEW.artist, ''), COALESCE(NEW.album, ''), NEW.genre, 'audio', NEW.id);
+END;
+
+CREATE TRIGGER audio_content_search_update AFTER UPDATE OF title, artist, album, genre ON audio_content BEGIN
+    UPDATE content_search
+    SET title = NEW.title, artist = COALESCE(NEW.artist, ''), album = COALESCE(NEW.album, ''), genre = NEW.genre
+    WHERE content_type = 'audio' AND content_id = OLD.id;
+END;
+
+CREATE TRIGGER audio_content_search_delete AFTER DELETE ON audio_content BEGIN
+    DELETE FROM content_search WHERE content_type = 'audio' AND content_id = OLD.id;
+END;
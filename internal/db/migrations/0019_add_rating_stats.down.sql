@@ -0,0 +1,34 @@
+DROP VIEW catalog_items;
+CREATE VIEW catalog_items AS
+SELECT 'audiovisual' AS content_type,
+       id AS content_id,
+       title,
+       type AS kind,
+       genre,
+       duration,
+       age_rating,
+       rating_system,
+       director AS credit,
+       average_rating,
+       is_available
+FROM audiovisual_content
+UNION ALL
+SELECT 'audio' AS content_type,
+       id AS content_id,
+       title,
+       type AS kind,
+       genre,
+       duration,
+       age_rating,
+       rating_system,
+       artist AS credit,
+       average_rating,
+       is_available
+FROM audio_content;
+
+DROP INDEX IF EXISTS idx_user_ratings_content;
+
+ALTER TABLE audio_content DROP COLUMN weighted_rating;
+ALTER TABLE audio_content DROP COLUMN rating_count;
+ALTER TABLE audiovisual_content DROP COLUMN weighted_rating;
+ALTER TABLE audiovisual_content DROP COLUMN rating_count;
@@ -0,0 +1,54 @@
+-- Votos y puntaje bayesiano de cada contenido. average_rating sigue siendo el
+-- promedio simple; weighted_rating lo acerca a la media del catálogo mientras
+-- hay pocos votos y es el que ordena el catálogo. El peso de esa media (5
+-- votos) es services.RatingPriorVotes.
+ALTER TABLE audiovisual_content ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE audiovisual_content ADD COLUMN weighted_rating REAL NOT NULL DEFAULT 0;
+ALTER TABLE audio_content ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE audio_content ADD COLUMN weighted_rating REAL NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_user_ratings_content ON user_ratings(content_type, content_id);
+
+UPDATE audiovisual_content
+SET rating_count = (SELECT COUNT(*) FROM user_ratings r WHERE r.content_type = 'audiovisual' AND r.content_id = audiovisual_content.id),
+    average_rating = COALESCE((SELECT AVG(r.rating) FROM user_ratings r WHERE r.content_type = 'audiovisual' AND r.content_id = audiovisual_content.id), 0);
+UPDATE audiovisual_content
+SET weighted_rating = (rating_count * average_rating + 5 * (SELECT COALESCE(AVG(rating), 0) FROM user_ratings WHERE content_type = 'audiovisual')) / (rating_count + 5);
+
+UPDATE audio_content
+SET rating_count = (SELECT COUNT(*) FROM user_ratings r WHERE r.content_type = 'audio' AND r.content_id = audio_content.id),
+    average_rating = COALESCE((SELECT AVG(r.rating) FROM user_ratings r WHERE r.content_type = 'audio' AND r.content_id = audio_content.id), 0);
+UPDATE audio_content
+SET weighted_rating = (rating_count * average_rating + 5 * (SELECT COALESCE(AVG(rating), 0) FROM user_ratings WHERE content_type = 'audio')) / (rating_count + 5);
+
+DROP VIEW catalog_items;
+CREATE VIEW catalog_items AS
+SELECT 'audiovisual' AS content_type,
+       id AS content_id,
+       title,
+       type AS kind,
+       genre,
+       duration,
+       age_rating,
+       rating_system,
+       director AS credit,
+       average_rating,
+       rating_count,
+       weighted_rating,
+       is_available
+FROM audiovisual_content
+UNION ALL
+SELECT 'audio' AS content_type,
+       id AS content_id,
+       title,
+       type AS kind,
+       genre,
+       duration,
+       age_rating,
+       rating_system,
+       artist AS credit,
+       average_rating,
+       rating_count,
+       weighted_rating,
+       is_available
+FROM audio_content;
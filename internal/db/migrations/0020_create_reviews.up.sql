@@ -0,0 +1,42 @@
+-- Reseñas escritas. Cada reseña cuelga de la calificación (user_ratings) de
+-- un perfil, así que hay a lo sumo una por perfil y contenido. Una reseña
+-- nueva o editada queda pendiente (pending) hasta que un moderador la
+-- aprueba (approved) u oculta (hidden); solo las aprobadas se muestran.
+CREATE TABLE reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rating_id INTEGER NOT NULL UNIQUE,
+    body TEXT NOT NULL,
+    has_spoilers INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',  -- pending | approved | hidden
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    moderated_by INTEGER,
+    moderated_at DATETIME,
+    FOREIGN KEY (rating_id) REFERENCES user_ratings(id) ON DELETE CASCADE,
+    FOREIGN KEY (moderated_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_reviews_status ON reviews(status);
+
+-- Perfiles a los que una reseña les resultó útil.
+CREATE TABLE review_votes (
+    review_id INTEGER NOT NULL,
+    profile_id INTEGER NOT NULL,
+    voted_at DATETIME NOT NULL,
+    PRIMARY KEY (review_id, profile_id),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+
+-- Denuncias de reseñas. Una aprobada con denuncias sin resolver vuelve a la
+-- cola de moderación; aprobarla u ocultarla las resuelve.
+CREATE TABLE review_reports (
+    review_id INTEGER NOT NULL,
+    profile_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    reported_at DATETIME NOT NULL,
+    resolved INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (review_id, profile_id),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE playback_history DROP COLUMN completed;
+DROP TABLE IF EXISTS playback_progress;
@@ -0,0 +1,41 @@
+-- Punto de reanudación: una fila por perfil y título con la última posición
+-- informada. En una serie guarda también el episodio. completed indica que
+-- esa reproducción llegó a models.CompletionThreshold de la duración.
+CREATE TABLE playback_progress (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    profile_id INTEGER NOT NULL,
+    content_id INTEGER NOT NULL,
+    content_type TEXT NOT NULL,         -- audiovisual | audio
+    episode_id INTEGER,
+    position_seconds INTEGER NOT NULL DEFAULT 0,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    FOREIGN KEY (episode_id) REFERENCES episodes(id),
+    UNIQUE(profile_id, content_id, content_type)
+);
+
+-- Cada entrada del historial es una reproducción; completed marca las que
+-- llegaron al umbral, que son las que cuentan como terminadas en tendencias.
+ALTER TABLE playback_history ADD COLUMN completed INTEGER NOT NULL DEFAULT 0;
+
+UPDATE playback_history
+SET completed = 1
+WHERE progress_seconds >= 0.9 * 60 * COALESCE(
+    (SELECT e.duration FROM episodes e WHERE e.id = playback_history.episode_id),
+    (SELECT ci.duration FROM catalog_items ci
+     WHERE ci.content_type = playback_history.content_type AND ci.content_id = playback_history.content_id));
+
+INSERT INTO playback_progress (profile_id, content_id, content_type, episode_id, position_seconds, duration_seconds, completed, updated_at)
+SELECT h.profile_id, h.content_id, h.content_type, h.episode_id, h.progress_seconds,
+       60 * COALESCE(e.duration, ci.duration, 0), h.completed, h.watched_at
+FROM playback_history h
+LEFT JOIN episodes e ON e.id = h.episode_id
+LEFT JOIN catalog_items ci ON ci.content_type = h.content_type AND ci.content_id = h.content_id
+WHERE h.id = (
+    SELECT h2.id FROM playback_history h2
+    WHERE h2.profile_id = h.profile_id AND h2.content_id = h.content_id AND h2.content_type = h.content_type
+    ORDER BY h2.watched_at DESC, h2.id DESC
+    LIMIT 1
+);
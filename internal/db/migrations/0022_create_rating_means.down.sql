@@ -0,0 +1,8 @@
+UPDATE audiovisual_content
+SET weighted_rating = COALESCE((SELECT mean FROM rating_means WHERE content_type = 'audiovisual'), 0)
+WHERE rating_count = 0;
+UPDATE audio_content
+SET weighted_rating = COALESCE((SELECT mean FROM rating_means WHERE content_type = 'audio'), 0)
+WHERE rating_count = 0;
+
+DROP TABLE rating_means;
@@ -0,0 +1,19 @@
+-- Media de las calificaciones de cada tipo de contenido, la que usa el
+-- puntaje bayesiano. Se guarda para que un voto solo recalcule la fila del
+-- contenido votado; la media y el puntaje del resto del catálogo se rehacen
+-- cuando la guardada tiene más de services.RatingMeanRefreshInterval. Sin
+-- votos la media es NULL: el primer voto la calcula en el momento.
+CREATE TABLE rating_means (
+    content_type TEXT PRIMARY KEY,      -- audiovisual | audio
+    mean REAL,
+    refreshed_at DATETIME NOT NULL
+);
+
+INSERT INTO rating_means (content_type, mean, refreshed_at)
+SELECT 'audiovisual', AVG(rating), CURRENT_TIMESTAMP FROM user_ratings WHERE content_type = 'audiovisual';
+INSERT INTO rating_means (content_type, mean, refreshed_at)
+SELECT 'audio', AVG(rating), CURRENT_TIMESTAMP FROM user_ratings WHERE content_type = 'audio';
+
+-- Un contenido sin votos no tiene puntaje: ya no toma la media del catálogo.
+UPDATE audiovisual_content SET weighted_rating = 0 WHERE rating_count = 0;
+UPDATE audio_content SET weighted_rating = 0 WHERE rating_count = 0;
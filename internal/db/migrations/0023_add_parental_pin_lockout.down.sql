@@ -0,0 +1,2 @@
+ALTER TABLE parental_pins DROP COLUMN locked_until;
+ALTER TABLE parental_pins DROP COLUMN failed_attempts;
@@ -0,0 +1,4 @@
+-- Intentos fallidos del PIN parental: al llegar al máximo, el PIN queda
+-- bloqueado hasta locked_until y la cuenta vuelve a contar desde cero.
+ALTER TABLE parental_pins ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE parental_pins ADD COLUMN locked_until DATETIME;
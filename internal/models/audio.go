@@ -1,17 +1,35 @@
 // internal/models/audio.go
 package models
 
+import "time"
+
 // AudioContent represents music, podcasts, or audiobooks.
 type AudioContent struct {
-	ID            int     `db:"id"`
-	Title         string  `db:"title"`
-	Type          string  `db:"type"`
-	Genre         string  `db:"genre"`
-	Duration      int     `db:"duration"` // minutes
-	AgeRating     string  `db:"age_rating"`
-	Artist        string  `db:"artist"`
-	Album         string  `db:"album"`
-	TrackNumber   int     `db:"track_number"`
-	AverageRating float64 `db:"average_rating"`
-	IsAvailable   bool    `db:"is_available"`
+	ID            int     `db:"id" json:"id"`
+	Title         string  `db:"title" json:"title"`
+	Type          string  `db:"type" json:"type"`
+	Genre         string  `db:"genre" json:"genre"`
+	Duration      int     `db:"duration" json:"duration"` // minutes
+	AgeRating     string  `db:"age_rating" json:"age_rating"`
+	RatingSystem  string  `db:"rating_system" json:"rating_system"`
+	Artist        string  `db:"artist" json:"artist"`
+	Album         string  `db:"album" json:"album"`
+	TrackNumber   int     `db:"track_number" json:"track_number"`
+	AverageRating float64 `db:"average_rating" json:"average_rating"`
+	// RatingCount son los votos y WeightedRating el puntaje bayesiano con el
+	// que se ordena el catálogo.
+	RatingCount    int     `db:"rating_count" json:"rating_count"`
+	WeightedRating float64 `db:"weighted_rating" json:"weighted_rating"`
+	IsAvailable    bool    `db:"is_available" json:"is_available"`
+	// Entidades enlazadas: artista y álbum en música y audiolibros, programa
+	// en los podcasts.
+	ArtistID *int `db:"artist_id" json:"artist_id,omitempty"`
+	AlbumID  *int `db:"album_id" json:"album_id,omitempty"`
+	ShowID   *int `db:"show_id" json:"show_id,omitempty"`
+	// Ventana de licencia; nil si no tiene inicio o fin.
+	AvailableFrom  *time.Time `db:"available_from" json:"available_from,omitempty"`
+	AvailableUntil *time.Time `db:"available_until" json:"available_until,omitempty"`
+	// Countries son los países donde está licenciado (vacío: todo el mundo);
+	// solo se cargan al obtener un contenido por id.
+	Countries []string `json:"countries,omitempty"`
 }
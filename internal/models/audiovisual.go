@@ -1,17 +1,33 @@
 // internal/models/audiovisual.go
 package models
 
+import "time"
+
 // AudiovisualContent represents movies, series, or documentaries.
 type AudiovisualContent struct {
-	ID            int     `db:"id"`
-	Title         string  `db:"title"`
-	Type          string  `db:"type"`
-	Genre         string  `db:"genre"`
-	Duration      int     `db:"duration"` // minutes
-	AgeRating     string  `db:"age_rating"`
-	Synopsis      string  `db:"synopsis"`
-	ReleaseYear   int     `db:"release_year"`
-	Director      string  `db:"director"`
-	AverageRating float64 `db:"average_rating"`
-	IsAvailable   bool    `db:"is_available"`
+	ID            int     `db:"id" json:"id"`
+	Title         string  `db:"title" json:"title"`
+	Type          string  `db:"type" json:"type"`
+	Genre         string  `db:"genre" json:"genre"`
+	Duration      int     `db:"duration" json:"duration"` // minutes
+	AgeRating     string  `db:"age_rating" json:"age_rating"`
+	RatingSystem  string  `db:"rating_system" json:"rating_system"`
+	Synopsis      string  `db:"synopsis" json:"synopsis"`
+	ReleaseYear   int     `db:"release_year" json:"release_year"`
+	Director      string  `db:"director" json:"director"`
+	AverageRating float64 `db:"average_rating" json:"average_rating"`
+	// RatingCount son los votos y WeightedRating el puntaje bayesiano con el
+	// que se ordena el catálogo.
+	RatingCount    int     `db:"rating_count" json:"rating_count"`
+	WeightedRating float64 `db:"weighted_rating" json:"weighted_rating"`
+	IsAvailable    bool    `db:"is_available" json:"is_available"`
+	// Ventana de licencia; nil si no tiene inicio o fin.
+	AvailableFrom  *time.Time `db:"available_from" json:"available_from,omitempty"`
+	AvailableUntil *time.Time `db:"available_until" json:"available_until,omitempty"`
+	// Countries son los países donde está licenciado (vacío: todo el mundo);
+	// solo se cargan al obtener un contenido por id.
+	Countries []string `json:"countries,omitempty"`
+	// Renditions son las calidades disponibles (SD/HD/4K); solo se cargan
+	// al obtener un contenido por id.
+	Renditions []string `json:"renditions,omitempty"`
 }
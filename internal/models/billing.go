@@ -0,0 +1,42 @@
+// internal/models/billing.go
+package models
+
+import "time"
+
+// Tipos de transacción del libro de pagos.
+const (
+	TransactionCharge    = "charge"
+	TransactionRefund    = "refund"
+	TransactionProration = "proration"
+)
+
+// Invoice es una factura emitida por un cobro de suscripción.
+type Invoice struct {
+	ID          int       `db:"id" json:"id"`
+	Number      string    `json:"number"` // derivado del id, p. ej. F-000001
+	UserID      int       `db:"user_id" json:"user_id"`
+	UserEmail   string    `json:"user_email,omitempty"`
+	PlanID      int       `db:"plan_id" json:"plan_id"`
+	Description string    `db:"description" json:"description"`
+	AmountCents int64     `db:"amount_cents" json:"amount_cents"`
+	Currency    string    `db:"currency" json:"currency"`
+	PeriodStart time.Time `db:"period_start" json:"period_start"`
+	PeriodEnd   time.Time `db:"period_end" json:"period_end"`
+	IssuedAt    time.Time `db:"issued_at" json:"issued_at"`
+
+	// RefundedCents suma (en positivo) las devoluciones registradas.
+	RefundedCents int64                `json:"refunded_cents"`
+	Transactions  []PaymentTransaction `json:"transactions"`
+}
+
+// PaymentTransaction es una fila inmutable del libro de pagos.
+type PaymentTransaction struct {
+	ID          int       `db:"id" json:"id"`
+	UserID      int       `db:"user_id" json:"user_id"`
+	InvoiceID   int       `db:"invoice_id" json:"invoice_id"`
+	Kind        string    `db:"kind" json:"kind"`
+	AmountCents int64     `db:"amount_cents" json:"amount_cents"`
+	GatewayRef  string    `db:"gateway_ref" json:"gateway_ref"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
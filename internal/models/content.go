@@ -0,0 +1,121 @@
+// internal/models/content.go
+package models
+
+// Catálogos de contenido. Favoritos, historial, calificaciones y licencias
+// identifican un contenido por su catálogo y su id (ContentRef).
+const (
+	ContentTypeAudiovisual = "audiovisual"
+	ContentTypeAudio       = "audio"
+)
+
+// Catalog describe uno de los catálogos: la tabla donde vive su contenido,
+// cómo se lo nombra en los mensajes y el sistema de clasificación por
+// defecto. Un medio nuevo se agrega registrándolo en Catalogs y sumando su
+// tabla a la vista catalog_items.
+type Catalog struct {
+	ContentType         string
+	Table               string
+	Label               string
+	DefaultRatingSystem string
+}
+
+// Catalogs son los catálogos registrados, en el orden en que se muestran.
+var Catalogs = []Catalog{
+	{ContentType: ContentTypeAudiovisual, Table: "audiovisual_content", Label: "contenido audiovisual", DefaultRatingSystem: RatingSystemMPAA},
+	{ContentType: ContentTypeAudio, Table: "audio_content", Label: "contenido de audio", DefaultRatingSystem: RatingSystemPA},
+}
+
+// CatalogOf devuelve el catálogo registrado para contentType.
+func CatalogOf(contentType string) (Catalog, bool) {
+	for _, c := range Catalogs {
+		if c.ContentType == contentType {
+			return c, true
+		}
+	}
+	return Catalog{}, false
+}
+
+// IsContentType indica si contentType es un catálogo registrado.
+func IsContentType(contentType string) bool {
+	_, ok := CatalogOf(contentType)
+	return ok
+}
+
+// ContentRef identifica un contenido de cualquier catálogo.
+type ContentRef struct {
+	ContentID   int    `db:"content_id" json:"content_id"`
+	ContentType string `db:"content_type" json:"content_type"`
+}
+
+// Content es lo que tiene todo contenido, sea del catálogo que sea.
+type Content interface {
+	Ref() ContentRef
+	Item() CatalogItem
+}
+
+// CatalogItem es la ficha común de un contenido, tal como la devuelve la
+// vista catalog_items. Kind es el tipo dentro del catálogo (movie, series,
+// song, podcast, …) y Credit el director o el artista.
+type CatalogItem struct {
+	ContentRef
+	Title          string  `db:"title" json:"title"`
+	Kind           string  `db:"kind" json:"kind"`
+	Genre          string  `db:"genre" json:"genre"`
+	Duration       int     `db:"duration" json:"duration"` // minutes
+	AgeRating      string  `db:"age_rating" json:"age_rating"`
+	RatingSystem   string  `db:"rating_system" json:"rating_system"`
+	Credit         string  `db:"credit" json:"credit,omitempty"`
+	AverageRating  float64 `db:"average_rating" json:"average_rating"`
+	RatingCount    int     `db:"rating_count" json:"rating_count"`
+	WeightedRating float64 `db:"weighted_rating" json:"weighted_rating"`
+	IsAvailable    bool    `db:"is_available" json:"is_available"`
+}
+
+func (c *AudiovisualContent) Ref() ContentRef {
+	return ContentRef{ContentID: c.ID, ContentType: ContentTypeAudiovisual}
+}
+
+func (c *AudiovisualContent) Item() CatalogItem {
+	return CatalogItem{
+		ContentRef:     c.Ref(),
+		Title:          c.Title,
+		Kind:           c.Type,
+		Genre:          c.Genre,
+		Duration:       c.Duration,
+		AgeRating:      c.AgeRating,
+		RatingSystem:   c.RatingSystem,
+		Credit:         c.Director,
+		AverageRating:  c.AverageRating,
+		RatingCount:    c.RatingCount,
+		WeightedRating: c.WeightedRating,
+		IsAvailable:    c.IsAvailable,
+	}
+}
+
+func (c *AudioContent) Ref() ContentRef {
+	return ContentRef{ContentID: c.ID, ContentType: ContentTypeAudio}
+}
+
+func (c *AudioContent) Item() CatalogItem {
+	return CatalogItem{
+		ContentRef:     c.Ref(),
+		Title:          c.Title,
+		Kind:           c.Type,
+		Genre:          c.Genre,
+		Duration:       c.Duration,
+		AgeRating:      c.AgeRating,
+		RatingSystem:   c.RatingSystem,
+		Credit:         c.Artist,
+		AverageRating:  c.AverageRating,
+		RatingCount:    c.RatingCount,
+		WeightedRating: c.WeightedRating,
+		IsAvailable:    c.IsAvailable,
+	}
+}
+
+// SearchResult es un contenido encontrado por el buscador; Score es su
+// relevancia, mayor cuanto mejor coincide.
+type SearchResult struct {
+	CatalogItem
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,39 @@
+// internal/models/library.go
+package models
+
+import "time"
+
+// Tipos de contenido de audio.
+const (
+	TypeSong      = "song"
+	TypePodcast   = "podcast"
+	TypeAudiobook = "audiobook"
+)
+
+// Artist es un artista (o autor, en los audiolibros). En su discografía,
+// Albums trae cada álbum con sus pistas y Singles las pistas sin álbum.
+type Artist struct {
+	ID        int            `db:"id" json:"id"`
+	Name      string         `db:"name" json:"name"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	Albums    []Album        `json:"albums,omitempty"`
+	Singles   []AudioContent `json:"singles,omitempty"`
+}
+
+// Album es un álbum de un artista; Tracks viene ordenado por número de pista.
+type Album struct {
+	ID       int            `db:"id" json:"id"`
+	ArtistID int            `db:"artist_id" json:"artist_id"`
+	Artist   string         `json:"artist"`
+	Title    string         `db:"title" json:"title"`
+	Tracks   []AudioContent `json:"tracks,omitempty"`
+}
+
+// Show es un programa de podcast; Episodes viene del más reciente al más
+// antiguo.
+type Show struct {
+	ID        int            `db:"id" json:"id"`
+	Title     string         `db:"title" json:"title"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	Episodes  []AudioContent `json:"episodes,omitempty"`
+}
@@ -0,0 +1,39 @@
+// internal/models/license.go
+package models
+
+import "time"
+
+// DefaultCountry es la región de las cuentas que no indican otra.
+const DefaultCountry = "EC"
+
+// ContentLicense es la licencia de un contenido: dónde y cuándo se puede ver.
+// Sin países, el contenido está licenciado en todo el mundo; sin fechas, la
+// ventana no tiene inicio o fin.
+type ContentLicense struct {
+	ContentID      int        `json:"content_id"`
+	ContentType    string     `json:"content_type"`
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+	Countries      []string   `json:"countries"`
+}
+
+// InWindow indica si la ventana de licencia está vigente en now.
+func (l *ContentLicense) InWindow(now time.Time) bool {
+	if l.AvailableFrom != nil && now.Before(*l.AvailableFrom) {
+		return false
+	}
+	return l.AvailableUntil == nil || now.Before(*l.AvailableUntil)
+}
+
+// AllowsCountry indica si el contenido está licenciado en el país.
+func (l *ContentLicense) AllowsCountry(country string) bool {
+	if len(l.Countries) == 0 {
+		return true
+	}
+	for _, c := range l.Countries {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
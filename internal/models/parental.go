@@ -0,0 +1,46 @@
+// internal/models/parental.go
+package models
+
+import "time"
+
+// BlockedContent es un título que el control parental oculta a un perfil.
+type BlockedContent struct {
+	ContentID   int    `json:"content_id"`
+	ContentType string `json:"content_type"`
+}
+
+// ParentalControls son las restricciones que el titular de la cuenta fija
+// para un perfil. Sin nivel máximo rige el de la clasificación del perfil
+// (ver ProfileMaxLevel).
+type ParentalControls struct {
+	ProfileID      int              `json:"profile_id"`
+	MaxLevel       *int             `json:"max_level"`
+	BlockedGenres  []string         `json:"blocked_genres"`
+	BlockedContent []BlockedContent `json:"blocked_content"`
+}
+
+// ParentalPIN es el PIN parental de una cuenta con sus intentos fallidos
+// seguidos. LockedUntil, si está en el futuro, es hasta cuándo queda
+// bloqueado por demasiados intentos.
+type ParentalPIN struct {
+	UserID         int
+	Hash           string
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+// Locked indica si en now el PIN está bloqueado.
+func (p *ParentalPIN) Locked(now time.Time) bool {
+	return p.LockedUntil != nil && now.Before(*p.LockedUntil)
+}
+
+// ContentFilter es lo que puede ver un perfil en un catálogo, combinando su
+// clasificación por edad con el control parental y la licencia del contenido
+// en la región de la cuenta.
+type ContentFilter struct {
+	MaxLevel      int
+	BlockedGenres []string
+	BlockedIDs    []int
+	Country       string
+	Now           time.Time
+}
@@ -1,21 +1,81 @@
 // internal/models/playback.go
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// CompletionThreshold es la fracción de la duración a partir de la cual una
+// reproducción cuenta como terminada.
+const CompletionThreshold = 0.9
 
 type PlaybackHistory struct {
-	ID          int       `db:"id"`
-	UserID      int       `db:"user_id"`
-	ContentID   int       `db:"content_id"`
-	ContentType string    `db:"content_type"`
-	Progress    int       `db:"progress_seconds"`
-	WatchedAt   time.Time `db:"watched_at"`
+	ID        int `db:"id" json:"id"`
+	ProfileID int `db:"profile_id" json:"profile_id"`
+	ContentRef
+	Progress  int       `db:"progress_seconds" json:"progress_seconds"`
+	Completed bool      `db:"completed" json:"completed"`
+	WatchedAt time.Time `db:"watched_at" json:"watched_at"`
+	// EpisodeID es el episodio visto cuando el contenido es una serie.
+	EpisodeID *int `db:"episode_id" json:"episode_id,omitempty"`
+	// Item es la ficha del contenido; nil si ya no está en el catálogo.
+	Item *CatalogItem `json:"item,omitempty"`
+}
+
+// ResumePoint es dónde dejó el perfil un título la última vez que lo
+// reprodujo. En una serie, EpisodeID es el episodio que estaba viendo.
+type ResumePoint struct {
+	ProfileID int `db:"profile_id" json:"profile_id"`
+	ContentRef
+	EpisodeID *int      `db:"episode_id" json:"episode_id,omitempty"`
+	Position  int       `db:"position_seconds" json:"position_seconds"`
+	Duration  int       `db:"duration_seconds" json:"duration_seconds"`
+	Completed bool      `db:"completed" json:"completed"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// Item es la ficha del contenido; nil si ya no está en el catálogo.
+	Item *CatalogItem `json:"item,omitempty"`
+}
+
+// Resumable indica si conviene ofrecer seguir desde Position: la última
+// reproducción empezó y no llegó al final.
+func (p *ResumePoint) Resumable() bool {
+	return p != nil && p.Position > 0 && !p.Completed
+}
+
+// IsCompleted indica si position cubre lo suficiente de duration para dar
+// la reproducción por terminada.
+func IsCompleted(position, duration int) bool {
+	return duration > 0 && float64(position) >= CompletionThreshold*float64(duration)
+}
+
+// FormatPosition muestra una posición en segundos como mm:ss, o h:mm:ss
+// desde la primera hora.
+func FormatPosition(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
 }
 
 type Favorite struct {
-	ID          int       `db:"id"`
-	UserID      int       `db:"user_id"`
-	ContentID   int       `db:"content_id"`
-	ContentType string    `db:"content_type"`
-	CreatedAt   time.Time `db:"created_at"`
+	ID        int `db:"id" json:"id"`
+	ProfileID int `db:"profile_id" json:"profile_id"`
+	ContentRef
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// Item es la ficha del contenido; nil si ya no está en el catálogo.
+	Item *CatalogItem `json:"item,omitempty"`
+}
+
+// StreamSession es una reproducción en curso en un dispositivo. Cuenta para
+// el límite de dispositivos del plan hasta que termina o deja de dar señales.
+type StreamSession struct {
+	ID          int        `db:"id" json:"id"`
+	UserID      int        `db:"user_id" json:"user_id"`
+	Device      string     `db:"device" json:"device"`
+	ContentID   int        `db:"content_id" json:"content_id"`
+	ContentType string     `db:"content_type" json:"content_type"`
+	StartedAt   time.Time  `db:"started_at" json:"started_at"`
+	LastSeenAt  time.Time  `db:"last_seen_at" json:"last_seen_at"`
+	EndedAt     *time.Time `db:"ended_at" json:"ended_at,omitempty"`
 }
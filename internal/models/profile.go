@@ -0,0 +1,43 @@
+// internal/models/profile.go
+package models
+
+import "time"
+
+// Clasificaciones por edad de usuarios y perfiles, de menor a mayor.
+const (
+	AgeRatingChild = "Niño"
+	AgeRatingTeen  = "Adolescente"
+	AgeRatingAdult = "Adulto"
+)
+
+// ProfileAgeRatings devuelve las clasificaciones que puede tener un perfil.
+func ProfileAgeRatings() []string {
+	return []string{AgeRatingChild, AgeRatingTeen, AgeRatingAdult}
+}
+
+// AgeRatingRank ordena las clasificaciones por edad; 0 si no es válida.
+func AgeRatingRank(rating string) int {
+	switch rating {
+	case AgeRatingChild:
+		return 1
+	case AgeRatingTeen:
+		return 2
+	case AgeRatingAdult:
+		return 3
+	}
+	return 0
+}
+
+// Profile es una persona del hogar dentro de una cuenta. Cada perfil tiene su
+// propia clasificación, favoritos, historial y calificaciones; el plan y la
+// facturación son de la cuenta.
+type Profile struct {
+	ID        int       `db:"id" json:"id"`
+	UserID    int       `db:"user_id" json:"user_id"`
+	Name      string    `db:"name" json:"name"`
+	AgeRating string    `db:"age_rating" json:"age_rating"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// Locked indica que el perfil excede el límite del plan actual (p. ej.
+	// tras bajar de plan) y no se puede elegir.
+	Locked bool `db:"-" json:"locked,omitempty"`
+}
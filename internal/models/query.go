@@ -0,0 +1,56 @@
+// internal/models/query.go
+package models
+
+// Órdenes posibles al recorrer un catálogo.
+const (
+	SortRating   = "rating"   // mejor calificados primero (por defecto)
+	SortTitle    = "title"    // alfabético
+	SortNewest   = "newest"   // estrenos más recientes primero (solo audiovisual)
+	SortRecent   = "recent"   // agregados al catálogo más recientemente
+	SortDuration = "duration" // más cortos primero
+)
+
+// CatalogSorts son los órdenes aceptados, en el orden en que se ofrecen.
+var CatalogSorts = []string{SortRating, SortTitle, SortNewest, SortRecent, SortDuration}
+
+// Tamaño de página al recorrer un catálogo.
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 50
+)
+
+// CatalogQuery filtra, ordena y pagina un catálogo. Los campos vacíos o en
+// cero no filtran. Kind es el tipo dentro del catálogo (movie, song, …),
+// AgeRating una clasificación exacta y las duraciones van en minutos.
+type CatalogQuery struct {
+	Genre       string
+	Kind        string
+	YearFrom    int
+	YearTo      int
+	MinDuration int
+	MaxDuration int
+	MinRating   float64
+	AgeRating   string
+	Sort        string
+	Offset      int
+	Limit       int
+}
+
+// Page es una página de resultados. Total cuenta todos los que cumplen la
+// consulta, no solo los de la página.
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// HasNext indica si quedan resultados después de esta página.
+func (p *Page[T]) HasNext() bool {
+	return p.Offset+len(p.Items) < p.Total
+}
+
+// HasPrev indica si hay resultados antes de esta página.
+func (p *Page[T]) HasPrev() bool {
+	return p.Offset > 0
+}
@@ -0,0 +1,61 @@
+// internal/models/rating.go
+package models
+
+// Niveles de madurez, comunes a todos los sistemas de clasificación.
+const (
+	LevelAllAges  = 0
+	LevelGuidance = 1
+	LevelTeens    = 2
+	LevelMature   = 3
+	LevelAdults   = 4
+)
+
+// MaturityLevels nombra cada nivel de madurez; el índice es el nivel.
+var MaturityLevels = []string{"Todo público", "Guía parental", "Adolescentes", "Mayores de 16", "Adultos"}
+
+// MaxMaturityLevel es el nivel más alto de la escala.
+const MaxMaturityLevel = LevelAdults
+
+// Sistemas de clasificación que usa el contenido nuevo si no se indica otro.
+const (
+	RatingSystemMPAA = "MPAA"
+	RatingSystemPA   = "PA"
+)
+
+// DefaultRatingSystem devuelve el sistema por defecto de un catálogo.
+func DefaultRatingSystem(contentType string) string {
+	if c, ok := CatalogOf(contentType); ok {
+		return c.DefaultRatingSystem
+	}
+	return RatingSystemMPAA
+}
+
+// ProfileMaxLevel es el nivel de madurez hasta el que puede ver un perfil sin
+// control parental, según su clasificación por edad.
+func ProfileMaxLevel(ageRating string) int {
+	switch ageRating {
+	case AgeRatingChild:
+		return LevelAllAges
+	case AgeRatingTeen:
+		return LevelTeens
+	}
+	return MaxMaturityLevel
+}
+
+// RatingSystem es un sistema de clasificación por edad (MPAA, PEGI, el de un
+// país, …) que se usa en uno de los catálogos.
+type RatingSystem struct {
+	Code        string           `db:"code" json:"code"`
+	Name        string           `db:"name" json:"name"`
+	ContentType string           `db:"content_type" json:"content_type"`
+	Country     string           `db:"country" json:"country,omitempty"`
+	Ratings     []MaturityRating `json:"ratings"`
+}
+
+// MaturityRating es una clasificación de un sistema con su nivel de madurez.
+type MaturityRating struct {
+	SystemCode  string `db:"system_code" json:"-"`
+	Code        string `db:"code" json:"code"`
+	Level       int    `db:"level" json:"level"`
+	Description string `db:"description" json:"description"`
+}
@@ -0,0 +1,37 @@
+// internal/models/recommendation.go
+package models
+
+// Señales con las que un perfil se relaciona con un contenido.
+const (
+	SignalRated    = "rated"
+	SignalFavorite = "favorite"
+	SignalWatched  = "watched"
+)
+
+// Interaction es una señal de un perfil sobre un contenido: haberlo
+// calificado (con Rating), tenerlo en Mi Lista o haberlo reproducido.
+type Interaction struct {
+	ProfileID int
+	ContentRef
+	Signal string
+	Rating float64
+}
+
+// Motivos por los que se recomienda un contenido.
+const (
+	ReasonSimilar  = "similar"   // se parece a algo que el perfil disfrutó
+	ReasonPopular  = "popular"   // lo disfrutaron otros perfiles
+	ReasonTopRated = "top_rated" // de lo mejor calificado del catálogo
+)
+
+// Recommendation es un contenido recomendado a un perfil. Reason y
+// Explanation dicen por qué aparece; si se parece a algo que el perfil vio,
+// calificó o guardó, Because es ese contenido. Score ordena las
+// recomendaciones similares y es cero en las demás.
+type Recommendation struct {
+	CatalogItem
+	Score       float64     `json:"score"`
+	Reason      string      `json:"reason"`
+	Explanation string      `json:"explanation"`
+	Because     *ContentRef `json:"because,omitempty"`
+}
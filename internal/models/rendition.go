@@ -0,0 +1,25 @@
+// internal/models/rendition.go
+package models
+
+import "strings"
+
+// Calidades de reproducción, de menor a mayor. Coinciden con plans.max_quality.
+const (
+	QualitySD = "SD"
+	QualityHD = "HD"
+	Quality4K = "4K"
+)
+
+var qualityRank = map[string]int{QualitySD: 1, QualityHD: 2, Quality4K: 3}
+
+// QualityRank ordena las calidades (SD < HD < 4K); 0 si no es válida.
+func QualityRank(quality string) int {
+	return qualityRank[strings.ToUpper(quality)]
+}
+
+// Rendition es una versión de un contenido audiovisual en una calidad.
+type Rendition struct {
+	ContentID    int    `db:"content_id" json:"content_id"`
+	Quality      string `db:"quality" json:"quality"`
+	ManifestPath string `db:"manifest_path" json:"manifest_path"`
+}
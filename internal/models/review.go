@@ -0,0 +1,56 @@
+// internal/models/review.go
+package models
+
+import "time"
+
+// Estados de una reseña. Solo las aprobadas se muestran a los demás perfiles.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewHidden   = "hidden"
+)
+
+// Acciones de moderación sobre una reseña.
+const (
+	ModerationApprove = "approve"
+	ModerationHide    = "hide"
+	ModerationDelete  = "delete"
+)
+
+// Largo máximo, en caracteres, de una reseña y del motivo de una denuncia.
+const (
+	MaxReviewLength       = 2000
+	MaxReportReasonLength = 200
+)
+
+// Review es la reseña escrita que acompaña la calificación de un perfil.
+type Review struct {
+	ID          int    `db:"id" json:"id"`
+	RatingID    int    `db:"rating_id" json:"-"`
+	ProfileID   int    `db:"profile_id" json:"profile_id"`
+	ProfileName string `json:"profile_name"`
+	ContentRef
+	Rating  float64 `json:"rating"`
+	Body    string  `db:"body" json:"body"`
+	Spoiler bool    `db:"has_spoilers" json:"spoiler"`
+	Status  string  `db:"status" json:"status"`
+	// Helpful es cuántos perfiles marcaron la reseña como útil; VotedHelpful,
+	// si el perfil que consulta es uno de ellos.
+	Helpful      int        `json:"helpful"`
+	VotedHelpful bool       `json:"voted_helpful"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+	ModeratedAt  *time.Time `db:"moderated_at" json:"moderated_at,omitempty"`
+	// Reports son las denuncias sin resolver; solo se cargan en la cola de
+	// moderación, igual que Item.
+	Reports []ReviewReport `json:"reports,omitempty"`
+	Item    *CatalogItem   `json:"item,omitempty"`
+}
+
+// ReviewReport es la denuncia de un perfil sobre una reseña.
+type ReviewReport struct {
+	ReviewID   int       `db:"review_id" json:"review_id"`
+	ProfileID  int       `db:"profile_id" json:"profile_id"`
+	Reason     string    `db:"reason" json:"reason"`
+	ReportedAt time.Time `db:"reported_at" json:"reported_at"`
+}
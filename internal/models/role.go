@@ -0,0 +1,67 @@
+// internal/models/role.go
+package models
+
+// Roles de usuario. Cada usuario tiene exactamente uno.
+const (
+	RoleViewer        = "viewer"         // cliente: solo consume contenido
+	RoleContentEditor = "content_editor" // publica contenido
+	RoleBillingAdmin  = "billing_admin"  // consulta facturación de todos
+	RoleSupport       = "support"        // atiende usuarios
+	RoleSuperAdmin    = "super_admin"    // todo, incluido asignar roles
+)
+
+// Permission es una acción protegida que los servicios verifican.
+type Permission string
+
+const (
+	PermManageContent   Permission = "content.manage"
+	PermViewUsers       Permission = "users.view"
+	PermManageRoles     Permission = "users.roles"
+	PermManageUsers     Permission = "users.manage"
+	PermRevokeSessions  Permission = "users.sessions"
+	PermViewBilling     Permission = "billing.view"
+	PermModerateReviews Permission = "reviews.moderate"
+)
+
+// rolePermissions define qué puede hacer cada rol. super_admin puede todo y
+// no figura aquí.
+var rolePermissions = map[string][]Permission{
+	RoleViewer:        {},
+	RoleContentEditor: {PermManageContent, PermModerateReviews},
+	RoleBillingAdmin:  {PermViewUsers, PermViewBilling},
+	RoleSupport:       {PermViewUsers, PermManageUsers, PermRevokeSessions, PermModerateReviews},
+}
+
+// Roles devuelve todos los roles válidos, del menos al más privilegiado.
+func Roles() []string {
+	return []string{RoleViewer, RoleContentEditor, RoleBillingAdmin, RoleSupport, RoleSuperAdmin}
+}
+
+// IsValidRole indica si role es uno de los roles conocidos.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok || role == RoleSuperAdmin
+}
+
+// RoleHasPermission indica si el rol incluye el permiso.
+func RoleHasPermission(role string, perm Permission) bool {
+	if role == RoleSuperAdmin {
+		return true
+	}
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Can indica si el usuario tiene el permiso según su rol.
+func (u *User) Can(perm Permission) bool {
+	return u != nil && RoleHasPermission(u.Role, perm)
+}
+
+// IsStaff indica si el usuario tiene algún rol de gestión.
+func (u *User) IsStaff() bool {
+	return u != nil && u.Role != RoleViewer
+}
@@ -0,0 +1,41 @@
+// internal/models/series.go
+package models
+
+import "fmt"
+
+// TypeSeries es el tipo de los contenidos audiovisuales que tienen
+// temporadas y episodios.
+const TypeSeries = "series"
+
+// Series es una serie con sus temporadas, ordenadas por número.
+type Series struct {
+	AudiovisualContent
+	Seasons []Season `json:"seasons"`
+}
+
+// Season es una temporada de una serie, con sus episodios ordenados.
+type Season struct {
+	ID       int       `db:"id" json:"id"`
+	SeriesID int       `db:"series_id" json:"series_id"`
+	Number   int       `db:"number" json:"number"`
+	Title    string    `db:"title" json:"title"`
+	Episodes []Episode `json:"episodes"`
+}
+
+// Episode es un episodio de una temporada. SeriesID y SeasonNumber vienen de
+// la temporada.
+type Episode struct {
+	ID           int    `db:"id" json:"id"`
+	SeasonID     int    `db:"season_id" json:"season_id"`
+	SeriesID     int    `json:"series_id"`
+	SeasonNumber int    `json:"season_number"`
+	Number       int    `db:"number" json:"number"`
+	Title        string `db:"title" json:"title"`
+	Duration     int    `db:"duration" json:"duration"` // minutes
+	Synopsis     string `db:"synopsis" json:"synopsis"`
+}
+
+// Code devuelve el episodio en forma corta, p. ej. T1E3.
+func (e *Episode) Code() string {
+	return fmt.Sprintf("T%dE%d", e.SeasonNumber, e.Number)
+}
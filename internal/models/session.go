@@ -0,0 +1,32 @@
+// internal/models/session.go
+package models
+
+import "time"
+
+// AuthSession es un inicio de sesión de un usuario en un cliente. Los tokens
+// no se guardan: solo sus hashes, que no se exponen.
+type AuthSession struct {
+	ID     int    `db:"id" json:"id"`
+	UserID int    `db:"user_id" json:"user_id"`
+	Client string `db:"client" json:"client"`
+	// ProfileID es el perfil elegido en esta sesión; nil hasta que se elige.
+	ProfileID        *int       `db:"profile_id" json:"profile_id,omitempty"`
+	AccessTokenHash  string     `db:"access_token_hash" json:"-"`
+	RefreshTokenHash string     `db:"refresh_token_hash" json:"-"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	LastUsedAt       time.Time  `db:"last_used_at" json:"last_used_at"`
+	ExpiresAt        time.Time  `db:"expires_at" json:"expires_at"`
+	RefreshExpiresAt time.Time  `db:"refresh_expires_at" json:"refresh_expires_at"`
+	RevokedAt        *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+}
+
+// AuthTokens son los tokens que recibe el cliente al iniciar sesión o
+// renovarla. El de acceso autentica cada petición; el de renovación sirve
+// para obtener un par nuevo cuando el de acceso vence.
+type AuthTokens struct {
+	SessionID        int       `json:"session_id"`
+	AccessToken      string    `json:"access_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -4,35 +4,62 @@ package models
 import "time"
 
 type Plan struct {
-	ID    int     `db:"id"`
-	Name  string  `db:"name"`
-	Price float64 `db:"price"`
-	MaxQuality string  `db:"max_quality"`
-	MaxDevices int     `db:"max_devices"`
+	ID          int     `db:"id" json:"id"`
+	Name        string  `db:"name" json:"name"`
+	Price       float64 `db:"price" json:"price"`
+	MaxQuality  string  `db:"max_quality" json:"max_quality"`
+	MaxDevices  int     `db:"max_devices" json:"max_devices"`
+	MaxProfiles int     `db:"max_profiles" json:"max_profiles"`
 }
 
-// Subscription representa la suscripción de un usuario a un plan.
-
+// Subscription representa un período de facturación de un usuario en un plan.
+// Cada renovación o cambio de plan crea una fila nueva; solo una está activa.
 type Subscription struct {
-	ID        int       `db:"id"`
-	UserID    int       `db:"user_id"`
-	PlanID    int       `db:"plan_id"`
-	StartDate time.Time `db:"start_date"`
-	EndDate   time.Time `db:"end_date"`
-	IsActive  bool      `db:"is_active"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID         int        `db:"id" json:"id"`
+	UserID     int        `db:"user_id" json:"user_id"`
+	PlanID     int        `db:"plan_id" json:"plan_id"`
+	StartDate  time.Time  `db:"start_date" json:"start_date"`
+	EndDate    time.Time  `db:"end_date" json:"end_date"`
+	IsActive   bool       `db:"is_active" json:"is_active"`
+	AutoRenew  bool       `db:"auto_renew" json:"auto_renew"`
+	CanceledAt *time.Time `db:"canceled_at" json:"canceled_at,omitempty"`
+	// PendingPlanID es el plan al que se pasará al renovar (cambio a un plan
+	// inferior programado); nil si no hay cambio pendiente.
+	PendingPlanID *int      `db:"pending_plan_id" json:"pending_plan_id,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Tipos de cambio de plan.
+const (
+	PlanChangeNew       = "new"       // sin período en curso: se cobra el mes completo
+	PlanChangeUpgrade   = "upgrade"   // plan superior: se cobra la diferencia prorrateada
+	PlanChangeDowngrade = "downgrade" // plan inferior: rige al terminar el período
+)
+
+// PlanChange describe el efecto de pasar a otro plan: cuánto se cobra hoy y
+// desde cuándo rige el nuevo plan.
+type PlanChange struct {
+	Kind          string    `json:"kind"`
+	CurrentPlanID int       `json:"current_plan_id"`
+	NewPlanID     int       `json:"new_plan_id"`
+	CreditCents   int64     `json:"credit_cents"` // valor no usado del plan actual
+	AmountCents   int64     `json:"amount_cents"` // a cobrar hoy
+	EffectiveAt   time.Time `json:"effective_at"`
+	PeriodEnd     time.Time `json:"period_end"`
 }
 
+// PaymentMethod es una tarjeta tokenizada. Nunca contiene el número completo
+// ni el CVV: solo el token de la pasarela y los datos para mostrarla.
 type PaymentMethod struct {
-	UserID         int       `db:"user_id"`
-	CardNumber     string    `db:"card_number"`
-	ExpirationDate string    `db:"expiration_date"`
-	CVV            string    `db:"cvv"`
-	CardHolder     string    `db:"card_holder_name"`
-	Last4          string    `db:"card_number_last4"`
-	ExpiryMonth    int       `db:"expiry_month"`
-	ExpiryYear     int       `db:"expiry_year"`
-	IsDefault      bool      `db:"is_default"`
-	CreatedAt      time.Time `db:"created_at"`
+	ID          int       `db:"id" json:"id"`
+	UserID      int       `db:"user_id" json:"user_id"`
+	CardHolder  string    `db:"card_holder_name" json:"card_holder_name"`
+	Token       string    `db:"token" json:"-"`
+	Brand       string    `db:"brand" json:"brand"`
+	Last4       string    `db:"card_number_last4" json:"last4"`
+	ExpiryMonth int       `db:"expiry_month" json:"expiry_month"`
+	ExpiryYear  int       `db:"expiry_year" json:"expiry_year"`
+	IsDefault   bool      `db:"is_default" json:"is_default"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
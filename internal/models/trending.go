@@ -0,0 +1,63 @@
+// internal/models/trending.go
+package models
+
+import "time"
+
+// SignalCompleted es una reproducción que llegó a CompletionThreshold; se
+// suma a las señales de recommendation.go para las tendencias.
+const SignalCompleted = "completed"
+
+// Engagement es una interacción con un contenido en un momento dado:
+// reproducirlo, terminarlo, guardarlo en Mi Lista o calificarlo (con Rating).
+type Engagement struct {
+	ContentRef
+	Signal string
+	Rating float64
+	At     time.Time
+}
+
+// Ventanas de las tendencias.
+const (
+	TrendingDay   = "24h"
+	TrendingWeek  = "7d"
+	TrendingMonth = "30d"
+)
+
+// TrendingWindow es el período que se mira para las tendencias. Dentro de
+// él, una interacción vale la mitad cada HalfLife, así lo más reciente pesa
+// más.
+type TrendingWindow struct {
+	Name     string
+	Span     time.Duration
+	HalfLife time.Duration
+}
+
+// TrendingWindows son las ventanas disponibles, de la más corta a la más
+// larga.
+var TrendingWindows = []TrendingWindow{
+	{Name: TrendingDay, Span: 24 * time.Hour, HalfLife: 6 * time.Hour},
+	{Name: TrendingWeek, Span: 7 * 24 * time.Hour, HalfLife: 36 * time.Hour},
+	{Name: TrendingMonth, Span: 30 * 24 * time.Hour, HalfLife: 7 * 24 * time.Hour},
+}
+
+// TrendingWindowOf devuelve la ventana con ese nombre.
+func TrendingWindowOf(name string) (TrendingWindow, bool) {
+	for _, w := range TrendingWindows {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return TrendingWindow{}, false
+}
+
+// TrendingItem es un contenido en tendencia: su puesto, su puntaje y cuántas
+// interacciones de cada tipo tuvo en la ventana.
+type TrendingItem struct {
+	CatalogItem
+	Rank        int     `json:"rank"`
+	Score       float64 `json:"score"`
+	Plays       int     `json:"plays"`
+	Completions int     `json:"completions"`
+	Favorites   int     `json:"favorites"`
+	Ratings     int     `json:"ratings"`
+}
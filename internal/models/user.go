@@ -5,14 +5,15 @@ import "time"
 
 // User represents a registered user in the system.
 type User struct {
-	ID           int       `db:"id"`
-	Name         string    `db:"name"`
-	Email        string    `db:"email"`
-	Age          int       `db:"age"`
-	PlanID       int       `db:"plan_id"`
-	AgeRating    string    `db:"age_rating"`
-	IsAdmin      bool      `db:"is_admin"`
-	CreatedAt    time.Time `db:"created_at"`
-	LastLogin    time.Time `db:"last_login"`
-	PasswordHash string    `db:"password_hash"`
+	ID           int       `db:"id" json:"id"`
+	Name         string    `db:"name" json:"name"`
+	Email        string    `db:"email" json:"email"`
+	Age          int       `db:"age" json:"age"`
+	PlanID       int       `db:"plan_id" json:"plan_id"`
+	AgeRating    string    `db:"age_rating" json:"age_rating"`
+	Country      string    `db:"country" json:"country"`
+	Role         string    `db:"role" json:"role"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	LastLogin    time.Time `db:"last_login" json:"last_login"`
+	PasswordHash string    `db:"password_hash" json:"-"`
 }
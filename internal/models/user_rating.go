@@ -0,0 +1,33 @@
+// internal/models/user_rating.go
+package models
+
+import "time"
+
+// Escala de las calificaciones de los perfiles.
+const (
+	MinUserRating = 1.0
+	MaxUserRating = 10.0
+)
+
+// UserRating es la calificación que un perfil le dio a un contenido.
+type UserRating struct {
+	ID        int `db:"id" json:"id"`
+	ProfileID int `db:"profile_id" json:"profile_id"`
+	ContentRef
+	Rating  float64   `db:"rating" json:"rating"`
+	RatedAt time.Time `db:"rated_at" json:"rated_at"`
+	// Item es la ficha del contenido; nil si ya no está en el catálogo.
+	Item *CatalogItem `json:"item,omitempty"`
+}
+
+// RatingSummary resume las calificaciones de un contenido: el promedio
+// simple, los votos, el puntaje bayesiano y cuántos votos cayeron en cada
+// punto de la escala (Histogram[0] son los de 1 a menos de 2, …,
+// Histogram[9] los de 10).
+type RatingSummary struct {
+	ContentRef
+	Average   float64 `json:"average"`
+	Count     int     `json:"count"`
+	Weighted  float64 `json:"weighted"`
+	Histogram [10]int `json:"histogram"`
+}
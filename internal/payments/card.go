@@ -0,0 +1,62 @@
+// internal/payments/card.go
+package payments
+
+import "strings"
+
+// Brand detecta la marca de la tarjeta a partir de sus primeros dígitos.
+func Brand(number string) string {
+	switch {
+	case strings.HasPrefix(number, "4"):
+		return "Visa"
+	case hasPrefixInRange(number, 2, 51, 55), hasPrefixInRange(number, 4, 2221, 2720):
+		return "Mastercard"
+	case strings.HasPrefix(number, "34"), strings.HasPrefix(number, "37"):
+		return "American Express"
+	case strings.HasPrefix(number, "6011"), strings.HasPrefix(number, "65"):
+		return "Discover"
+	default:
+		return "Desconocida"
+	}
+}
+
+// ValidLuhn comprueba el dígito verificador de un número de tarjeta.
+func ValidLuhn(number string) bool {
+	if number == "" {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// hasPrefixInRange indica si los primeros `digits` dígitos del número, como
+// entero, están entre min y max.
+func hasPrefixInRange(number string, digits, min, max int) bool {
+	if len(number) < digits {
+		return false
+	}
+	n := 0
+	for _, c := range number[:digits] {
+		if c < '0' || c > '9' {
+			return false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n >= min && n <= max
+}
@@ -0,0 +1,90 @@
+// internal/payments/gateway.go
+// Abstracción de la pasarela de pagos. La aplicación nunca guarda el número
+// completo ni el CVV de una tarjeta: se los entrega a la pasarela, que
+// devuelve un token opaco con el que se identificará la tarjeta en adelante.
+package payments
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"errors"
+)
+
+// Códigos de AppError propios de la pasarela.
+const (
+	// CodeDeclined: el emisor rechazó la operación. No se debe reintentar.
+	CodeDeclined = "PAYMENT_DECLINED"
+	// CodeUnavailable: fallo transitorio; la operación puede reintentarse.
+	CodeUnavailable = "PAYMENT_UNAVAILABLE"
+	// CodeTimeout: la pasarela no respondió a tiempo. El resultado es
+	// desconocido, por lo que no se reintenta automáticamente.
+	CodeTimeout = "PAYMENT_TIMEOUT"
+)
+
+// Card son los datos de la tarjeta tal como los ingresa el usuario. Solo
+// viven en memoria el tiempo necesario para tokenizarla.
+type Card struct {
+	Holder      string
+	Number      string
+	ExpiryMonth int
+	ExpiryYear  int
+	CVV         string
+}
+
+// CardToken es lo que se puede persistir de una tarjeta tokenizada.
+type CardToken struct {
+	Token       string
+	Brand       string
+	Last4       string
+	ExpiryMonth int
+	ExpiryYear  int
+}
+
+// Authorization es una retención de fondos pendiente de capturar o anular.
+type Authorization struct {
+	ID          string
+	Token       string
+	AmountCents int64
+}
+
+// Charge es una autorización capturada: el cobro efectivo.
+type Charge struct {
+	ID              string
+	AuthorizationID string
+	AmountCents     int64
+}
+
+// Refund es una devolución total o parcial de un cobro.
+type Refund struct {
+	ID          string
+	ChargeID    string
+	AmountCents int64
+}
+
+// Gateway es la interfaz que debe cumplir cualquier pasarela de pagos.
+// Los montos van en centavos para evitar errores de redondeo.
+type Gateway interface {
+	// Tokenize valida la tarjeta y devuelve un token reutilizable.
+	Tokenize(card Card) (*CardToken, error)
+	// Authorize retiene el monto en la tarjeta identificada por el token.
+	Authorize(token string, amountCents int64) (*Authorization, error)
+	// Capture cobra una autorización previa.
+	Capture(authorizationID string) (*Charge, error)
+	// Refund devuelve parte o todo un cobro ya capturado.
+	Refund(chargeID string, amountCents int64) (*Refund, error)
+	// Void libera una autorización que no se llegó a capturar.
+	Void(authorizationID string) error
+}
+
+// IsRetryable indica si el error es un fallo transitorio de la pasarela.
+func IsRetryable(err error) bool {
+	var appErr *apperrors.AppError
+	return errors.As(err, &appErr) && appErr.Code == CodeUnavailable
+}
+
+// ToCents convierte un precio en dólares a centavos.
+func ToCents(amount float64) int64 {
+	if amount < 0 {
+		return -int64(-amount*100 + 0.5)
+	}
+	return int64(amount*100 + 0.5)
+}
@@ -0,0 +1,262 @@
+// internal/payments/simulator.go
+package payments
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulatorConfig define qué tarjetas se comportan de forma especial. El
+// resultado depende solo del número de tarjeta, así que los escenarios de
+// fallo son reproducibles.
+type SimulatorConfig struct {
+	// DeclinedCards son rechazadas siempre en Authorize.
+	DeclinedCards []string
+	// TimeoutCards hacen que Authorize agote el tiempo de espera.
+	TimeoutCards []string
+	// FlakyCards fallan de forma transitoria el número de veces indicado
+	// antes de autorizar (para probar reintentos).
+	FlakyCards map[string]int
+	// CaptureFailCards se autorizan, pero la captura falla.
+	CaptureFailCards []string
+}
+
+// DefaultSimulatorConfig son las tarjetas de prueba documentadas en el README.
+func DefaultSimulatorConfig() SimulatorConfig {
+	return SimulatorConfig{
+		DeclinedCards:    []string{"4000000000000002"},
+		TimeoutCards:     []string{"4000000000000697"},
+		FlakyCards:       map[string]int{"4000000000000119": 2, "4000000000000259": 100},
+		CaptureFailCards: []string{"4000000000000341"},
+	}
+}
+
+type cardBehavior int
+
+const (
+	behaviorApprove cardBehavior = iota
+	behaviorDecline
+	behaviorTimeout
+	behaviorFlaky
+	behaviorCaptureFail
+)
+
+type simCard struct {
+	behavior cardBehavior
+	failures int // fallos transitorios pendientes (behaviorFlaky)
+}
+
+type simAuthorization struct {
+	Authorization
+	behavior cardBehavior
+	captured bool
+	voided   bool
+}
+
+type simCharge struct {
+	Charge
+	refundedCents int64
+}
+
+// Simulator es una pasarela en memoria para desarrollo y pruebas: valida la
+// tarjeta, emite tokens aleatorios y simula autorizaciones, capturas,
+// devoluciones y anulaciones sin contactar a ningún procesador real.
+//
+// El comportamiento se recuerda por token solo mientras el proceso vive; un
+// token emitido por una ejecución anterior se trata como tarjeta aprobada.
+type Simulator struct {
+	config SimulatorConfig
+	now    func() time.Time
+
+	mu             sync.Mutex
+	cards          map[string]*simCard
+	authorizations map[string]*simAuthorization
+	charges        map[string]*simCharge
+}
+
+// NewSimulator crea el simulador con la configuración indicada.
+func NewSimulator(config SimulatorConfig) *Simulator {
+	return &Simulator{
+		config:         config,
+		now:            time.Now,
+		cards:          make(map[string]*simCard),
+		authorizations: make(map[string]*simAuthorization),
+		charges:        make(map[string]*simCharge),
+	}
+}
+
+func (g *Simulator) Tokenize(card Card) (*CardToken, error) {
+	if len(card.Number) < 13 || len(card.Number) > 19 || !ValidLuhn(card.Number) {
+		return nil, apperrors.New("INVALID_INPUT", "número de tarjeta inválido")
+	}
+	if card.ExpiryMonth < 1 || card.ExpiryMonth > 12 {
+		return nil, apperrors.New("INVALID_INPUT", "mes de vencimiento inválido")
+	}
+	now := g.now()
+	if card.ExpiryYear < now.Year() || (card.ExpiryYear == now.Year() && card.ExpiryMonth < int(now.Month())) {
+		return nil, apperrors.New("INVALID_INPUT", "la tarjeta está vencida")
+	}
+	if len(card.CVV) < 3 || len(card.CVV) > 4 {
+		return nil, apperrors.New("INVALID_INPUT", "CVV inválido")
+	}
+
+	token, err := newID("tok")
+	if err != nil {
+		return nil, apperrors.ErrInternal(err)
+	}
+
+	g.mu.Lock()
+	g.cards[token] = g.behaviorFor(card.Number)
+	g.mu.Unlock()
+
+	return &CardToken{
+		Token:       token,
+		Brand:       Brand(card.Number),
+		Last4:       card.Number[len(card.Number)-4:],
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+	}, nil
+}
+
+func (g *Simulator) Authorize(token string, amountCents int64) (*Authorization, error) {
+	if amountCents <= 0 {
+		return nil, apperrors.New("INVALID_INPUT", "el monto a cobrar debe ser positivo")
+	}
+	if !strings.HasPrefix(token, "tok_") {
+		return nil, apperrors.New(CodeDeclined, "la tarjeta guardada no es válida; ingrésela nuevamente")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	card, ok := g.cards[token]
+	if !ok {
+		card = &simCard{behavior: behaviorApprove}
+	}
+
+	switch card.behavior {
+	case behaviorDecline:
+		return nil, apperrors.New(CodeDeclined, "pago rechazado por el emisor de la tarjeta")
+	case behaviorTimeout:
+		return nil, apperrors.New(CodeTimeout, "la pasarela de pagos no respondió a tiempo")
+	case behaviorFlaky:
+		if card.failures > 0 {
+			card.failures--
+			return nil, apperrors.New(CodeUnavailable, "la pasarela de pagos no está disponible, intente más tarde")
+		}
+	}
+
+	id, err := newID("auth")
+	if err != nil {
+		return nil, apperrors.ErrInternal(err)
+	}
+	auth := &simAuthorization{
+		Authorization: Authorization{ID: id, Token: token, AmountCents: amountCents},
+		behavior:      card.behavior,
+	}
+	g.authorizations[id] = auth
+
+	result := auth.Authorization
+	return &result, nil
+}
+
+func (g *Simulator) Capture(authorizationID string) (*Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	auth, ok := g.authorizations[authorizationID]
+	if !ok {
+		return nil, apperrors.ErrNotFound("autorización")
+	}
+	if auth.voided || auth.captured {
+		return nil, apperrors.ErrConflict("la autorización ya fue utilizada")
+	}
+	if auth.behavior == behaviorCaptureFail {
+		return nil, apperrors.New(CodeDeclined, "no se pudo capturar el pago")
+	}
+
+	id, err := newID("ch")
+	if err != nil {
+		return nil, apperrors.ErrInternal(err)
+	}
+	auth.captured = true
+	charge := &simCharge{Charge: Charge{ID: id, AuthorizationID: auth.ID, AmountCents: auth.AmountCents}}
+	g.charges[id] = charge
+
+	result := charge.Charge
+	return &result, nil
+}
+
+func (g *Simulator) Refund(chargeID string, amountCents int64) (*Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[chargeID]
+	if !ok {
+		return nil, apperrors.ErrNotFound("cobro")
+	}
+	if amountCents <= 0 || charge.refundedCents+amountCents > charge.AmountCents {
+		return nil, apperrors.New("INVALID_INPUT", "monto de devolución inválido")
+	}
+
+	id, err := newID("re")
+	if err != nil {
+		return nil, apperrors.ErrInternal(err)
+	}
+	charge.refundedCents += amountCents
+
+	return &Refund{ID: id, ChargeID: chargeID, AmountCents: amountCents}, nil
+}
+
+func (g *Simulator) Void(authorizationID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	auth, ok := g.authorizations[authorizationID]
+	if !ok {
+		return apperrors.ErrNotFound("autorización")
+	}
+	if auth.captured {
+		return apperrors.ErrConflict("la autorización ya fue capturada; use una devolución")
+	}
+	auth.voided = true
+	return nil
+}
+
+// behaviorFor busca el número en la configuración.
+func (g *Simulator) behaviorFor(number string) *simCard {
+	if n, ok := g.config.FlakyCards[number]; ok {
+		return &simCard{behavior: behaviorFlaky, failures: n}
+	}
+	switch {
+	case contains(g.config.DeclinedCards, number):
+		return &simCard{behavior: behaviorDecline}
+	case contains(g.config.TimeoutCards, number):
+		return &simCard{behavior: behaviorTimeout}
+	case contains(g.config.CaptureFailCards, number):
+		return &simCard{behavior: behaviorCaptureFail}
+	}
+	return &simCard{behavior: behaviorApprove}
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// newID genera un identificador opaco del estilo "<prefijo>_<hex>".
+func newID(prefix string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + "_" + hex.EncodeToString(b), nil
+}
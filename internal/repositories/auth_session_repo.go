@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type AuthSessionRepo interface {
+	Create(s *models.AuthSession) error
+	FindByAccessTokenHash(hash string) (*models.AuthSession, error)
+	FindByRefreshTokenHash(hash string) (*models.AuthSession, error)
+	FindActiveByUserID(userID int, now time.Time) ([]models.AuthSession, error)
+	Rotate(s *models.AuthSession) error
+	Touch(id int, now time.Time) error
+	Revoke(id int, now time.Time) error
+	RevokeAllByUserID(userID int, now time.Time) (int, error)
+	SetProfile(id, profileID int) error
+}
+
+type sqliteAuthSessionRepo struct {
+	conn *sql.DB
+}
+
+func NewAuthSessionRepo() AuthSessionRepo {
+	return &sqliteAuthSessionRepo{
+		conn: db.GetDB(),
+	}
+}
+
+const authSessionColumns = `id, user_id, client, profile_id, access_token_hash, refresh_token_hash, created_at, last_used_at, expires_at, refresh_expires_at, revoked_at`
+
+func scanAuthSession(row rowScanner) (*models.AuthSession, error) {
+	var s models.AuthSession
+	var profileID sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Client,
+		&profileID,
+		&s.AccessTokenHash,
+		&s.RefreshTokenHash,
+		&s.CreatedAt,
+		&s.LastUsedAt,
+		&s.ExpiresAt,
+		&s.RefreshExpiresAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if profileID.Valid {
+		id := int(profileID.Int64)
+		s.ProfileID = &id
+	}
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.Time
+	}
+	return &s, nil
+}
+
+func (r *sqliteAuthSessionRepo) Create(s *models.AuthSession) error {
+	query := `
+		INSERT INTO auth_sessions (user_id, client, access_token_hash, refresh_token_hash, created_at, last_used_at, expires_at, refresh_expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn.Exec(query,
+		s.UserID,
+		s.Client,
+		s.AccessTokenHash,
+		s.RefreshTokenHash,
+		s.CreatedAt.UTC(),
+		s.LastUsedAt.UTC(),
+		s.ExpiresAt.UTC(),
+		s.RefreshExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating auth session: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	s.ID = int(id)
+	return nil
+}
+
+func (r *sqliteAuthSessionRepo) findOne(column, value string) (*models.AuthSession, error) {
+	query := `SELECT ` + authSessionColumns + ` FROM auth_sessions WHERE ` + column + ` = ?`
+
+	s, err := scanAuthSession(r.conn.QueryRow(query, value))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning auth session: %w", err)
+	}
+	return s, nil
+}
+
+// FindByAccessTokenHash devuelve nil, nil si no hay sesión con ese token.
+func (r *sqliteAuthSessionRepo) FindByAccessTokenHash(hash string) (*models.AuthSession, error) {
+	return r.findOne("access_token_hash", hash)
+}
+
+// FindByRefreshTokenHash devuelve nil, nil si no hay sesión con ese token.
+func (r *sqliteAuthSessionRepo) FindByRefreshTokenHash(hash string) (*models.AuthSession, error) {
+	return r.findOne("refresh_token_hash", hash)
+}
+
+// FindActiveByUserID devuelve las sesiones no revocadas que aún se pueden
+// renovar, la más reciente primero.
+func (r *sqliteAuthSessionRepo) FindActiveByUserID(userID int, now time.Time) ([]models.AuthSession, error) {
+	query := `
+		SELECT ` + authSessionColumns + `
+		FROM auth_sessions
+		WHERE user_id = ? AND revoked_at IS NULL AND refresh_expires_at > ?
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.conn.Query(query, userID, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching auth sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.AuthSession
+	for rows.Next() {
+		s, err := scanAuthSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning auth session row: %w", err)
+		}
+		list = append(list, *s)
+	}
+	return list, rows.Err()
+}
+
+// Rotate guarda los hashes y vencimientos nuevos de una sesión renovada.
+func (r *sqliteAuthSessionRepo) Rotate(s *models.AuthSession) error {
+	query := `
+		UPDATE auth_sessions
+		SET access_token_hash = ?, refresh_token_hash = ?, last_used_at = ?, expires_at = ?, refresh_expires_at = ?
+		WHERE id = ? AND revoked_at IS NULL
+	`
+
+	_, err := r.conn.Exec(query,
+		s.AccessTokenHash,
+		s.RefreshTokenHash,
+		s.LastUsedAt.UTC(),
+		s.ExpiresAt.UTC(),
+		s.RefreshExpiresAt.UTC(),
+		s.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error rotating auth session: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteAuthSessionRepo) Touch(id int, now time.Time) error {
+	_, err := r.conn.Exec(`UPDATE auth_sessions SET last_used_at = ? WHERE id = ?`, now.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error updating auth session: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteAuthSessionRepo) Revoke(id int, now time.Time) error {
+	_, err := r.conn.Exec(`UPDATE auth_sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, now.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error revoking auth session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllByUserID cierra todas las sesiones abiertas del usuario y devuelve
+// cuántas había.
+func (r *sqliteAuthSessionRepo) RevokeAllByUserID(userID int, now time.Time) (int, error) {
+	res, err := r.conn.Exec(`UPDATE auth_sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`, now.UTC(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("error revoking auth sessions: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// SetProfile guarda el perfil elegido en la sesión.
+func (r *sqliteAuthSessionRepo) SetProfile(id, profileID int) error {
+	_, err := r.conn.Exec(`UPDATE auth_sessions SET profile_id = ? WHERE id = ?`, profileID, id)
+	if err != nil {
+		return fmt.Errorf("error updating auth session profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// internal/repositories/billing_repo.go
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// BillingRepo accede a las facturas y al libro de pagos. Solo permite
+// insertar y leer: las tablas son inmutables.
+type BillingRepo interface {
+	// CreateInvoice inserta la factura junto con sus transacciones.
+	CreateInvoice(inv *models.Invoice) error
+	AddTransaction(t *models.PaymentTransaction) error
+	FindInvoicesByUserID(userID int) ([]models.Invoice, error)
+	FindAllInvoices() ([]models.Invoice, error)
+}
+
+type sqliteBillingRepo struct {
+	conn *sql.DB
+}
+
+func NewBillingRepo() BillingRepo {
+	return &sqliteBillingRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// invoiceNumber es el número visible de la factura.
+func invoiceNumber(id int) string {
+	return fmt.Sprintf("F-%06d", id)
+}
+
+func (r *sqliteBillingRepo) CreateInvoice(inv *models.Invoice) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating invoice: %w", err)
+	}
+	defer tx.Rollback()
+
+	if inv.Currency == "" {
+		inv.Currency = "USD"
+	}
+	if inv.IssuedAt.IsZero() {
+		inv.IssuedAt = time.Now()
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO invoices (user_id, plan_id, description, amount_cents, currency, period_start, period_end, issued_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		inv.UserID,
+		inv.PlanID,
+		inv.Description,
+		inv.AmountCents,
+		inv.Currency,
+		inv.PeriodStart.UTC(),
+		inv.PeriodEnd.UTC(),
+		inv.IssuedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating invoice: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	inv.ID = int(id)
+	inv.Number = invoiceNumber(inv.ID)
+
+	for i := range inv.Transactions {
+		t := &inv.Transactions[i]
+		t.UserID = inv.UserID
+		t.InvoiceID = inv.ID
+		if err := insertTransaction(tx, t); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *sqliteBillingRepo) AddTransaction(t *models.PaymentTransaction) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error inserting payment transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTransaction(tx, t); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertTransaction(tx *sql.Tx, t *models.PaymentTransaction) error {
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO payment_transactions (user_id, invoice_id, kind, amount_cents, gateway_ref, description, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`,
+		t.UserID,
+		t.InvoiceID,
+		t.Kind,
+		t.AmountCents,
+		t.GatewayRef,
+		t.Description,
+		t.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting payment transaction: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	t.ID = int(id)
+	return nil
+}
+
+func (r *sqliteBillingRepo) FindInvoicesByUserID(userID int) ([]models.Invoice, error) {
+	return r.queryInvoices(`WHERE i.user_id = ?`, userID)
+}
+
+func (r *sqliteBillingRepo) FindAllInvoices() ([]models.Invoice, error) {
+	return r.queryInvoices(``)
+}
+
+// queryInvoices carga las facturas que cumplen el filtro y sus transacciones.
+func (r *sqliteBillingRepo) queryInvoices(where string, args ...interface{}) ([]models.Invoice, error) {
+	rows, err := r.conn.Query(`
+		SELECT i.id, i.user_id, COALESCE(u.email, ''), i.plan_id, i.description, i.amount_cents, i.currency,
+		       i.period_start, i.period_end, i.issued_at
+		FROM invoices i
+		LEFT JOIN users u ON u.id = i.user_id
+		`+where+`
+		ORDER BY i.issued_at DESC, i.id DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []models.Invoice
+	index := make(map[int]int)
+	for rows.Next() {
+		var inv models.Invoice
+		if err := rows.Scan(
+			&inv.ID,
+			&inv.UserID,
+			&inv.UserEmail,
+			&inv.PlanID,
+			&inv.Description,
+			&inv.AmountCents,
+			&inv.Currency,
+			&inv.PeriodStart,
+			&inv.PeriodEnd,
+			&inv.IssuedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning invoice row: %w", err)
+		}
+		inv.Number = invoiceNumber(inv.ID)
+		index[inv.ID] = len(invoices)
+		invoices = append(invoices, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(invoices) == 0 {
+		return invoices, nil
+	}
+
+	txRows, err := r.conn.Query(`
+		SELECT t.id, t.user_id, t.invoice_id, t.kind, t.amount_cents, t.gateway_ref, t.description, t.created_at
+		FROM payment_transactions t
+		JOIN invoices i ON i.id = t.invoice_id
+		`+where+`
+		ORDER BY t.id ASC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching payment transactions: %w", err)
+	}
+	defer txRows.Close()
+
+	for txRows.Next() {
+		var t models.PaymentTransaction
+		if err := txRows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.InvoiceID,
+			&t.Kind,
+			&t.AmountCents,
+			&t.GatewayRef,
+			&t.Description,
+			&t.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning payment transaction: %w", err)
+		}
+
+		pos, ok := index[t.InvoiceID]
+		if !ok {
+			continue
+		}
+		inv := &invoices[pos]
+		inv.Transactions = append(inv.Transactions, t)
+		if t.Kind == models.TransactionRefund {
+			inv.RefundedCents -= t.AmountCents
+		}
+	}
+
+	return invoices, txRows.Err()
+}
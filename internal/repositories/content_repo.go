@@ -3,9 +3,11 @@ package repositories
 
 import (
 	"SDGEStreaming/internal/db"
+	apperrors "SDGEStreaming/internal/errors"
 	"SDGEStreaming/internal/models"
 	"database/sql"
-	"fmt"
+	"strings"
+	"time"
 )
 
 type ContentRepo interface {
@@ -13,20 +15,33 @@ type ContentRepo interface {
 	CreateAudiovisual(content *models.AudiovisualContent) error
 	FindAudiovisualByID(id int) (*models.AudiovisualContent, error)
 	FindAllAudiovisual() ([]models.AudiovisualContent, error)
-	SearchAudiovisualByTitle(title string) ([]models.AudiovisualContent, error)
 
 	// Audio
 	CreateAudio(content *models.AudioContent) error
 	FindAudioByID(id int) (*models.AudioContent, error)
 	FindAllAudio() ([]models.AudioContent, error)
-	SearchAudioByTitle(title string) ([]models.AudioContent, error)
 
-	// Ratings
-	UpdateAverageRating(contentID int, contentType string, avg float64) error
-
-	// Filtrado por edad
-	FindAllAudiovisualAllowed(userAgeRating string) ([]models.AudiovisualContent, error)
-	FindAllAudioAllowed(userAgeRating string) ([]models.AudioContent, error)
+	// Catálogo común (vista catalog_items)
+	FindCatalogItem(ref models.ContentRef) (*models.CatalogItem, error)
+	FindCatalogItems(refs []models.ContentRef) (map[models.ContentRef]models.CatalogItem, error)
+
+	// Calidades disponibles (solo audiovisual)
+	AddRendition(rendition *models.Rendition) error
+	FindRenditions(contentID int) ([]models.Rendition, error)
+
+	// Filtrado por edad, control parental y licencia
+	FindAllAudiovisualAllowed(filter models.ContentFilter) ([]models.AudiovisualContent, error)
+	FindAllAudioAllowed(filter models.ContentFilter) ([]models.AudioContent, error)
+	QueryAudiovisual(filter models.ContentFilter, q models.CatalogQuery) (*models.Page[models.AudiovisualContent], error)
+	QueryAudio(filter models.ContentFilter, q models.CatalogQuery) (*models.Page[models.AudioContent], error)
+	FindAllowedIDs(filter models.ContentFilter, contentType string, ids []int) ([]int, error)
+	FindArtistTracks(artistID int, filter models.ContentFilter) ([]models.AudioContent, error)
+	FindAlbumTracks(albumID int, filter models.ContentFilter) ([]models.AudioContent, error)
+	FindShowEpisodes(showID int, filter models.ContentFilter) ([]models.AudioContent, error)
+
+	// Licencias (ventana de disponibilidad y países)
+	FindLicense(contentID int, contentType string) (*models.ContentLicense, error)
+	SaveLicense(license *models.ContentLicense) error
 }
 
 type sqliteContentRepo struct{}
@@ -41,8 +56,8 @@ func (r *sqliteContentRepo) CreateAudiovisual(content *models.AudiovisualContent
 	conn := db.GetDB()
 
 	query := `
-		INSERT INTO audiovisual_content (title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO audiovisual_content (title, type, genre, duration, age_rating, rating_system, synopsis, release_year, director, average_rating, is_available)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := conn.Exec(query,
@@ -51,6 +66,7 @@ func (r *sqliteContentRepo) CreateAudiovisual(content *models.AudiovisualContent
 		content.Genre,
 		content.Duration,
 		content.AgeRating,
+		content.RatingSystem,
 		content.Synopsis,
 		content.ReleaseYear,
 		content.Director,
@@ -73,7 +89,7 @@ func (r *sqliteContentRepo) FindAudiovisualByID(id int) (*models.AudiovisualCont
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available
+		SELECT id, title, type, genre, duration, age_rating, rating_system, synopsis, release_year, director, average_rating, rating_count, weighted_rating, is_available, available_from, available_until
 		FROM audiovisual_content
 		WHERE id = ?
 	`
@@ -86,14 +102,19 @@ func (r *sqliteContentRepo) FindAudiovisualByID(id int) (*models.AudiovisualCont
 		&c.Genre,
 		&c.Duration,
 		&c.AgeRating,
+		&c.RatingSystem,
 		&c.Synopsis,
 		&c.ReleaseYear,
 		&c.Director,
 		&c.AverageRating,
+		&c.RatingCount,
+		&c.WeightedRating,
 		&c.IsAvailable,
+		&c.AvailableFrom,
+		&c.AvailableUntil,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("contenido audiovisual no encontrado")
+		return nil, apperrors.ErrNotFound("contenido audiovisual")
 	}
 	if err != nil {
 		return nil, err
@@ -106,10 +127,10 @@ func (r *sqliteContentRepo) FindAllAudiovisual() ([]models.AudiovisualContent, e
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available
+		SELECT id, title, type, genre, duration, age_rating, rating_system, synopsis, release_year, director, average_rating, rating_count, weighted_rating, is_available, available_from, available_until
 		FROM audiovisual_content
 		WHERE is_available = 1
-		ORDER BY average_rating DESC
+		ORDER BY weighted_rating DESC
 	`
 
 	rows, err := conn.Query(query)
@@ -128,32 +149,76 @@ func (r *sqliteContentRepo) FindAllAudiovisual() ([]models.AudiovisualContent, e
 			&c.Genre,
 			&c.Duration,
 			&c.AgeRating,
+			&c.RatingSystem,
 			&c.Synopsis,
 			&c.ReleaseYear,
 			&c.Director,
 			&c.AverageRating,
+			&c.RatingCount,
+			&c.WeightedRating,
 			&c.IsAvailable,
+			&c.AvailableFrom,
+			&c.AvailableUntil,
 		)
 		if err != nil {
 			return nil, err
 		}
 		contents = append(contents, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return contents, nil
 }
 
-func (r *sqliteContentRepo) SearchAudiovisualByTitle(title string) ([]models.AudiovisualContent, error) {
+// FindAllAudiovisualAllowed devuelve el catálogo visible para un perfil según
+// su filtro (clasificación por edad y control parental).
+func (r *sqliteContentRepo) FindAllAudiovisualAllowed(filter models.ContentFilter) ([]models.AudiovisualContent, error) {
+	return findAudiovisualAllowed(filter, "", nil, "c.weighted_rating DESC, c.rating_count DESC", 0, 0)
+}
+
+// QueryAudiovisual devuelve una página del catálogo visible según el filtro,
+// acotada y ordenada por la consulta.
+func (r *sqliteContentRepo) QueryAudiovisual(filter models.ContentFilter, q models.CatalogQuery) (*models.Page[models.AudiovisualContent], error) {
+	scope, scopeArgs := queryClause(q)
+	total, err := countAllowed(filter, models.ContentTypeAudiovisual, scope, scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+	items, err := findAudiovisualAllowed(filter, scope, scopeArgs, sortOrder(q.Sort), q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.AudiovisualContent{}
+	}
+	return &models.Page[models.AudiovisualContent]{Items: items, Total: total, Offset: q.Offset, Limit: q.Limit}, nil
+}
+
+// findAudiovisualAllowed lista el contenido audiovisual que deja ver el
+// filtro, acotado por scope (una condición extra sobre c, opcional) y en el
+// orden indicado. Con limit en cero no pagina.
+func findAudiovisualAllowed(filter models.ContentFilter, scope string, scopeArgs []interface{}, orderBy string, limit, offset int) ([]models.AudiovisualContent, error) {
 	conn := db.GetDB()
 
+	where, args := filterClause(filter, models.ContentTypeAudiovisual)
+	if scope != "" {
+		where += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
 	query := `
-		SELECT id, title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available
-		FROM audiovisual_content
-		WHERE title LIKE ? AND is_available = 1
-		ORDER BY average_rating DESC
-	`
+		SELECT c.id, c.title, c.type, c.genre, c.duration, c.age_rating, c.rating_system, c.synopsis, c.release_year, c.director, c.average_rating, c.rating_count, c.weighted_rating, c.is_available, c.available_from, c.available_until
+		FROM audiovisual_content c
+		LEFT JOIN maturity_ratings mr ON mr.system_code = c.rating_system AND mr.code = c.age_rating
+		WHERE ` + where + `
+		ORDER BY ` + orderBy
+	if limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
 
-	rows, err := conn.Query(query, "%"+title+"%")
+	rows, err := conn.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -169,83 +234,69 @@ func (r *sqliteContentRepo) SearchAudiovisualByTitle(title string) ([]models.Aud
 			&c.Genre,
 			&c.Duration,
 			&c.AgeRating,
+			&c.RatingSystem,
 			&c.Synopsis,
 			&c.ReleaseYear,
 			&c.Director,
 			&c.AverageRating,
+			&c.RatingCount,
+			&c.WeightedRating,
 			&c.IsAvailable,
+			&c.AvailableFrom,
+			&c.AvailableUntil,
 		)
 		if err != nil {
 			return nil, err
 		}
 		contents = append(contents, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return contents, nil
 }
 
-func (r *sqliteContentRepo) FindAllAudiovisualAllowed(userAgeRating string) ([]models.AudiovisualContent, error) {
+// --- CALIDADES ---
+
+func (r *sqliteContentRepo) AddRendition(rendition *models.Rendition) error {
 	conn := db.GetDB()
 
-	var query string
-
-	// Regla simple:
-	// - Niño: solo G
-	// - Adolescente: G, PG, PG-13
-	// - Adulto: todo lo disponible
-	switch userAgeRating {
-	case "Niño":
-		query = `
-			SELECT id, title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available
-			FROM audiovisual_content
-			WHERE age_rating IN ('G') AND is_available = 1
-			ORDER BY average_rating DESC
-		`
-	case "Adolescente":
-		query = `
-			SELECT id, title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available
-			FROM audiovisual_content
-			WHERE age_rating IN ('G', 'PG', 'PG-13') AND is_available = 1
-			ORDER BY average_rating DESC
-		`
-	default: // Adulto u otros
-		query = `
-			SELECT id, title, type, genre, duration, age_rating, synopsis, release_year, director, average_rating, is_available
-			FROM audiovisual_content
-			WHERE is_available = 1
-			ORDER BY average_rating DESC
-		`
-	}
+	query := `
+		INSERT INTO audiovisual_renditions (content_id, quality, manifest_path)
+		VALUES (?, ?, ?)
+	`
 
-	rows, err := conn.Query(query)
+	_, err := conn.Exec(query, rendition.ContentID, rendition.Quality, rendition.ManifestPath)
+	return err
+}
+
+// FindRenditions devuelve las calidades de un contenido, de menor a mayor.
+func (r *sqliteContentRepo) FindRenditions(contentID int) ([]models.Rendition, error) {
+	conn := db.GetDB()
+
+	query := `
+		SELECT content_id, quality, manifest_path
+		FROM audiovisual_renditions
+		WHERE content_id = ?
+		ORDER BY CASE quality WHEN 'SD' THEN 1 WHEN 'HD' THEN 2 ELSE 3 END
+	`
+
+	rows, err := conn.Query(query, contentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var contents []models.AudiovisualContent
+	var list []models.Rendition
 	for rows.Next() {
-		var c models.AudiovisualContent
-		err := rows.Scan(
-			&c.ID,
-			&c.Title,
-			&c.Type,
-			&c.Genre,
-			&c.Duration,
-			&c.AgeRating,
-			&c.Synopsis,
-			&c.ReleaseYear,
-			&c.Director,
-			&c.AverageRating,
-			&c.IsAvailable,
-		)
-		if err != nil {
+		var rd models.Rendition
+		if err := rows.Scan(&rd.ContentID, &rd.Quality, &rd.ManifestPath); err != nil {
 			return nil, err
 		}
-		contents = append(contents, c)
+		list = append(list, rd)
 	}
-
-	return contents, nil
+	return list, rows.Err()
 }
 
 // --- AUDIO ---
@@ -254,8 +305,8 @@ func (r *sqliteContentRepo) CreateAudio(content *models.AudioContent) error {
 	conn := db.GetDB()
 
 	query := `
-		INSERT INTO audio_content (title, type, genre, duration, age_rating, artist, album, track_number, average_rating, is_available)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO audio_content (title, type, genre, duration, age_rating, rating_system, artist, album, track_number, artist_id, album_id, show_id, average_rating, is_available)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := conn.Exec(query,
@@ -264,9 +315,13 @@ func (r *sqliteContentRepo) CreateAudio(content *models.AudioContent) error {
 		content.Genre,
 		content.Duration,
 		content.AgeRating,
+		content.RatingSystem,
 		content.Artist,
 		content.Album,
 		content.TrackNumber,
+		content.ArtistID,
+		content.AlbumID,
+		content.ShowID,
 		content.AverageRating,
 		content.IsAvailable,
 	)
@@ -286,7 +341,7 @@ func (r *sqliteContentRepo) FindAudioByID(id int) (*models.AudioContent, error)
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, title, type, genre, duration, age_rating, artist, album, track_number, average_rating, is_available
+		SELECT id, title, type, genre, duration, age_rating, rating_system, artist, album, track_number, artist_id, album_id, show_id, average_rating, rating_count, weighted_rating, is_available, available_from, available_until
 		FROM audio_content
 		WHERE id = ?
 	`
@@ -299,14 +354,22 @@ func (r *sqliteContentRepo) FindAudioByID(id int) (*models.AudioContent, error)
 		&c.Genre,
 		&c.Duration,
 		&c.AgeRating,
+		&c.RatingSystem,
 		&c.Artist,
 		&c.Album,
 		&c.TrackNumber,
+		&c.ArtistID,
+		&c.AlbumID,
+		&c.ShowID,
 		&c.AverageRating,
+		&c.RatingCount,
+		&c.WeightedRating,
 		&c.IsAvailable,
+		&c.AvailableFrom,
+		&c.AvailableUntil,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("contenido de audio no encontrado")
+		return nil, apperrors.ErrNotFound("contenido de audio")
 	}
 	if err != nil {
 		return nil, err
@@ -319,10 +382,10 @@ func (r *sqliteContentRepo) FindAllAudio() ([]models.AudioContent, error) {
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, title, type, genre, duration, age_rating, artist, album, track_number, average_rating, is_available
+		SELECT id, title, type, genre, duration, age_rating, rating_system, artist, album, track_number, artist_id, album_id, show_id, average_rating, rating_count, weighted_rating, is_available, available_from, available_until
 		FROM audio_content
 		WHERE is_available = 1
-		ORDER BY average_rating DESC
+		ORDER BY weighted_rating DESC
 	`
 
 	rows, err := conn.Query(query)
@@ -341,32 +404,92 @@ func (r *sqliteContentRepo) FindAllAudio() ([]models.AudioContent, error) {
 			&c.Genre,
 			&c.Duration,
 			&c.AgeRating,
+			&c.RatingSystem,
 			&c.Artist,
 			&c.Album,
 			&c.TrackNumber,
+			&c.ArtistID,
+			&c.AlbumID,
+			&c.ShowID,
 			&c.AverageRating,
+			&c.RatingCount,
+			&c.WeightedRating,
 			&c.IsAvailable,
+			&c.AvailableFrom,
+			&c.AvailableUntil,
 		)
 		if err != nil {
 			return nil, err
 		}
 		contents = append(contents, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return contents, nil
 }
 
-func (r *sqliteContentRepo) SearchAudioByTitle(title string) ([]models.AudioContent, error) {
+// FindAllAudioAllowed es el equivalente de FindAllAudiovisualAllowed para audio.
+func (r *sqliteContentRepo) FindAllAudioAllowed(filter models.ContentFilter) ([]models.AudioContent, error) {
+	return findAudioAllowed(filter, "", nil, "c.weighted_rating DESC, c.rating_count DESC", 0, 0)
+}
+
+// QueryAudio es el equivalente de QueryAudiovisual para audio.
+func (r *sqliteContentRepo) QueryAudio(filter models.ContentFilter, q models.CatalogQuery) (*models.Page[models.AudioContent], error) {
+	scope, scopeArgs := queryClause(q)
+	total, err := countAllowed(filter, models.ContentTypeAudio, scope, scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+	items, err := findAudioAllowed(filter, scope, scopeArgs, sortOrder(q.Sort), q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.AudioContent{}
+	}
+	return &models.Page[models.AudioContent]{Items: items, Total: total, Offset: q.Offset, Limit: q.Limit}, nil
+}
+
+// FindArtistTracks devuelve las pistas del artista ordenadas por álbum y
+// número de pista.
+func (r *sqliteContentRepo) FindArtistTracks(artistID int, filter models.ContentFilter) ([]models.AudioContent, error) {
+	return findAudioAllowed(filter, "c.artist_id = ?", []interface{}{artistID}, "c.album_id, c.track_number, c.id", 0, 0)
+}
+
+// FindAlbumTracks devuelve las pistas del álbum en orden.
+func (r *sqliteContentRepo) FindAlbumTracks(albumID int, filter models.ContentFilter) ([]models.AudioContent, error) {
+	return findAudioAllowed(filter, "c.album_id = ?", []interface{}{albumID}, "c.track_number, c.id", 0, 0)
+}
+
+// FindShowEpisodes devuelve los episodios del programa, del más reciente al
+// más antiguo.
+func (r *sqliteContentRepo) FindShowEpisodes(showID int, filter models.ContentFilter) ([]models.AudioContent, error) {
+	return findAudioAllowed(filter, "c.show_id = ?", []interface{}{showID}, "c.track_number DESC, c.id DESC", 0, 0)
+}
+
+// findAudioAllowed es el equivalente de findAudiovisualAllowed para audio.
+func findAudioAllowed(filter models.ContentFilter, scope string, scopeArgs []interface{}, orderBy string, limit, offset int) ([]models.AudioContent, error) {
 	conn := db.GetDB()
 
+	where, args := filterClause(filter, models.ContentTypeAudio)
+	if scope != "" {
+		where += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
 	query := `
-		SELECT id, title, type, genre, duration, age_rating, artist, album, track_number, average_rating, is_available
-		FROM audio_content
-		WHERE title LIKE ? AND is_available = 1
-		ORDER BY average_rating DESC
-	`
+		SELECT c.id, c.title, c.type, c.genre, c.duration, c.age_rating, c.rating_system, c.artist, c.album, c.track_number, c.artist_id, c.album_id, c.show_id, c.average_rating, c.rating_count, c.weighted_rating, c.is_available, c.available_from, c.available_until
+		FROM audio_content c
+		LEFT JOIN maturity_ratings mr ON mr.system_code = c.rating_system AND mr.code = c.age_rating
+		WHERE ` + where + `
+		ORDER BY ` + orderBy
+	if limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
 
-	rows, err := conn.Query(query, "%"+title+"%")
+	rows, err := conn.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -382,89 +505,306 @@ func (r *sqliteContentRepo) SearchAudioByTitle(title string) ([]models.AudioCont
 			&c.Genre,
 			&c.Duration,
 			&c.AgeRating,
+			&c.RatingSystem,
 			&c.Artist,
 			&c.Album,
 			&c.TrackNumber,
+			&c.ArtistID,
+			&c.AlbumID,
+			&c.ShowID,
 			&c.AverageRating,
+			&c.RatingCount,
+			&c.WeightedRating,
 			&c.IsAvailable,
+			&c.AvailableFrom,
+			&c.AvailableUntil,
 		)
 		if err != nil {
 			return nil, err
 		}
 		contents = append(contents, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return contents, nil
 }
 
-func (r *sqliteContentRepo) FindAllAudioAllowed(userAgeRating string) ([]models.AudioContent, error) {
-	conn := db.GetDB()
+// filterClause traduce el filtro de un perfil a la condición WHERE de las
+// consultas de catálogo (contenido c unido a su clasificación mr). Una
+// clasificación que no está en el registro cuenta como de adultos. Solo
+// quedan los títulos con licencia vigente en el país del filtro.
+func filterClause(filter models.ContentFilter, contentType string) (string, []interface{}) {
+	now := filter.Now.UTC()
+	where := `c.is_available = 1 AND COALESCE(mr.level, ?) <= ?
+		AND (c.available_from IS NULL OR c.available_from <= ?)
+		AND (c.available_until IS NULL OR c.available_until > ?)
+		AND (NOT EXISTS (SELECT 1 FROM content_territories t WHERE t.content_id = c.id AND t.content_type = ?)
+			OR EXISTS (SELECT 1 FROM content_territories t WHERE t.content_id = c.id AND t.content_type = ? AND t.country = ?))`
+	args := []interface{}{models.MaxMaturityLevel, filter.MaxLevel, now, now, contentType, contentType, filter.Country}
+
+	if len(filter.BlockedGenres) > 0 {
+		where += " AND LOWER(c.genre) NOT IN (" + placeholders(len(filter.BlockedGenres)) + ")"
+		for _, genre := range filter.BlockedGenres {
+			args = append(args, strings.ToLower(genre))
+		}
+	}
+	if len(filter.BlockedIDs) > 0 {
+		where += " AND c.id NOT IN (" + placeholders(len(filter.BlockedIDs)) + ")"
+		for _, id := range filter.BlockedIDs {
+			args = append(args, id)
+		}
+	}
+	return where, args
+}
 
-	var query string
-
-	// Regla simple:
-	// - Niño, Adolescente: solo General
-	// - Adulto: General y Explicit
-	switch userAgeRating {
-	case "Niño", "Adolescente":
-		query = `
-			SELECT id, title, type, genre, duration, age_rating, artist, album, track_number, average_rating, is_available
-			FROM audio_content
-			WHERE age_rating = 'General' AND is_available = 1
-			ORDER BY average_rating DESC
-		`
-	default: // Adulto
-		query = `
-			SELECT id, title, type, genre, duration, age_rating, artist, album, track_number, average_rating, is_available
-			FROM audio_content
-			WHERE is_available = 1
-			ORDER BY average_rating DESC
-		`
+// FindAllowedIDs devuelve, de ids, los del catálogo indicado que deja ver el
+// filtro.
+func (r *sqliteContentRepo) FindAllowedIDs(filter models.ContentFilter, contentType string, ids []int) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	where, args := filterClause(filter, contentType)
+	where += " AND c.id IN (" + placeholders(len(ids)) + ")"
+	for _, id := range ids {
+		args = append(args, id)
 	}
 
-	rows, err := conn.Query(query)
+	rows, err := db.GetDB().Query(`
+		SELECT c.id
+		FROM `+contentTable(contentType)+` c
+		LEFT JOIN maturity_ratings mr ON mr.system_code = c.rating_system AND mr.code = c.age_rating
+		WHERE `+where, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var contents []models.AudioContent
+	var allowed []int
 	for rows.Next() {
-		var c models.AudioContent
-		err := rows.Scan(
-			&c.ID,
-			&c.Title,
-			&c.Type,
-			&c.Genre,
-			&c.Duration,
-			&c.AgeRating,
-			&c.Artist,
-			&c.Album,
-			&c.TrackNumber,
-			&c.AverageRating,
-			&c.IsAvailable,
-		)
-		if err != nil {
+		var id int
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		contents = append(contents, c)
+		allowed = append(allowed, id)
 	}
+	return allowed, rows.Err()
+}
 
-	return contents, nil
+// countAllowed cuenta el contenido del catálogo que deja ver el filtro,
+// acotado por scope como en findAudiovisualAllowed.
+func countAllowed(filter models.ContentFilter, contentType, scope string, scopeArgs []interface{}) (int, error) {
+	where, args := filterClause(filter, contentType)
+	if scope != "" {
+		where += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	query := `
+		SELECT COUNT(*)
+		FROM ` + contentTable(contentType) + ` c
+		LEFT JOIN maturity_ratings mr ON mr.system_code = c.rating_system AND mr.code = c.age_rating
+		WHERE ` + where
+
+	var total int
+	if err := db.GetDB().QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// queryClause traduce los filtros de una consulta de catálogo a una
+// condición sobre c; vacía si la consulta no filtra nada. El año de estreno
+// solo existe en el catálogo audiovisual.
+func queryClause(q models.CatalogQuery) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+
+	if q.Genre != "" {
+		add("LOWER(c.genre) = ?", strings.ToLower(q.Genre))
+	}
+	if q.Kind != "" {
+		add("c.type = ?", q.Kind)
+	}
+	if q.YearFrom > 0 {
+		add("c.release_year >= ?", q.YearFrom)
+	}
+	if q.YearTo > 0 {
+		add("c.release_year <= ?", q.YearTo)
+	}
+	if q.MinDuration > 0 {
+		add("c.duration >= ?", q.MinDuration)
+	}
+	if q.MaxDuration > 0 {
+		add("c.duration <= ?", q.MaxDuration)
+	}
+	if q.MinRating > 0 {
+		add("c.average_rating >= ?", q.MinRating)
+	}
+	if q.AgeRating != "" {
+		add("c.age_rating = ?", q.AgeRating)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// catalogOrders traduce cada orden de models.CatalogSorts a su ORDER BY. El
+// id desempata para que las páginas no se solapen.
+var catalogOrders = map[string]string{
+	models.SortRating:   "c.weighted_rating DESC, c.rating_count DESC, c.id",
+	models.SortTitle:    "c.title COLLATE NOCASE, c.id",
+	models.SortNewest:   "c.release_year DESC, c.id DESC",
+	models.SortRecent:   "c.id DESC",
+	models.SortDuration: "c.duration, c.id",
+}
+
+// sortOrder devuelve el ORDER BY de un orden; el de calificación si no lo
+// conoce.
+func sortOrder(sort string) string {
+	if order, ok := catalogOrders[sort]; ok {
+		return order
+	}
+	return catalogOrders[models.SortRating]
+}
+
+// placeholders devuelve "?, ?, ..." con n marcadores.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
 }
 
-// --- RATINGS ---
+// --- LICENCIAS ---
 
-func (r *sqliteContentRepo) UpdateAverageRating(contentID int, contentType string, avg float64) error {
+// contentTable devuelve la tabla del catálogo indicado.
+func contentTable(contentType string) string {
+	if c, ok := models.CatalogOf(contentType); ok {
+		return c.Table
+	}
+	return "audiovisual_content"
+}
+
+func (r *sqliteContentRepo) FindLicense(contentID int, contentType string) (*models.ContentLicense, error) {
 	conn := db.GetDB()
 
-	var query string
-	if contentType == "audiovisual" {
-		query = `UPDATE audiovisual_content SET average_rating = ? WHERE id = ?`
-	} else {
-		query = `UPDATE audio_content SET average_rating = ? WHERE id = ?`
+	license := &models.ContentLicense{ContentID: contentID, ContentType: contentType, Countries: []string{}}
+	query := `SELECT available_from, available_until FROM ` + contentTable(contentType) + ` WHERE id = ?`
+	err := conn.QueryRow(query, contentID).Scan(&license.AvailableFrom, &license.AvailableUntil)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.ErrNotFound("contenido")
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := conn.Exec(query, avg, contentID)
-	return err
+	rows, err := conn.Query(`
+		SELECT country
+		FROM content_territories
+		WHERE content_id = ? AND content_type = ?
+		ORDER BY country
+	`, contentID, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var country string
+		if err := rows.Scan(&country); err != nil {
+			return nil, err
+		}
+		license.Countries = append(license.Countries, country)
+	}
+	return license, rows.Err()
+}
+
+// SaveLicense reemplaza la ventana y los países del contenido.
+func (r *sqliteContentRepo) SaveLicense(license *models.ContentLicense) error {
+	tx, err := db.GetDB().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE ` + contentTable(license.ContentType) + ` SET available_from = ?, available_until = ? WHERE id = ?`
+	if _, err := tx.Exec(query, utcOrNil(license.AvailableFrom), utcOrNil(license.AvailableUntil), license.ContentID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM content_territories WHERE content_id = ? AND content_type = ?`, license.ContentID, license.ContentType); err != nil {
+		return err
+	}
+	for _, country := range license.Countries {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO content_territories (content_id, content_type, country)
+			VALUES (?, ?, ?)
+		`, license.ContentID, license.ContentType, country)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// utcOrNil guarda las fechas en UTC y nil como NULL.
+func utcOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// --- CATÁLOGO COMÚN ---
+
+const catalogSelect = `
+	SELECT content_type, content_id, title, kind, genre, duration, age_rating, rating_system, COALESCE(credit, ''), average_rating, rating_count, weighted_rating, is_available
+	FROM catalog_items
+`
+
+func scanCatalogItem(row interface{ Scan(...interface{}) error }) (models.CatalogItem, error) {
+	var c models.CatalogItem
+	err := row.Scan(&c.ContentType, &c.ContentID, &c.Title, &c.Kind, &c.Genre, &c.Duration, &c.AgeRating, &c.RatingSystem, &c.Credit, &c.AverageRating, &c.RatingCount, &c.WeightedRating, &c.IsAvailable)
+	return c, err
+}
+
+// FindCatalogItem devuelve nil, nil si el contenido no existe.
+func (r *sqliteContentRepo) FindCatalogItem(ref models.ContentRef) (*models.CatalogItem, error) {
+	item, err := scanCatalogItem(db.GetDB().QueryRow(catalogSelect+`WHERE content_type = ? AND content_id = ?`, ref.ContentType, ref.ContentID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindCatalogItems devuelve las fichas de los contenidos indicados; los que
+// no existen no aparecen en el mapa.
+func (r *sqliteContentRepo) FindCatalogItems(refs []models.ContentRef) (map[models.ContentRef]models.CatalogItem, error) {
+	items := make(map[models.ContentRef]models.CatalogItem, len(refs))
+	if len(refs) == 0 {
+		return items, nil
+	}
+
+	conditions := make([]string, len(refs))
+	args := make([]interface{}, 0, 2*len(refs))
+	for i, ref := range refs {
+		conditions[i] = "(content_type = ? AND content_id = ?)"
+		args = append(args, ref.ContentType, ref.ContentID)
+	}
+	rows, err := db.GetDB().Query(catalogSelect+`WHERE `+strings.Join(conditions, " OR "), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCatalogItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items[item.ContentRef] = item
+	}
+	return items, rows.Err()
 }
@@ -2,15 +2,19 @@ package repositories
 
 import (
 	"SDGEStreaming/internal/db"
+	apperrors "SDGEStreaming/internal/errors"
 	"SDGEStreaming/internal/models"
 	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/mattn/go-sqlite3"
 )
 
 type FavoriteRepo interface {
 	Create(f *models.Favorite) error
-	Delete(userID, contentID int, contentType string) error
-	FindByUserID(userID int) ([]models.Favorite, error)
+	Delete(profileID int, ref models.ContentRef) error
+	FindByProfileID(profileID int) ([]models.Favorite, error)
 }
 
 type sqliteFavoriteRepo struct {
@@ -25,11 +29,15 @@ func NewFavoriteRepo() FavoriteRepo {
 
 func (r *sqliteFavoriteRepo) Create(f *models.Favorite) error {
 	query := `
-		INSERT INTO favorites (user_id, content_id, content_type)
+		INSERT INTO favorites (profile_id, content_id, content_type)
 		VALUES (?, ?, ?)
 	`
 
-	_, err := r.conn.Exec(query, f.UserID, f.ContentID, f.ContentType)
+	_, err := r.conn.Exec(query, f.ProfileID, f.ContentID, f.ContentType)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return apperrors.ErrConflict("el contenido ya está en tu lista")
+	}
 	if err != nil {
 		return fmt.Errorf("error adding favorite: %w", err)
 	}
@@ -37,34 +45,34 @@ func (r *sqliteFavoriteRepo) Create(f *models.Favorite) error {
 	return nil
 }
 
-func (r *sqliteFavoriteRepo) Delete(userID, contentID int, contentType string) error {
+func (r *sqliteFavoriteRepo) Delete(profileID int, ref models.ContentRef) error {
 	query := `
 		DELETE FROM favorites
-		WHERE user_id = ? AND content_id = ? AND content_type = ?
+		WHERE profile_id = ? AND content_id = ? AND content_type = ?
 	`
 
-	res, err := r.conn.Exec(query, userID, contentID, contentType)
+	res, err := r.conn.Exec(query, profileID, ref.ContentID, ref.ContentType)
 	if err != nil {
 		return fmt.Errorf("error deleting favorite: %w", err)
 	}
 
 	rows, _ := res.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("favorite not found")
+		return apperrors.ErrNotFound("favorito")
 	}
 
 	return nil
 }
 
-func (r *sqliteFavoriteRepo) FindByUserID(userID int) ([]models.Favorite, error) {
+func (r *sqliteFavoriteRepo) FindByProfileID(profileID int) ([]models.Favorite, error) {
 	query := `
-		SELECT id, user_id, content_id, content_type, added_at
+		SELECT id, profile_id, content_id, content_type, added_at
 		FROM favorites
-		WHERE user_id = ?
+		WHERE profile_id = ?
 		ORDER BY added_at DESC
 	`
 
-	rows, err := r.conn.Query(query, userID)
+	rows, err := r.conn.Query(query, profileID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching favorites: %w", err)
 	}
@@ -74,7 +82,7 @@ func (r *sqliteFavoriteRepo) FindByUserID(userID int) ([]models.Favorite, error)
 
 	for rows.Next() {
 		var f models.Favorite
-		if err := rows.Scan(&f.ID, &f.UserID, &f.ContentID, &f.ContentType, &f.CreatedAt); err != nil {
+		if err := rows.Scan(&f.ID, &f.ProfileID, &f.ContentID, &f.ContentType, &f.CreatedAt); err != nil {
 			return nil, fmt.Errorf("error scanning favorite row: %w", err)
 		}
 		favorites = append(favorites, f)
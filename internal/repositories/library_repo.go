@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// LibraryRepo guarda los artistas, álbumes y programas de podcast, y los
+// artistas que sigue cada perfil.
+type LibraryRepo interface {
+	// Alta idempotente por nombre (sin distinguir mayúsculas)
+	EnsureArtist(name string) (*models.Artist, error)
+	EnsureAlbum(artistID int, title string) (*models.Album, error)
+	EnsureShow(title string) (*models.Show, error)
+
+	FindArtists() ([]models.Artist, error)
+	FindArtistByID(id int) (*models.Artist, error)
+	FindAlbumsByArtist(artistID int) ([]models.Album, error)
+	FindAlbumByID(id int) (*models.Album, error)
+	FindShows() ([]models.Show, error)
+	FindShowByID(id int) (*models.Show, error)
+
+	// Artistas seguidos por perfil
+	Follow(profileID, artistID int, at time.Time) error
+	Unfollow(profileID, artistID int) error
+	FindFollowed(profileID int) ([]models.Artist, error)
+}
+
+type sqliteLibraryRepo struct {
+	conn *sql.DB
+}
+
+func NewLibraryRepo() LibraryRepo {
+	return &sqliteLibraryRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// --- ARTISTAS ---
+
+func (r *sqliteLibraryRepo) EnsureArtist(name string) (*models.Artist, error) {
+	if _, err := r.conn.Exec(`INSERT OR IGNORE INTO artists (name) VALUES (?)`, name); err != nil {
+		return nil, fmt.Errorf("error creating artist: %w", err)
+	}
+
+	var a models.Artist
+	err := r.conn.QueryRow(`
+		SELECT id, name, created_at
+		FROM artists
+		WHERE name = ?
+	`, name).Scan(&a.ID, &a.Name, &a.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning artist: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *sqliteLibraryRepo) FindArtists() ([]models.Artist, error) {
+	return r.queryArtists(`
+		SELECT id, name, created_at
+		FROM artists
+		ORDER BY name ASC
+	`)
+}
+
+// FindArtistByID devuelve nil, nil si el artista no existe.
+func (r *sqliteLibraryRepo) FindArtistByID(id int) (*models.Artist, error) {
+	var a models.Artist
+	err := r.conn.QueryRow(`
+		SELECT id, name, created_at
+		FROM artists
+		WHERE id = ?
+	`, id).Scan(&a.ID, &a.Name, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning artist: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *sqliteLibraryRepo) queryArtists(query string, args ...interface{}) ([]models.Artist, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching artists: %w", err)
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	for rows.Next() {
+		var a models.Artist
+		if err := rows.Scan(&a.ID, &a.Name, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning artist: %w", err)
+		}
+		artists = append(artists, a)
+	}
+	return artists, rows.Err()
+}
+
+// --- ÁLBUMES ---
+
+func (r *sqliteLibraryRepo) EnsureAlbum(artistID int, title string) (*models.Album, error) {
+	if _, err := r.conn.Exec(`INSERT OR IGNORE INTO albums (artist_id, title) VALUES (?, ?)`, artistID, title); err != nil {
+		return nil, fmt.Errorf("error creating album: %w", err)
+	}
+
+	album, err := r.scanAlbum(r.conn.QueryRow(albumSelect+`WHERE al.artist_id = ? AND al.title = ?`, artistID, title))
+	if err != nil {
+		return nil, fmt.Errorf("error scanning album: %w", err)
+	}
+	return album, nil
+}
+
+// albumSelect trae cada álbum con el nombre de su artista.
+const albumSelect = `
+	SELECT al.id, al.artist_id, a.name, al.title
+	FROM albums al
+	JOIN artists a ON a.id = al.artist_id
+`
+
+func (r *sqliteLibraryRepo) scanAlbum(row interface{ Scan(...interface{}) error }) (*models.Album, error) {
+	var al models.Album
+	if err := row.Scan(&al.ID, &al.ArtistID, &al.Artist, &al.Title); err != nil {
+		return nil, err
+	}
+	return &al, nil
+}
+
+// FindAlbumsByArtist devuelve la discografía del artista por título.
+func (r *sqliteLibraryRepo) FindAlbumsByArtist(artistID int) ([]models.Album, error) {
+	rows, err := r.conn.Query(albumSelect+`
+		WHERE al.artist_id = ?
+		ORDER BY al.title ASC
+	`, artistID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching albums: %w", err)
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		album, err := r.scanAlbum(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning album: %w", err)
+		}
+		albums = append(albums, *album)
+	}
+	return albums, rows.Err()
+}
+
+// FindAlbumByID devuelve nil, nil si el álbum no existe.
+func (r *sqliteLibraryRepo) FindAlbumByID(id int) (*models.Album, error) {
+	album, err := r.scanAlbum(r.conn.QueryRow(albumSelect+`WHERE al.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning album: %w", err)
+	}
+	return album, nil
+}
+
+// --- PODCASTS ---
+
+func (r *sqliteLibraryRepo) EnsureShow(title string) (*models.Show, error) {
+	if _, err := r.conn.Exec(`INSERT OR IGNORE INTO shows (title) VALUES (?)`, title); err != nil {
+		return nil, fmt.Errorf("error creating show: %w", err)
+	}
+
+	var sh models.Show
+	err := r.conn.QueryRow(`
+		SELECT id, title, created_at
+		FROM shows
+		WHERE title = ?
+	`, title).Scan(&sh.ID, &sh.Title, &sh.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning show: %w", err)
+	}
+	return &sh, nil
+}
+
+func (r *sqliteLibraryRepo) FindShows() ([]models.Show, error) {
+	rows, err := r.conn.Query(`
+		SELECT id, title, created_at
+		FROM shows
+		ORDER BY title ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching shows: %w", err)
+	}
+	defer rows.Close()
+
+	shows := []models.Show{}
+	for rows.Next() {
+		var sh models.Show
+		if err := rows.Scan(&sh.ID, &sh.Title, &sh.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning show: %w", err)
+		}
+		shows = append(shows, sh)
+	}
+	return shows, rows.Err()
+}
+
+// FindShowByID devuelve nil, nil si el programa no existe.
+func (r *sqliteLibraryRepo) FindShowByID(id int) (*models.Show, error) {
+	var sh models.Show
+	err := r.conn.QueryRow(`
+		SELECT id, title, created_at
+		FROM shows
+		WHERE id = ?
+	`, id).Scan(&sh.ID, &sh.Title, &sh.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning show: %w", err)
+	}
+	return &sh, nil
+}
+
+// --- SEGUIDOS ---
+
+// Follow no hace nada si el perfil ya sigue al artista.
+func (r *sqliteLibraryRepo) Follow(profileID, artistID int, at time.Time) error {
+	_, err := r.conn.Exec(`
+		INSERT OR IGNORE INTO artist_follows (profile_id, artist_id, followed_at)
+		VALUES (?, ?, ?)
+	`, profileID, artistID, at.UTC())
+	if err != nil {
+		return fmt.Errorf("error following artist: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteLibraryRepo) Unfollow(profileID, artistID int) error {
+	_, err := r.conn.Exec(`DELETE FROM artist_follows WHERE profile_id = ? AND artist_id = ?`, profileID, artistID)
+	if err != nil {
+		return fmt.Errorf("error unfollowing artist: %w", err)
+	}
+	return nil
+}
+
+// FindFollowed devuelve los artistas que sigue el perfil por nombre.
+func (r *sqliteLibraryRepo) FindFollowed(profileID int) ([]models.Artist, error) {
+	return r.queryArtists(`
+		SELECT a.id, a.name, a.created_at
+		FROM artist_follows f
+		JOIN artists a ON a.id = f.artist_id
+		WHERE f.profile_id = ?
+		ORDER BY a.name ASC
+	`, profileID)
+}
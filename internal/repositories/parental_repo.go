@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type ParentalRepo interface {
+	FindPIN(userID int) (*models.ParentalPIN, error)
+	SavePINHash(userID int, hash string, now time.Time) error
+	RecordPINFailure(userID, maxAttempts int, lockUntil time.Time) (*models.ParentalPIN, error)
+	ResetPINFailures(userID int) error
+	FindByProfileID(profileID int) (*models.ParentalControls, error)
+	Save(c *models.ParentalControls) error
+}
+
+type sqliteParentalRepo struct {
+	conn *sql.DB
+}
+
+func NewParentalRepo() ParentalRepo {
+	return &sqliteParentalRepo{
+		conn: db.GetDB(),
+	}
+}
+
+const parentalPINColumns = `user_id, pin_hash, failed_attempts, locked_until`
+
+func scanParentalPIN(row rowScanner) (*models.ParentalPIN, error) {
+	var p models.ParentalPIN
+	var lockedUntil sql.NullTime
+	if err := row.Scan(&p.UserID, &p.Hash, &p.FailedAttempts, &lockedUntil); err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		p.LockedUntil = &lockedUntil.Time
+	}
+	return &p, nil
+}
+
+// FindPIN devuelve nil si la cuenta no tiene PIN parental.
+func (r *sqliteParentalRepo) FindPIN(userID int) (*models.ParentalPIN, error) {
+	p, err := scanParentalPIN(r.conn.QueryRow(`SELECT `+parentalPINColumns+` FROM parental_pins WHERE user_id = ?`, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching parental pin: %w", err)
+	}
+	return p, nil
+}
+
+// SavePINHash crea o cambia el PIN; un PIN nuevo empieza sin intentos
+// fallidos ni bloqueo.
+func (r *sqliteParentalRepo) SavePINHash(userID int, hash string, now time.Time) error {
+	_, err := r.conn.Exec(`
+		INSERT INTO parental_pins (user_id, pin_hash, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			pin_hash = excluded.pin_hash,
+			updated_at = excluded.updated_at,
+			failed_attempts = 0,
+			locked_until = NULL
+	`, userID, hash, now.UTC())
+	if err != nil {
+		return fmt.Errorf("error saving parental pin: %w", err)
+	}
+	return nil
+}
+
+// RecordPINFailure suma un intento fallido en la misma sentencia que lo lee,
+// para que dos intentos simultáneos no cuenten como uno. Al llegar a
+// maxAttempts bloquea el PIN hasta lockUntil y vuelve a contar desde cero.
+func (r *sqliteParentalRepo) RecordPINFailure(userID, maxAttempts int, lockUntil time.Time) (*models.ParentalPIN, error) {
+	p, err := scanParentalPIN(r.conn.QueryRow(`
+		UPDATE parental_pins
+		SET failed_attempts = CASE WHEN failed_attempts + 1 >= ? THEN 0 ELSE failed_attempts + 1 END,
+		    locked_until = CASE WHEN failed_attempts + 1 >= ? THEN ? ELSE locked_until END
+		WHERE user_id = ?
+		RETURNING `+parentalPINColumns, maxAttempts, maxAttempts, lockUntil.UTC(), userID))
+	if err != nil {
+		return nil, fmt.Errorf("error recording parental pin failure: %w", err)
+	}
+	return p, nil
+}
+
+// ResetPINFailures olvida los intentos fallidos tras un PIN correcto.
+func (r *sqliteParentalRepo) ResetPINFailures(userID int) error {
+	_, err := r.conn.Exec(`UPDATE parental_pins SET failed_attempts = 0, locked_until = NULL WHERE user_id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("error resetting parental pin failures: %w", err)
+	}
+	return nil
+}
+
+// FindByProfileID devuelve el control parental del perfil; si nunca se
+// configuró, vuelve vacío (sin techos propios ni bloqueos).
+func (r *sqliteParentalRepo) FindByProfileID(profileID int) (*models.ParentalControls, error) {
+	c := &models.ParentalControls{
+		ProfileID:      profileID,
+		BlockedGenres:  []string{},
+		BlockedContent: []models.BlockedContent{},
+	}
+
+	var maxLevel sql.NullInt64
+	err := r.conn.QueryRow(`SELECT max_level FROM parental_controls WHERE profile_id = ?`, profileID).Scan(&maxLevel)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error fetching parental controls: %w", err)
+	}
+	if maxLevel.Valid {
+		level := int(maxLevel.Int64)
+		c.MaxLevel = &level
+	}
+
+	genres, err := r.conn.Query(`SELECT genre FROM parental_blocked_genres WHERE profile_id = ? ORDER BY genre`, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching blocked genres: %w", err)
+	}
+	defer genres.Close()
+	for genres.Next() {
+		var genre string
+		if err := genres.Scan(&genre); err != nil {
+			return nil, fmt.Errorf("error scanning blocked genre: %w", err)
+		}
+		c.BlockedGenres = append(c.BlockedGenres, genre)
+	}
+	if err := genres.Err(); err != nil {
+		return nil, err
+	}
+
+	titles, err := r.conn.Query(`
+		SELECT content_id, content_type
+		FROM parental_blocked_content
+		WHERE profile_id = ?
+		ORDER BY content_type, content_id
+	`, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching blocked content: %w", err)
+	}
+	defer titles.Close()
+	for titles.Next() {
+		var b models.BlockedContent
+		if err := titles.Scan(&b.ContentID, &b.ContentType); err != nil {
+			return nil, fmt.Errorf("error scanning blocked content: %w", err)
+		}
+		c.BlockedContent = append(c.BlockedContent, b)
+	}
+	return c, titles.Err()
+}
+
+// Save reemplaza el control parental del perfil por el indicado.
+func (r *sqliteParentalRepo) Save(c *models.ParentalControls) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error saving parental controls: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO parental_controls (profile_id, max_level)
+		VALUES (?, ?)
+		ON CONFLICT(profile_id) DO UPDATE SET max_level = excluded.max_level
+	`, c.ProfileID, c.MaxLevel)
+	if err != nil {
+		return fmt.Errorf("error saving parental controls: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM parental_blocked_genres WHERE profile_id = ?`, c.ProfileID); err != nil {
+		return fmt.Errorf("error saving blocked genres: %w", err)
+	}
+	for _, genre := range c.BlockedGenres {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO parental_blocked_genres (profile_id, genre) VALUES (?, ?)`, c.ProfileID, genre); err != nil {
+			return fmt.Errorf("error saving blocked genres: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM parental_blocked_content WHERE profile_id = ?`, c.ProfileID); err != nil {
+		return fmt.Errorf("error saving blocked content: %w", err)
+	}
+	for _, b := range c.BlockedContent {
+		_, err := tx.Exec(`
+			INSERT OR IGNORE INTO parental_blocked_content (profile_id, content_id, content_type)
+			VALUES (?, ?, ?)
+		`, c.ProfileID, b.ContentID, b.ContentType)
+		if err != nil {
+			return fmt.Errorf("error saving blocked content: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
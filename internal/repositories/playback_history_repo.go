@@ -9,9 +9,16 @@ import (
 
 type PlaybackHistoryRepo interface {
 	Create(history *models.PlaybackHistory) error
-	UpdateProgress(userID, contentID int, contentType string, progress int) error
-	FindByUserID(userID int) ([]models.PlaybackHistory, error)
-	FindContinueWatching(userID int) ([]models.PlaybackHistory, error)
+	FindByID(id int) (*models.PlaybackHistory, error)
+	// UpdateProgress actualiza solo la entrada indicada, que es una
+	// reproducción.
+	UpdateProgress(id int, progress int, completed bool) error
+	FindByProfileID(profileID int) ([]models.PlaybackHistory, error)
+
+	// Punto de reanudación: uno por perfil y título
+	SaveResumePoint(point *models.ResumePoint) error
+	FindResumePoint(profileID int, ref models.ContentRef) (*models.ResumePoint, error)
+	FindContinueWatching(profileID int) ([]models.ResumePoint, error)
 }
 
 type sqlitePlaybackHistoryRepo struct {
@@ -26,26 +33,44 @@ func NewPlaybackHistoryRepo() PlaybackHistoryRepo {
 
 func (r *sqlitePlaybackHistoryRepo) Create(h *models.PlaybackHistory) error {
 	query := `
-		INSERT INTO playback_history (user_id, content_id, content_type, progress_seconds)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO playback_history (profile_id, content_id, content_type, episode_id, progress_seconds)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id, watched_at
 	`
 
-	_, err := r.conn.Exec(query, h.UserID, h.ContentID, h.ContentType, h.Progress)
+	err := r.conn.QueryRow(query, h.ProfileID, h.ContentID, h.ContentType, h.EpisodeID, h.Progress).Scan(&h.ID, &h.WatchedAt)
 	if err != nil {
 		return fmt.Errorf("error inserting playback history: %w", err)
 	}
-
 	return nil
 }
 
-func (r *sqlitePlaybackHistoryRepo) UpdateProgress(userID, contentID int, contentType string, progress int) error {
+func (r *sqlitePlaybackHistoryRepo) FindByID(id int) (*models.PlaybackHistory, error) {
+	query := `
+		SELECT id, profile_id, content_id, content_type, episode_id, progress_seconds, completed, watched_at
+		FROM playback_history
+		WHERE id = ?
+	`
+
+	var h models.PlaybackHistory
+	err := r.conn.QueryRow(query, id).Scan(&h.ID, &h.ProfileID, &h.ContentID, &h.ContentType, &h.EpisodeID, &h.Progress, &h.Completed, &h.WatchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching playback history entry: %w", err)
+	}
+	return &h, nil
+}
+
+func (r *sqlitePlaybackHistoryRepo) UpdateProgress(id int, progress int, completed bool) error {
 	query := `
 		UPDATE playback_history
-		SET progress_seconds = ?, watched_at = CURRENT_TIMESTAMP
-		WHERE user_id = ? AND content_id = ? AND content_type = ?
+		SET progress_seconds = ?, completed = ?, watched_at = CURRENT_TIMESTAMP
+		WHERE id = ?
 	`
 
-	res, err := r.conn.Exec(query, progress, userID, contentID, contentType)
+	res, err := r.conn.Exec(query, progress, completed, id)
 	if err != nil {
 		return fmt.Errorf("error updating playback progress: %w", err)
 	}
@@ -58,15 +83,15 @@ func (r *sqlitePlaybackHistoryRepo) UpdateProgress(userID, contentID int, conten
 	return nil
 }
 
-func (r *sqlitePlaybackHistoryRepo) FindByUserID(userID int) ([]models.PlaybackHistory, error) {
+func (r *sqlitePlaybackHistoryRepo) FindByProfileID(profileID int) ([]models.PlaybackHistory, error) {
 	query := `
-		SELECT id, user_id, content_id, content_type, progress_seconds, watched_at
+		SELECT id, profile_id, content_id, content_type, episode_id, progress_seconds, completed, watched_at
 		FROM playback_history
-		WHERE user_id = ?
+		WHERE profile_id = ?
 		ORDER BY watched_at DESC
 	`
 
-	rows, err := r.conn.Query(query, userID)
+	rows, err := r.conn.Query(query, profileID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching playback history: %w", err)
 	}
@@ -76,7 +101,7 @@ func (r *sqlitePlaybackHistoryRepo) FindByUserID(userID int) ([]models.PlaybackH
 
 	for rows.Next() {
 		var h models.PlaybackHistory
-		if err := rows.Scan(&h.ID, &h.UserID, &h.ContentID, &h.ContentType, &h.Progress, &h.WatchedAt); err != nil {
+		if err := rows.Scan(&h.ID, &h.ProfileID, &h.ContentID, &h.ContentType, &h.EpisodeID, &h.Progress, &h.Completed, &h.WatchedAt); err != nil {
 			return nil, fmt.Errorf("error scanning playback history: %w", err)
 		}
 		history = append(history, h)
@@ -85,31 +110,84 @@ func (r *sqlitePlaybackHistoryRepo) FindByUserID(userID int) ([]models.PlaybackH
 	return history, nil
 }
 
-func (r *sqlitePlaybackHistoryRepo) FindContinueWatching(userID int) ([]models.PlaybackHistory, error) {
+// SaveResumePoint guarda la posición del perfil en el título, reemplazando
+// la anterior.
+func (r *sqlitePlaybackHistoryRepo) SaveResumePoint(p *models.ResumePoint) error {
 	query := `
-		SELECT id, user_id, content_id, content_type, progress_seconds, watched_at
-		FROM playback_history
-		WHERE user_id = ?
-		AND progress_seconds > 0
-		ORDER BY watched_at DESC
+		INSERT INTO playback_progress (profile_id, content_id, content_type, episode_id, position_seconds, duration_seconds, completed, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(profile_id, content_id, content_type) DO UPDATE SET
+			episode_id = excluded.episode_id,
+			position_seconds = excluded.position_seconds,
+			duration_seconds = excluded.duration_seconds,
+			completed = excluded.completed,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.conn.Exec(query, p.ProfileID, p.ContentID, p.ContentType, p.EpisodeID, p.Position, p.Duration, p.Completed, p.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("error saving resume point: %w", err)
+	}
+	return nil
+}
+
+const resumePointColumns = `profile_id, content_id, content_type, episode_id, position_seconds, duration_seconds, completed, updated_at`
+
+func scanResumePoint(row interface{ Scan(...interface{}) error }) (*models.ResumePoint, error) {
+	var p models.ResumePoint
+	err := row.Scan(&p.ProfileID, &p.ContentID, &p.ContentType, &p.EpisodeID, &p.Position, &p.Duration, &p.Completed, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// FindResumePoint devuelve dónde dejó el perfil el título, o nil, nil si
+// nunca lo reprodujo.
+func (r *sqlitePlaybackHistoryRepo) FindResumePoint(profileID int, ref models.ContentRef) (*models.ResumePoint, error) {
+	query := `
+		SELECT ` + resumePointColumns + `
+		FROM playback_progress
+		WHERE profile_id = ? AND content_id = ? AND content_type = ?
+	`
+
+	p, err := scanResumePoint(r.conn.QueryRow(query, profileID, ref.ContentID, ref.ContentType))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching resume point: %w", err)
+	}
+	return p, nil
+}
+
+// FindContinueWatching devuelve los títulos que el perfil dejó a medias, del
+// más reciente al más antiguo.
+func (r *sqlitePlaybackHistoryRepo) FindContinueWatching(profileID int) ([]models.ResumePoint, error) {
+	query := `
+		SELECT ` + resumePointColumns + `
+		FROM playback_progress
+		WHERE profile_id = ?
+		AND position_seconds > 0 AND completed = 0
+		ORDER BY updated_at DESC
 		LIMIT 20
 	`
 
-	rows, err := r.conn.Query(query, userID)
+	rows, err := r.conn.Query(query, profileID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching continue-watching list: %w", err)
 	}
 	defer rows.Close()
 
-	var history []models.PlaybackHistory
+	var points []models.ResumePoint
 
 	for rows.Next() {
-		var h models.PlaybackHistory
-		if err := rows.Scan(&h.ID, &h.UserID, &h.ContentID, &h.ContentType, &h.Progress, &h.WatchedAt); err != nil {
+		p, err := scanResumePoint(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning continue-watching rows: %w", err)
 		}
-		history = append(history, h)
+		points = append(points, *p)
 	}
 
-	return history, nil
+	return points, rows.Err()
 }
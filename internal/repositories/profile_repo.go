@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+type ProfileRepo interface {
+	Create(p *models.Profile) error
+	FindByID(id int) (*models.Profile, error)
+	FindByUserID(userID int) ([]models.Profile, error)
+	Delete(id int) error
+}
+
+type sqliteProfileRepo struct {
+	conn *sql.DB
+}
+
+func NewProfileRepo() ProfileRepo {
+	return &sqliteProfileRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// insertProfile crea el perfil dentro de la transacción; UserRepo.Create lo
+// usa para el perfil principal de cada cuenta nueva.
+func insertProfile(tx *sql.Tx, p *models.Profile) error {
+	result, err := tx.Exec(`
+		INSERT INTO profiles (user_id, name, age_rating, created_at)
+		VALUES (?, ?, ?, ?)
+	`, p.UserID, p.Name, p.AgeRating, p.CreatedAt.UTC())
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return apperrors.ErrConflict("ya existe un perfil con ese nombre")
+	}
+	if err != nil {
+		return fmt.Errorf("error creating profile: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID = int(id)
+	return nil
+}
+
+func (r *sqliteProfileRepo) Create(p *models.Profile) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating profile: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertProfile(tx, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByID devuelve nil, nil si el perfil no existe.
+func (r *sqliteProfileRepo) FindByID(id int) (*models.Profile, error) {
+	query := `
+		SELECT id, user_id, name, age_rating, created_at
+		FROM profiles
+		WHERE id = ?
+	`
+
+	var p models.Profile
+	err := r.conn.QueryRow(query, id).Scan(&p.ID, &p.UserID, &p.Name, &p.AgeRating, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning profile: %w", err)
+	}
+	return &p, nil
+}
+
+// FindByUserID devuelve los perfiles de la cuenta; el primero es el principal.
+func (r *sqliteProfileRepo) FindByUserID(userID int) ([]models.Profile, error) {
+	query := `
+		SELECT id, user_id, name, age_rating, created_at
+		FROM profiles
+		WHERE user_id = ?
+		ORDER BY id ASC
+	`
+
+	rows, err := r.conn.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.Profile
+	for rows.Next() {
+		var p models.Profile
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name, &p.AgeRating, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning profile row: %w", err)
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// Delete borra el perfil y lo quita de las sesiones que lo tenían elegido.
+// Sus favoritos, historial, calificaciones (con sus reseñas), control
+// parental y artistas seguidos se borran en cascada.
+func (r *sqliteProfileRepo) Delete(id int) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error deleting profile: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`UPDATE auth_sessions SET profile_id = NULL WHERE profile_id = ?`,
+		`DELETE FROM profiles WHERE id = ?`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return fmt.Errorf("error deleting profile: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// RatingRepo es el registro de sistemas de clasificación por edad.
+type RatingRepo interface {
+	CreateSystem(s *models.RatingSystem) error
+	FindSystems() ([]models.RatingSystem, error)
+	FindSystem(code string) (*models.RatingSystem, error)
+	FindRating(systemCode, code string) (*models.MaturityRating, error)
+}
+
+type sqliteRatingRepo struct {
+	conn *sql.DB
+}
+
+func NewRatingRepo() RatingRepo {
+	return &sqliteRatingRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// CreateSystem guarda el sistema junto con sus clasificaciones.
+func (r *sqliteRatingRepo) CreateSystem(s *models.RatingSystem) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating rating system: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO rating_systems (code, name, content_type, country)
+		VALUES (?, ?, ?, ?)
+	`, s.Code, s.Name, s.ContentType, nullString(s.Country))
+	if isPrimaryKeyConflict(err) {
+		return apperrors.ErrConflict(fmt.Sprintf("ya existe el sistema de clasificación %s", s.Code))
+	}
+	if err != nil {
+		return fmt.Errorf("error creating rating system: %w", err)
+	}
+
+	for _, rating := range s.Ratings {
+		_, err := tx.Exec(`
+			INSERT INTO maturity_ratings (system_code, code, level, description)
+			VALUES (?, ?, ?, ?)
+		`, s.Code, rating.Code, rating.Level, rating.Description)
+		if isPrimaryKeyConflict(err) {
+			return apperrors.ErrConflict(fmt.Sprintf("la clasificación %s está repetida", rating.Code))
+		}
+		if err != nil {
+			return fmt.Errorf("error creating maturity rating: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *sqliteRatingRepo) FindSystems() ([]models.RatingSystem, error) {
+	rows, err := r.conn.Query(`
+		SELECT code, name, content_type, country
+		FROM rating_systems
+		ORDER BY content_type, code
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching rating systems: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.RatingSystem
+	for rows.Next() {
+		var s models.RatingSystem
+		var country sql.NullString
+		if err := rows.Scan(&s.Code, &s.Name, &s.ContentType, &country); err != nil {
+			return nil, fmt.Errorf("error scanning rating system: %w", err)
+		}
+		s.Country = country.String
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		if list[i].Ratings, err = r.findRatings(list[i].Code); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// FindSystem devuelve nil, nil si el sistema no existe.
+func (r *sqliteRatingRepo) FindSystem(code string) (*models.RatingSystem, error) {
+	var s models.RatingSystem
+	var country sql.NullString
+	err := r.conn.QueryRow(`
+		SELECT code, name, content_type, country
+		FROM rating_systems
+		WHERE code = ?
+	`, code).Scan(&s.Code, &s.Name, &s.ContentType, &country)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching rating system: %w", err)
+	}
+	s.Country = country.String
+
+	if s.Ratings, err = r.findRatings(s.Code); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FindRating devuelve nil, nil si el sistema no tiene esa clasificación.
+func (r *sqliteRatingRepo) FindRating(systemCode, code string) (*models.MaturityRating, error) {
+	var m models.MaturityRating
+	err := r.conn.QueryRow(`
+		SELECT system_code, code, level, description
+		FROM maturity_ratings
+		WHERE system_code = ? AND code = ?
+	`, systemCode, code).Scan(&m.SystemCode, &m.Code, &m.Level, &m.Description)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching maturity rating: %w", err)
+	}
+	return &m, nil
+}
+
+// findRatings devuelve las clasificaciones del sistema, de menor a mayor nivel.
+func (r *sqliteRatingRepo) findRatings(systemCode string) ([]models.MaturityRating, error) {
+	rows, err := r.conn.Query(`
+		SELECT system_code, code, level, description
+		FROM maturity_ratings
+		WHERE system_code = ?
+		ORDER BY level, code
+	`, systemCode)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching maturity ratings: %w", err)
+	}
+	defer rows.Close()
+
+	list := []models.MaturityRating{}
+	for rows.Next() {
+		var m models.MaturityRating
+		if err := rows.Scan(&m.SystemCode, &m.Code, &m.Level, &m.Description); err != nil {
+			return nil, fmt.Errorf("error scanning maturity rating: %w", err)
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// nullString guarda "" como NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func isPrimaryKeyConflict(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
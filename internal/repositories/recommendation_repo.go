@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+)
+
+// RecommendationRepo lee las señales de gusto de los perfiles:
+// calificaciones, Mi Lista e historial de reproducción.
+type RecommendationRepo interface {
+	FindInteractions() ([]models.Interaction, error)
+	FindInteractionsByProfileID(profileID int) ([]models.Interaction, error)
+}
+
+type sqliteRecommendationRepo struct {
+	conn *sql.DB
+}
+
+func NewRecommendationRepo() RecommendationRepo {
+	return &sqliteRecommendationRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// FindInteractions devuelve una fila por perfil, contenido y señal. Un
+// contenido reproducido varias veces (o varios episodios de una serie)
+// cuenta una sola vez.
+func (r *sqliteRecommendationRepo) FindInteractions() ([]models.Interaction, error) {
+	rows, err := r.conn.Query(`
+		SELECT profile_id, content_type, content_id, ?, rating FROM user_ratings
+		UNION ALL
+		SELECT profile_id, content_type, content_id, ?, 0 FROM favorites
+		UNION ALL
+		SELECT DISTINCT profile_id, content_type, content_id, ?, 0 FROM playback_history
+	`, models.SignalRated, models.SignalFavorite, models.SignalWatched)
+	if err != nil {
+		return nil, err
+	}
+	return scanInteractions(rows)
+}
+
+// FindInteractionsByProfileID es FindInteractions limitado a un perfil.
+func (r *sqliteRecommendationRepo) FindInteractionsByProfileID(profileID int) ([]models.Interaction, error) {
+	rows, err := r.conn.Query(`
+		SELECT profile_id, content_type, content_id, ?, rating FROM user_ratings WHERE profile_id = ?
+		UNION ALL
+		SELECT profile_id, content_type, content_id, ?, 0 FROM favorites WHERE profile_id = ?
+		UNION ALL
+		SELECT DISTINCT profile_id, content_type, content_id, ?, 0 FROM playback_history WHERE profile_id = ?
+	`, models.SignalRated, profileID, models.SignalFavorite, profileID, models.SignalWatched, profileID)
+	if err != nil {
+		return nil, err
+	}
+	return scanInteractions(rows)
+}
+
+func scanInteractions(rows *sql.Rows) ([]models.Interaction, error) {
+	defer rows.Close()
+
+	var interactions []models.Interaction
+	for rows.Next() {
+		var i models.Interaction
+		if err := rows.Scan(&i.ProfileID, &i.ContentType, &i.ContentID, &i.Signal, &i.Rating); err != nil {
+			return nil, err
+		}
+		interactions = append(interactions, i)
+	}
+	return interactions, rows.Err()
+}
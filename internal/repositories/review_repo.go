@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReviewRepo guarda las reseñas, los votos de "útil" y las denuncias.
+type ReviewRepo interface {
+	// Save crea o reemplaza la reseña de la calificación del perfil y la deja
+	// pendiente de moderación. Devuelve false si el perfil no calificó el
+	// contenido.
+	Save(profileID int, ref models.ContentRef, body string, spoiler bool, now time.Time) (bool, error)
+	FindByID(id int) (*models.Review, error)
+	FindByProfile(profileID int, ref models.ContentRef) (*models.Review, error)
+	// FindApproved devuelve las reseñas aprobadas del contenido, las más
+	// útiles primero. viewerID indica para qué perfil se calcula VotedHelpful.
+	FindApproved(ref models.ContentRef, viewerID int) ([]models.Review, error)
+	FindModerationQueue() ([]models.Review, error)
+	SetStatus(id int, status string, moderatorID int, now time.Time) error
+	Delete(id int) error
+	AddVote(reviewID, profileID int, now time.Time) error
+	RemoveVote(reviewID, profileID int) error
+	// AddReport devuelve false si el perfil ya había denunciado la reseña.
+	AddReport(report models.ReviewReport) (bool, error)
+}
+
+type sqliteReviewRepo struct {
+	conn *sql.DB
+}
+
+func NewReviewRepo() ReviewRepo {
+	return &sqliteReviewRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// reviewSelect lee la reseña junto con su calificación, el nombre del autor
+// y los votos. El primer parámetro es el perfil para VotedHelpful.
+const reviewSelect = `
+	SELECT rv.id, rv.rating_id, ur.profile_id, p.name, ur.content_id, ur.content_type, ur.rating,
+		rv.body, rv.has_spoilers, rv.status,
+		(SELECT COUNT(*) FROM review_votes v WHERE v.review_id = rv.id) AS helpful,
+		EXISTS(SELECT 1 FROM review_votes v WHERE v.review_id = rv.id AND v.profile_id = ?),
+		rv.created_at, rv.updated_at, rv.moderated_at
+	FROM reviews rv
+	JOIN user_ratings ur ON ur.id = rv.rating_id
+	JOIN profiles p ON p.id = ur.profile_id
+`
+
+func scanReview(row interface{ Scan(...interface{}) error }) (*models.Review, error) {
+	var rv models.Review
+	var moderatedAt sql.NullTime
+	err := row.Scan(&rv.ID, &rv.RatingID, &rv.ProfileID, &rv.ProfileName, &rv.ContentID, &rv.ContentType, &rv.Rating,
+		&rv.Body, &rv.Spoiler, &rv.Status, &rv.Helpful, &rv.VotedHelpful,
+		&rv.CreatedAt, &rv.UpdatedAt, &moderatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if moderatedAt.Valid {
+		rv.ModeratedAt = &moderatedAt.Time
+	}
+	return &rv, nil
+}
+
+func (r *sqliteReviewRepo) queryReviews(query string, args ...interface{}) ([]models.Review, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning review: %w", err)
+		}
+		reviews = append(reviews, *rv)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *sqliteReviewRepo) findOne(query string, args ...interface{}) (*models.Review, error) {
+	rv, err := scanReview(r.conn.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching review: %w", err)
+	}
+	return rv, nil
+}
+
+func (r *sqliteReviewRepo) Save(profileID int, ref models.ContentRef, body string, spoiler bool, now time.Time) (bool, error) {
+	res, err := r.conn.Exec(`
+		INSERT INTO reviews (rating_id, body, has_spoilers, status, created_at, updated_at)
+		SELECT id, ?, ?, ?, ?, ? FROM user_ratings
+		WHERE profile_id = ? AND content_id = ? AND content_type = ?
+		ON CONFLICT(rating_id) DO UPDATE SET
+			body = excluded.body,
+			has_spoilers = excluded.has_spoilers,
+			status = excluded.status,
+			updated_at = excluded.updated_at,
+			moderated_by = NULL,
+			moderated_at = NULL
+	`, body, spoiler, models.ReviewPending, now.UTC(), now.UTC(), profileID, ref.ContentID, ref.ContentType)
+	if err != nil {
+		return false, fmt.Errorf("error saving review: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+func (r *sqliteReviewRepo) FindByID(id int) (*models.Review, error) {
+	return r.findOne(reviewSelect+`WHERE rv.id = ?`, 0, id)
+}
+
+func (r *sqliteReviewRepo) FindByProfile(profileID int, ref models.ContentRef) (*models.Review, error) {
+	return r.findOne(reviewSelect+`WHERE ur.profile_id = ? AND ur.content_id = ? AND ur.content_type = ?`,
+		profileID, profileID, ref.ContentID, ref.ContentType)
+}
+
+func (r *sqliteReviewRepo) FindApproved(ref models.ContentRef, viewerID int) ([]models.Review, error) {
+	return r.queryReviews(reviewSelect+`
+		WHERE ur.content_id = ? AND ur.content_type = ? AND rv.status = ?
+		ORDER BY helpful DESC, rv.updated_at DESC, rv.id DESC
+	`, viewerID, ref.ContentID, ref.ContentType, models.ReviewApproved)
+}
+
+// FindModerationQueue devuelve las reseñas pendientes y las aprobadas con
+// denuncias sin resolver, con esas denuncias. Primero las más denunciadas y,
+// entre ellas, las más antiguas.
+func (r *sqliteReviewRepo) FindModerationQueue() ([]models.Review, error) {
+	reviews, err := r.queryReviews(reviewSelect+`
+		WHERE rv.status = ?
+			OR (rv.status = ? AND EXISTS(SELECT 1 FROM review_reports rr WHERE rr.review_id = rv.id AND rr.resolved = 0))
+		ORDER BY (SELECT COUNT(*) FROM review_reports rr WHERE rr.review_id = rv.id AND rr.resolved = 0) DESC,
+			rv.updated_at, rv.id
+	`, 0, models.ReviewPending, models.ReviewApproved)
+	if err != nil || len(reviews) == 0 {
+		return reviews, err
+	}
+
+	byID := make(map[int]*models.Review, len(reviews))
+	placeholders := make([]string, len(reviews))
+	args := make([]interface{}, len(reviews))
+	for i := range reviews {
+		byID[reviews[i].ID] = &reviews[i]
+		placeholders[i] = "?"
+		args[i] = reviews[i].ID
+	}
+	rows, err := r.conn.Query(`
+		SELECT review_id, profile_id, reason, reported_at
+		FROM review_reports
+		WHERE resolved = 0 AND review_id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY reported_at
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching review reports: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rr models.ReviewReport
+		if err := rows.Scan(&rr.ReviewID, &rr.ProfileID, &rr.Reason, &rr.ReportedAt); err != nil {
+			return nil, fmt.Errorf("error scanning review report: %w", err)
+		}
+		rv := byID[rr.ReviewID]
+		rv.Reports = append(rv.Reports, rr)
+	}
+	return reviews, rows.Err()
+}
+
+// SetStatus aprueba u oculta la reseña y da por resueltas sus denuncias.
+func (r *sqliteReviewRepo) SetStatus(id int, status string, moderatorID int, now time.Time) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error moderating review: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE reviews SET status = ?, moderated_by = ?, moderated_at = ? WHERE id = ?
+	`, status, moderatorID, now.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error moderating review: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE review_reports SET resolved = 1 WHERE review_id = ?`, id); err != nil {
+		return fmt.Errorf("error resolving review reports: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Delete borra la reseña; sus votos y denuncias se borran en cascada. La
+// calificación queda.
+func (r *sqliteReviewRepo) Delete(id int) error {
+	if _, err := r.conn.Exec(`DELETE FROM reviews WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting review: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteReviewRepo) AddVote(reviewID, profileID int, now time.Time) error {
+	_, err := r.conn.Exec(`
+		INSERT OR IGNORE INTO review_votes (review_id, profile_id, voted_at) VALUES (?, ?, ?)
+	`, reviewID, profileID, now.UTC())
+	if err != nil {
+		return fmt.Errorf("error saving review vote: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteReviewRepo) RemoveVote(reviewID, profileID int) error {
+	_, err := r.conn.Exec(`DELETE FROM review_votes WHERE review_id = ? AND profile_id = ?`, reviewID, profileID)
+	if err != nil {
+		return fmt.Errorf("error deleting review vote: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteReviewRepo) AddReport(report models.ReviewReport) (bool, error) {
+	res, err := r.conn.Exec(`
+		INSERT OR IGNORE INTO review_reports (review_id, profile_id, reason, reported_at) VALUES (?, ?, ?, ?)
+	`, report.ReviewID, report.ProfileID, report.Reason, report.ReportedAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("error saving review report: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// SearchRepo busca en el índice de texto completo del catálogo
+// (content_search, FTS4).
+type SearchRepo interface {
+	Search(query, contentType string, filter models.ContentFilter, limit int) ([]models.SearchResult, error)
+}
+
+type sqliteSearchRepo struct{}
+
+func NewSearchRepo() SearchRepo {
+	return &sqliteSearchRepo{}
+}
+
+// searchWeights pesa cada columna de content_search en bm25: título, sinopsis,
+// director, artista, álbum y género. content_type y content_id no se indexan.
+var searchWeights = []float64{10.0, 1.0, 4.0, 4.0, 3.0, 2.0, 0, 0}
+
+// Parámetros de bm25, los mismos que usa FTS5.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Search devuelve el contenido del catálogo indicado que coincide con query
+// (frases FTS4 separadas por espacios) y que deja ver el filtro, del más al
+// menos relevante.
+func (r *sqliteSearchRepo) Search(query, contentType string, filter models.ContentFilter, limit int) ([]models.SearchResult, error) {
+	idf, err := phraseIDF(query)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := filterClause(filter, contentType)
+	rows, err := db.GetDB().Query(`
+		SELECT ci.content_type, ci.content_id, ci.title, ci.kind, ci.genre, ci.duration, ci.age_rating, ci.rating_system, COALESCE(ci.credit, ''), ci.average_rating, ci.rating_count, ci.weighted_rating, ci.is_available,
+			matchinfo(content_search, 'pcnalx')
+		FROM content_search
+		JOIN catalog_items ci ON ci.content_type = content_search.content_type AND ci.content_id = content_search.content_id
+		JOIN `+contentTable(contentType)+` c ON c.id = ci.content_id
+		LEFT JOIN maturity_ratings mr ON mr.system_code = c.rating_system AND mr.code = c.age_rating
+		WHERE content_search MATCH ? AND content_search.content_type = ? AND `+where+`
+	`, append([]interface{}{query, contentType}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching content: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var s models.SearchResult
+		var info []byte
+		c := &s.CatalogItem
+		if err := rows.Scan(&c.ContentType, &c.ContentID, &c.Title, &c.Kind, &c.Genre, &c.Duration, &c.AgeRating, &c.RatingSystem, &c.Credit, &c.AverageRating, &c.RatingCount, &c.WeightedRating, &c.IsAvailable, &info); err != nil {
+			return nil, fmt.Errorf("error scanning search result: %w", err)
+		}
+		s.Score = bm25(info, idf, searchWeights)
+		results = append(results, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].WeightedRating > results[j].WeightedRating
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// phraseIDF calcula el idf de cada frase de la consulta como FTS5: con las
+// filas de todo el índice que la contienen en cualquier columna, que
+// matchinfo no informa.
+func phraseIDF(query string) ([]float64, error) {
+	var total float64
+	if err := db.GetDB().QueryRow(`SELECT COUNT(*) FROM content_search`).Scan(&total); err != nil {
+		return nil, fmt.Errorf("error counting search index: %w", err)
+	}
+
+	phrases := strings.Fields(query)
+	idf := make([]float64, len(phrases))
+	for i, phrase := range phrases {
+		var hits float64
+		err := db.GetDB().QueryRow(`
+			SELECT COUNT(*) FROM content_search WHERE content_search MATCH ?
+		`, phrase).Scan(&hits)
+		if err != nil {
+			return nil, fmt.Errorf("error searching content: %w", err)
+		}
+		idf[i] = math.Log((total - hits + 0.5) / (hits + 0.5))
+		if idf[i] <= 0 {
+			idf[i] = 1e-6
+		}
+	}
+	return idf, nil
+}
+
+// bm25 calcula la relevancia de una fila como la función bm25() de FTS5 pero
+// con el signo invertido (más es mejor), a partir de matchinfo(...,
+// 'pcnalx') y del idf de cada frase. Las coincidencias de cada frase se
+// suman pesadas por columna, y la longitud de la fila y la media son las de
+// todas las columnas. La única diferencia es que matchinfo da la longitud
+// media de cada columna redondeada.
+func bm25(info []byte, idf, weights []float64) float64 {
+	word := func(i int) float64 {
+		return float64(binary.NativeEndian.Uint32(info[4*i:]))
+	}
+	phrases, cols := int(word(0)), int(word(1))
+
+	var avgLen, rowLen float64
+	for col := 0; col < cols; col++ {
+		avgLen += word(3 + col)
+		rowLen += word(3 + cols + col)
+	}
+	avgLen = max(avgLen, 1)
+
+	score := 0.0
+	for p := 0; p < phrases && p < len(idf); p++ {
+		freq := 0.0
+		for col := 0; col < cols && col < len(weights); col++ {
+			freq += weights[col] * word(3+2*cols+3*(p*cols+col))
+		}
+		score += idf[p] * freq * (bm25K1 + 1) / (freq + bm25K1*(1-bm25B+bm25B*rowLen/avgLen))
+	}
+	return score
+}
@@ -0,0 +1,72 @@
+//go:build sqlite_fts5
+
+// internal/repositories/search_repo_fts5_test.go
+// Compara el bm25 calculado sobre FTS4 con el bm25() de FTS5. Solo corre con
+// `go test -tags sqlite_fts5`, que compila FTS5 en go-sqlite3.
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSearchScoresAgainstFTS5(t *testing.T) {
+	newSearchTestDB(t)
+	conn := db.GetDB()
+	_, err := conn.Exec(`
+		CREATE VIRTUAL TABLE fts5_search USING fts5(title, synopsis, director, artist, album, genre, content_type UNINDEXED, content_id UNINDEXED, tokenize = "unicode61 remove_diacritics 2");
+		INSERT INTO fts5_search SELECT title, synopsis, director, artist, album, genre, content_type, content_id FROM content_search;
+	`)
+	if err != nil {
+		t.Fatalf("crear el índice FTS5: %v", err)
+	}
+
+	for _, q := range rankingQueries {
+		t.Run(strings.Join(q.words, " "), func(t *testing.T) {
+			terms := make([]string, len(q.words))
+			for i, w := range q.words {
+				terms[i] = `"` + w + `"*`
+			}
+			rows, err := conn.Query(`
+				SELECT a.title, -bm25(fts5_search, 10.0, 1.0, 4.0, 4.0, 3.0, 2.0, 0, 0)
+				FROM fts5_search
+				JOIN audiovisual_content a ON a.id = fts5_search.content_id
+				WHERE fts5_search MATCH ? AND fts5_search.content_type = 'audiovisual'
+				ORDER BY bm25(fts5_search, 10.0, 1.0, 4.0, 4.0, 3.0, 2.0, 0, 0)
+			`, strings.Join(terms, " "))
+			if err != nil {
+				t.Fatalf("consulta FTS5: %v", err)
+			}
+			defer rows.Close()
+			var want []string
+			var scores []float64
+			for rows.Next() {
+				var title string
+				var score float64
+				if err := rows.Scan(&title, &score); err != nil {
+					t.Fatal(err)
+				}
+				want = append(want, title)
+				scores = append(scores, score)
+			}
+
+			got, results := searchTitles(t, q.words)
+			if !slices.Equal(got, want) {
+				t.Fatalf("orden = %q, FTS5 da %q", got, want)
+			}
+			if !slices.Equal(q.want, want) {
+				t.Errorf("rankingQueries tiene %q, FTS5 da %q", q.want, want)
+			}
+			// La longitud media de matchinfo sale redondeada por columna, así
+			// que los puntajes se parecen pero no son idénticos.
+			for i := range results {
+				if math.Abs(results[i].Score-scores[i]) > 0.05*scores[i] {
+					t.Errorf("%s: puntaje %.4f, FTS5 da %.4f", got[i], results[i].Score, scores[i])
+				}
+			}
+		})
+	}
+}
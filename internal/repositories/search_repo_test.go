@@ -0,0 +1,87 @@
+// internal/repositories/search_repo_test.go
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rankingCorpus son títulos pensados para que el orden dependa de la columna
+// de la coincidencia, de cuántas veces aparece y del largo de cada fila.
+var rankingCorpus = []models.AudiovisualContent{
+	{Title: "El viaje", Synopsis: "Un viaje por el espacio.", Director: "Ana Ruiz", Genre: "Ciencia Ficción"},
+	{Title: "Espacio profundo", Synopsis: "Una nave viaja al espacio profundo y el espacio la cambia para siempre.", Director: "Luis Mora", Genre: "Ciencia Ficción"},
+	{Title: "La casa", Synopsis: "Una familia en una casa antigua con un largo pasado que nadie en el pueblo quiere recordar.", Director: "Marta Espacio", Genre: "Terror"},
+	{Title: "Viaje al espacio", Synopsis: "Documental.", Director: "Ana Ruiz", Genre: "Documental"},
+	{Title: "Noche", Synopsis: "Drama urbano sobre una noche sin fin.", Director: "Pedro Gil", Genre: "Drama"},
+	{Title: "Espacio", Synopsis: "Una mujer vuelve a su ciudad natal después de veinte años y encuentra que todo cambió menos su familia.", Director: "Sofía León", Genre: "Drama"},
+	{Title: "Drama en el espacio", Synopsis: "Comedia.", Director: "Ana Ruiz", Genre: "Comedia"},
+}
+
+// rankingQueries son las palabras buscadas y el orden de los títulos que da
+// bm25() de FTS5 con searchWeights (ver search_repo_fts5_test.go).
+var rankingQueries = []struct {
+	words []string
+	want  []string
+}{
+	{words: []string{"espacio"}, want: []string{"Viaje al espacio", "Drama en el espacio", "Espacio profundo", "Espacio", "La casa", "El viaje"}},
+	{words: []string{"viaj"}, want: []string{"Viaje al espacio", "El viaje", "Espacio profundo"}},
+	{words: []string{"ana", "viaje"}, want: []string{"Viaje al espacio", "El viaje"}},
+	{words: []string{"drama"}, want: []string{"Drama en el espacio", "Noche", "Espacio"}},
+}
+
+func newSearchTestDB(t *testing.T) {
+	t.Helper()
+	if err := db.InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := NewContentRepo()
+	for _, c := range rankingCorpus {
+		c.Type, c.Duration, c.AgeRating, c.RatingSystem, c.IsAvailable = "movie", 100, "PG", models.RatingSystemMPAA, true
+		if err := repo.CreateAudiovisual(&c); err != nil {
+			t.Fatalf("CreateAudiovisual(%s): %v", c.Title, err)
+		}
+	}
+}
+
+// fts4Query arma la consulta como services.ftsQuery.
+func fts4Query(words []string) string {
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `*"`
+	}
+	return strings.Join(terms, " ")
+}
+
+func searchTitles(t *testing.T, words []string) ([]string, []models.SearchResult) {
+	t.Helper()
+	filter := models.ContentFilter{MaxLevel: models.MaxMaturityLevel, Country: models.DefaultCountry, Now: time.Now()}
+	results, err := NewSearchRepo().Search(fts4Query(words), models.ContentTypeAudiovisual, filter, 50)
+	if err != nil {
+		t.Fatalf("Search(%v): %v", words, err)
+	}
+	titles := make([]string, len(results))
+	for i := range results {
+		titles[i] = results[i].Title
+	}
+	return titles, results
+}
+
+func TestSearchRankingMatchesFTS5(t *testing.T) {
+	newSearchTestDB(t)
+	for _, q := range rankingQueries {
+		t.Run(strings.Join(q.words, " "), func(t *testing.T) {
+			got, _ := searchTitles(t, q.words)
+			if !slices.Equal(got, q.want) {
+				t.Errorf("orden = %q, FTS5 da %q", got, q.want)
+			}
+		})
+	}
+}
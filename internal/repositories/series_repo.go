@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SeriesRepo guarda las temporadas y episodios de las series.
+type SeriesRepo interface {
+	CreateSeason(s *models.Season) error
+	CreateEpisode(e *models.Episode) error
+	FindSeasons(seriesID int) ([]models.Season, error)
+	FindSeasonByID(id int) (*models.Season, error)
+	FindEpisodeByID(id int) (*models.Episode, error)
+	FindNextEpisode(seriesID, seasonNumber, episodeNumber int) (*models.Episode, error)
+}
+
+type sqliteSeriesRepo struct {
+	conn *sql.DB
+}
+
+func NewSeriesRepo() SeriesRepo {
+	return &sqliteSeriesRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// episodeSelect une cada episodio con su temporada para saber la serie y el
+// número de temporada.
+const episodeSelect = `
+	SELECT e.id, e.season_id, s.series_id, s.number, e.number, e.title, e.duration, e.synopsis
+	FROM episodes e
+	JOIN seasons s ON s.id = e.season_id
+`
+
+func scanEpisode(row interface{ Scan(...interface{}) error }) (models.Episode, error) {
+	var e models.Episode
+	err := row.Scan(&e.ID, &e.SeasonID, &e.SeriesID, &e.SeasonNumber, &e.Number, &e.Title, &e.Duration, &e.Synopsis)
+	return e, err
+}
+
+func isUniqueConflict(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+func (r *sqliteSeriesRepo) CreateSeason(s *models.Season) error {
+	result, err := r.conn.Exec(`
+		INSERT INTO seasons (series_id, number, title)
+		VALUES (?, ?, ?)
+	`, s.SeriesID, s.Number, s.Title)
+	if isUniqueConflict(err) {
+		return apperrors.ErrConflict(fmt.Sprintf("la serie ya tiene una temporada %d", s.Number))
+	}
+	if err != nil {
+		return fmt.Errorf("error creating season: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	s.ID = int(id)
+	return nil
+}
+
+func (r *sqliteSeriesRepo) CreateEpisode(e *models.Episode) error {
+	result, err := r.conn.Exec(`
+		INSERT INTO episodes (season_id, number, title, duration, synopsis)
+		VALUES (?, ?, ?, ?, ?)
+	`, e.SeasonID, e.Number, e.Title, e.Duration, e.Synopsis)
+	if isUniqueConflict(err) {
+		return apperrors.ErrConflict(fmt.Sprintf("la temporada ya tiene un episodio %d", e.Number))
+	}
+	if err != nil {
+		return fmt.Errorf("error creating episode: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	e.ID = int(id)
+	return nil
+}
+
+// FindSeasons devuelve las temporadas de la serie con sus episodios, en orden.
+func (r *sqliteSeriesRepo) FindSeasons(seriesID int) ([]models.Season, error) {
+	rows, err := r.conn.Query(`
+		SELECT id, series_id, number, title
+		FROM seasons
+		WHERE series_id = ?
+		ORDER BY number ASC
+	`, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching seasons: %w", err)
+	}
+	defer rows.Close()
+
+	seasons := []models.Season{}
+	index := make(map[int]int)
+	for rows.Next() {
+		s := models.Season{Episodes: []models.Episode{}}
+		if err := rows.Scan(&s.ID, &s.SeriesID, &s.Number, &s.Title); err != nil {
+			return nil, fmt.Errorf("error scanning season: %w", err)
+		}
+		index[s.ID] = len(seasons)
+		seasons = append(seasons, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	episodes, err := r.conn.Query(episodeSelect+`
+		WHERE s.series_id = ?
+		ORDER BY s.number ASC, e.number ASC
+	`, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching episodes: %w", err)
+	}
+	defer episodes.Close()
+
+	for episodes.Next() {
+		e, err := scanEpisode(episodes)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning episode: %w", err)
+		}
+		i := index[e.SeasonID]
+		seasons[i].Episodes = append(seasons[i].Episodes, e)
+	}
+	return seasons, episodes.Err()
+}
+
+// FindSeasonByID devuelve nil, nil si la temporada no existe.
+func (r *sqliteSeriesRepo) FindSeasonByID(id int) (*models.Season, error) {
+	var s models.Season
+	err := r.conn.QueryRow(`
+		SELECT id, series_id, number, title
+		FROM seasons
+		WHERE id = ?
+	`, id).Scan(&s.ID, &s.SeriesID, &s.Number, &s.Title)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning season: %w", err)
+	}
+	return &s, nil
+}
+
+// FindEpisodeByID devuelve nil, nil si el episodio no existe.
+func (r *sqliteSeriesRepo) FindEpisodeByID(id int) (*models.Episode, error) {
+	e, err := scanEpisode(r.conn.QueryRow(episodeSelect+`WHERE e.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning episode: %w", err)
+	}
+	return &e, nil
+}
+
+// FindNextEpisode devuelve el primer episodio de la serie posterior al
+// indicado por temporada y número; con 0, 0 devuelve el primero de la serie.
+// Devuelve nil, nil si no hay más.
+func (r *sqliteSeriesRepo) FindNextEpisode(seriesID, seasonNumber, episodeNumber int) (*models.Episode, error) {
+	e, err := scanEpisode(r.conn.QueryRow(episodeSelect+`
+		WHERE s.series_id = ?
+		AND (s.number > ? OR (s.number = ? AND e.number > ?))
+		ORDER BY s.number ASC, e.number ASC
+		LIMIT 1
+	`, seriesID, seasonNumber, seasonNumber, episodeNumber))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning episode: %w", err)
+	}
+	return &e, nil
+}
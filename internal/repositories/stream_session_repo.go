@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type StreamSessionRepo interface {
+	Start(s *models.StreamSession, maxDevices int, seenSince time.Time) (bool, error)
+	FindByID(id int) (*models.StreamSession, error)
+	FindActiveByUserID(userID int, seenSince time.Time) ([]models.StreamSession, error)
+	Touch(id int, now time.Time) error
+	End(id int, now time.Time) error
+}
+
+type sqliteStreamSessionRepo struct {
+	conn *sql.DB
+}
+
+func NewStreamSessionRepo() StreamSessionRepo {
+	return &sqliteStreamSessionRepo{
+		conn: db.GetDB(),
+	}
+}
+
+const streamSessionColumns = `id, user_id, device, content_id, content_type, started_at, last_seen_at, ended_at`
+
+func scanStreamSession(row rowScanner) (*models.StreamSession, error) {
+	var s models.StreamSession
+	var endedAt sql.NullTime
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Device,
+		&s.ContentID,
+		&s.ContentType,
+		&s.StartedAt,
+		&s.LastSeenAt,
+		&endedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		s.EndedAt = &endedAt.Time
+	}
+	return &s, nil
+}
+
+// Start abre la sesión s en una sola transacción: termina la que tuviera
+// abierta el mismo dispositivo y crea la nueva solo si el usuario tiene menos
+// de maxDevices sesiones activas (con señales desde seenSince). Contar e
+// insertar en la misma sentencia evita que dos dispositivos que arrancan a la
+// vez vean ambos un lugar libre. Devuelve false, sin cambiar nada, si ya no
+// hay lugar.
+func (r *sqliteStreamSessionRepo) Start(s *models.StreamSession, maxDevices int, seenSince time.Time) (bool, error) {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error starting stream session: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE stream_sessions SET ended_at = ?
+		WHERE user_id = ? AND device = ? AND ended_at IS NULL
+	`, s.StartedAt.UTC(), s.UserID, s.Device)
+	if err != nil {
+		return false, fmt.Errorf("error ending previous stream session: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO stream_sessions (user_id, device, content_id, content_type, started_at, last_seen_at)
+		SELECT ?, ?, ?, ?, ?, ?
+		WHERE (
+			SELECT COUNT(*) FROM stream_sessions
+			WHERE user_id = ? AND ended_at IS NULL AND last_seen_at >= ?
+		) < ?
+	`, s.UserID, s.Device, s.ContentID, s.ContentType, s.StartedAt.UTC(), s.LastSeenAt.UTC(),
+		s.UserID, seenSince.UTC(), maxDevices)
+	if err != nil {
+		return false, fmt.Errorf("error creating stream session: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	s.ID = int(id)
+	return true, tx.Commit()
+}
+
+// FindByID devuelve nil, nil si la sesión no existe.
+func (r *sqliteStreamSessionRepo) FindByID(id int) (*models.StreamSession, error) {
+	query := `SELECT ` + streamSessionColumns + ` FROM stream_sessions WHERE id = ?`
+
+	s, err := scanStreamSession(r.conn.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error scanning stream session: %w", err)
+	}
+	return s, nil
+}
+
+// FindActiveByUserID devuelve las sesiones sin terminar que dieron señales
+// desde `seenSince`; las más antiguas se consideran abandonadas.
+func (r *sqliteStreamSessionRepo) FindActiveByUserID(userID int, seenSince time.Time) ([]models.StreamSession, error) {
+	query := `
+		SELECT ` + streamSessionColumns + `
+		FROM stream_sessions
+		WHERE user_id = ? AND ended_at IS NULL AND last_seen_at >= ?
+		ORDER BY started_at ASC
+	`
+
+	rows, err := r.conn.Query(query, userID, seenSince.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stream sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.StreamSession
+	for rows.Next() {
+		s, err := scanStreamSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning stream session row: %w", err)
+		}
+		list = append(list, *s)
+	}
+	return list, rows.Err()
+}
+
+func (r *sqliteStreamSessionRepo) Touch(id int, now time.Time) error {
+	_, err := r.conn.Exec(`UPDATE stream_sessions SET last_seen_at = ? WHERE id = ?`, now.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error updating stream session: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteStreamSessionRepo) End(id int, now time.Time) error {
+	_, err := r.conn.Exec(`UPDATE stream_sessions SET ended_at = ? WHERE id = ? AND ended_at IS NULL`, now.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error ending stream session: %w", err)
+	}
+	return nil
+}
@@ -10,10 +10,14 @@ import (
 
 type SubscriptionRepo interface {
 	Create(sub *models.Subscription) error
-	UpdateUserPlan(userID int, planID int) error
-	FindByUserID(userID int) (*models.Subscription, error)
+	StartPeriod(sub *models.Subscription) error
+	FindActiveByUserID(userID int) (*models.Subscription, error)
+	FindHistoryByUserID(userID int) ([]models.Subscription, error)
 	FindAll() ([]models.Subscription, error)
+	FindExpired(now time.Time) ([]models.Subscription, error)
+	Deactivate(id int) error
 	Cancel(userID int) error
+	SetPendingPlan(id int, planID *int) error
 	GetPlanByID(planID int) (*models.Plan, error)
 	GetAllPlans() ([]models.Plan, error)
 }
@@ -28,96 +32,179 @@ func NewSubscriptionRepo() SubscriptionRepo {
 	}
 }
 
-//
-// CREAR SUSCRIPCIÓN
-//
+const subscriptionColumns = `id, user_id, plan_id, start_date, end_date, is_active, auto_renew, canceled_at, pending_plan_id, created_at, updated_at`
 
-func (r *sqliteSubscriptionRepo) Create(s *models.Subscription) error {
-	query := `
-		INSERT INTO subscriptions (user_id, plan_id, start_date, end_date, is_active)
-		VALUES (?, ?, ?, ?, ?)
-	`
+// rowScanner lo cumplen tanto *sql.Row como *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	_, err := r.conn.Exec(query,
-		s.UserID,
-		s.PlanID,
-		s.StartDate,
-		s.EndDate,
-		s.IsActive,
+func scanSubscription(row rowScanner) (*models.Subscription, error) {
+	var s models.Subscription
+	var canceledAt sql.NullTime
+	var pendingPlanID sql.NullInt64
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.PlanID,
+		&s.StartDate,
+		&s.EndDate,
+		&s.IsActive,
+		&s.AutoRenew,
+		&canceledAt,
+		&pendingPlanID,
+		&s.CreatedAt,
+		&s.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if canceledAt.Valid {
+		s.CanceledAt = &canceledAt.Time
+	}
+	if pendingPlanID.Valid {
+		id := int(pendingPlanID.Int64)
+		s.PendingPlanID = &id
+	}
+	return &s, nil
+}
 
+func (r *sqliteSubscriptionRepo) querySubscriptions(query string, args ...interface{}) ([]models.Subscription, error) {
+	rows, err := r.conn.Query(query, args...)
 	if err != nil {
-		return fmt.Errorf("error creating subscription: %w", err)
+		return nil, fmt.Errorf("error fetching subscriptions: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	var list []models.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning subscription row: %w", err)
+		}
+		list = append(list, *s)
+	}
+
+	return list, rows.Err()
 }
 
 //
-// ACTUALIZAR PLAN DEL USUARIO
+// CREAR SUSCRIPCIÓN
 //
 
-func (r *sqliteSubscriptionRepo) UpdateUserPlan(userID int, planID int) error {
-	query := `
+func (r *sqliteSubscriptionRepo) Create(s *models.Subscription) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error creating subscription: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertSubscription(tx, s); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// StartPeriod reemplaza el período activo del usuario por s en una sola
+// transacción: desactiva el que hubiera, crea s y deja la cuenta en el plan
+// de s. Si algo falla, sigue vigente el período anterior.
+func (r *sqliteSubscriptionRepo) StartPeriod(s *models.Subscription) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting subscription period: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
 		UPDATE subscriptions
-		SET plan_id = ?, start_date = ?, end_date = ?, is_active = 1
-		WHERE user_id = ?
-	`
+		SET is_active = 0, updated_at = ?
+		WHERE user_id = ? AND is_active = 1
+	`, time.Now().UTC(), s.UserID)
+	if err != nil {
+		return fmt.Errorf("error deactivating subscription: %w", err)
+	}
+	if err := insertSubscription(tx, s); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE users SET plan_id = ? WHERE id = ?`, s.PlanID, s.UserID); err != nil {
+		return fmt.Errorf("error updating user plan: %w", err)
+	}
+	return tx.Commit()
+}
 
-	now := time.Now()
-	end := now.AddDate(0, 1, 0) // un mes de suscripción
+func insertSubscription(tx *sql.Tx, s *models.Subscription) error {
+	query := `
+		INSERT INTO subscriptions (user_id, plan_id, start_date, end_date, is_active, auto_renew, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
 
-	_, err := r.conn.Exec(query,
-		planID,
+	now := time.Now().UTC()
+	result, err := tx.Exec(query,
+		s.UserID,
+		s.PlanID,
+		s.StartDate.UTC(),
+		s.EndDate.UTC(),
+		s.IsActive,
+		s.AutoRenew,
+		now,
 		now,
-		end,
-		userID,
 	)
+	if err != nil {
+		return fmt.Errorf("error creating subscription: %w", err)
+	}
 
+	id, err := result.LastInsertId()
 	if err != nil {
-		return fmt.Errorf("error updating user plan: %w", err)
+		return err
 	}
+	s.ID = int(id)
+	s.CreatedAt = now
+	s.UpdatedAt = now
 	return nil
 }
 
 //
-// OBTENER SUSCRIPCIÓN POR USUARIO
+// OBTENER SUSCRIPCIÓN ACTIVA POR USUARIO
 //
 
-func (r *sqliteSubscriptionRepo) FindByUserID(userID int) (*models.Subscription, error) {
+func (r *sqliteSubscriptionRepo) FindActiveByUserID(userID int) (*models.Subscription, error) {
 	query := `
-		SELECT id, user_id, plan_id, start_date, end_date, is_active
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
-		WHERE user_id = ?
+		WHERE user_id = ? AND is_active = 1
+		ORDER BY start_date DESC
+		LIMIT 1
 	`
 
-	row := r.conn.QueryRow(query, userID)
-
-	var s models.Subscription
-	err := row.Scan(
-		&s.ID,
-		&s.UserID,
-		&s.PlanID,
-		&s.StartDate,
-		&s.EndDate,
-		&s.IsActive,
-	)
-
+	s, err := scanSubscription(r.conn.QueryRow(query, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-
 	if err != nil {
 		return nil, fmt.Errorf("error scanning subscription: %w", err)
 	}
 
-	return &s, nil
+	return s, nil
+}
+
+//
+// HISTORIAL DE SUSCRIPCIONES DE UN USUARIO
+//
+
+func (r *sqliteSubscriptionRepo) FindHistoryByUserID(userID int) ([]models.Subscription, error) {
+	return r.querySubscriptions(`
+		SELECT `+subscriptionColumns+`
+		FROM subscriptions
+		WHERE user_id = ?
+		ORDER BY start_date DESC, id DESC
+	`, userID)
 }
 
 // solucion del error GetPlanByID method defined
 // add this method to the subscription_repo.go file
 func (r *sqliteSubscriptionRepo) GetPlanByID(planID int) (*models.Plan, error) {
 	query := `
-		SELECT id, name, price
+		SELECT id, name, price, max_quality, max_devices, max_profiles
 		FROM plans
 		WHERE id = ?
 	`
@@ -129,6 +216,9 @@ func (r *sqliteSubscriptionRepo) GetPlanByID(planID int) (*models.Plan, error) {
 		&p.ID,
 		&p.Name,
 		&p.Price,
+		&p.MaxQuality,
+		&p.MaxDevices,
+		&p.MaxProfiles,
 	)
 
 	if err == sql.ErrNoRows {
@@ -147,50 +237,59 @@ func (r *sqliteSubscriptionRepo) GetPlanByID(planID int) (*models.Plan, error) {
 //
 
 func (r *sqliteSubscriptionRepo) FindAll() ([]models.Subscription, error) {
-	query := `
-		SELECT id, user_id, plan_id, start_date, end_date, is_active
+	return r.querySubscriptions(`
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
 		ORDER BY start_date DESC
-	`
+	`)
+}
 
-	rows, err := r.conn.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching subscriptions: %w", err)
-	}
-	defer rows.Close()
+//
+// SUSCRIPCIONES ACTIVAS CUYO PERÍODO YA TERMINÓ
+//
 
-	var list []models.Subscription
+func (r *sqliteSubscriptionRepo) FindExpired(now time.Time) ([]models.Subscription, error) {
+	return r.querySubscriptions(`
+		SELECT `+subscriptionColumns+`
+		FROM subscriptions
+		WHERE is_active = 1 AND end_date <= ?
+		ORDER BY end_date ASC
+	`, now.UTC())
+}
 
-	for rows.Next() {
-		var s models.Subscription
-		if err := rows.Scan(
-			&s.ID,
-			&s.UserID,
-			&s.PlanID,
-			&s.StartDate,
-			&s.EndDate,
-			&s.IsActive,
-		); err != nil {
-			return nil, fmt.Errorf("error scanning subscription row: %w", err)
-		}
-		list = append(list, s)
-	}
+//
+// CERRAR UN PERÍODO (RENOVADO, REEMPLAZADO O VENCIDO)
+//
 
-	return list, nil
+func (r *sqliteSubscriptionRepo) Deactivate(id int) error {
+	query := `
+		UPDATE subscriptions
+		SET is_active = 0, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.conn.Exec(query, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error deactivating subscription: %w", err)
+	}
+	return nil
 }
 
 //
 // CANCELAR SUSCRIPCIÓN
 //
 
+// Cancel desactiva la renovación automática; el período en curso se respeta
+// hasta su end_date.
 func (r *sqliteSubscriptionRepo) Cancel(userID int) error {
 	query := `
 		UPDATE subscriptions
-		SET is_active = 0
-		WHERE user_id = ?
+		SET auto_renew = 0, canceled_at = ?, updated_at = ?
+		WHERE user_id = ? AND is_active = 1
 	`
 
-	_, err := r.conn.Exec(query, userID)
+	now := time.Now().UTC()
+	_, err := r.conn.Exec(query, now, now, userID)
 	if err != nil {
 		return fmt.Errorf("error canceling subscription: %w", err)
 	}
@@ -198,14 +297,35 @@ func (r *sqliteSubscriptionRepo) Cancel(userID int) error {
 	return nil
 }
 
+//
+// PROGRAMAR CAMBIO DE PLAN PARA LA RENOVACIÓN
+//
+
+// SetPendingPlan guarda el plan que se aplicará al renovar; nil quita el
+// cambio programado.
+func (r *sqliteSubscriptionRepo) SetPendingPlan(id int, planID *int) error {
+	query := `
+		UPDATE subscriptions
+		SET pending_plan_id = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.conn.Exec(query, planID, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("error scheduling plan change: %w", err)
+	}
+	return nil
+}
+
 //
 // OBTENER TODOS LOS PLANES
 //
 
 func (r *sqliteSubscriptionRepo) GetAllPlans() ([]models.Plan, error) {
 	query := `
-		SELECT id, name, price
+		SELECT id, name, price, max_quality, max_devices, max_profiles
 		FROM plans
+		ORDER BY id ASC
 	`
 
 	rows, err := r.conn.Query(query)
@@ -222,6 +342,9 @@ func (r *sqliteSubscriptionRepo) GetAllPlans() ([]models.Plan, error) {
 			&p.ID,
 			&p.Name,
 			&p.Price,
+			&p.MaxQuality,
+			&p.MaxDevices,
+			&p.MaxProfiles,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning plan row: %w", err)
 		}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"time"
+)
+
+// TrendingRepo lee las interacciones recientes con el catálogo para armar
+// las tendencias.
+type TrendingRepo interface {
+	FindEngagement(since time.Time) ([]models.Engagement, error)
+}
+
+type sqliteTrendingRepo struct {
+	conn *sql.DB
+}
+
+func NewTrendingRepo() TrendingRepo {
+	return &sqliteTrendingRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// FindEngagement devuelve las interacciones desde since: cada reproducción
+// del historial, las que quedaron marcadas como terminadas (ver
+// PlaybackService.ReportProgress), los agregados a Mi Lista y las
+// calificaciones.
+func (r *sqliteTrendingRepo) FindEngagement(since time.Time) ([]models.Engagement, error) {
+	since = since.UTC()
+	rows, err := r.conn.Query(`
+		SELECT content_type, content_id, ?, 0, watched_at
+		FROM playback_history
+		WHERE watched_at >= ?
+		UNION ALL
+		SELECT content_type, content_id, ?, 0, watched_at
+		FROM playback_history
+		WHERE watched_at >= ? AND completed = 1
+		UNION ALL
+		SELECT content_type, content_id, ?, 0, added_at
+		FROM favorites
+		WHERE added_at >= ?
+		UNION ALL
+		SELECT content_type, content_id, ?, rating, rated_at
+		FROM user_ratings
+		WHERE rated_at >= ?
+	`, models.SignalWatched, since,
+		models.SignalCompleted, since,
+		models.SignalFavorite, since,
+		models.SignalRated, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.Engagement
+	for rows.Next() {
+		var e models.Engagement
+		if err := rows.Scan(&e.ContentType, &e.ContentID, &e.Signal, &e.Rating, &e.At); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
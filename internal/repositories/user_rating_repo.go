@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UserRatingRepo guarda las calificaciones de cada perfil y mantiene los
+// votos, el promedio y el puntaje bayesiano de cada contenido.
+type UserRatingRepo interface {
+	Save(profileID int, ref models.ContentRef, rating float64) error
+	// Delete devuelve false si el perfil no había calificado el contenido.
+	Delete(profileID int, ref models.ContentRef) (bool, error)
+	FindByProfileID(profileID int) ([]models.UserRating, error)
+	FindHistogram(ref models.ContentRef) ([10]int, error)
+	RefreshStats(ref models.ContentRef, priorVotes float64) error
+	FindMean(contentType string) (*float64, time.Time, error)
+	RefreshMean(contentType string, priorVotes float64, now time.Time) error
+}
+
+type sqliteUserRatingRepo struct {
+	conn *sql.DB
+}
+
+func NewUserRatingRepo() UserRatingRepo {
+	return &sqliteUserRatingRepo{
+		conn: db.GetDB(),
+	}
+}
+
+// Save guarda la calificación; si el perfil ya había calificado el
+// contenido, la reemplaza.
+func (r *sqliteUserRatingRepo) Save(profileID int, ref models.ContentRef, rating float64) error {
+	_, err := r.conn.Exec(`
+		INSERT INTO user_ratings (profile_id, content_id, content_type, rating)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(profile_id, content_id, content_type)
+		DO UPDATE SET rating = excluded.rating, rated_at = CURRENT_TIMESTAMP
+	`, profileID, ref.ContentID, ref.ContentType, rating)
+	if err != nil {
+		return fmt.Errorf("error saving rating: %w", err)
+	}
+	return nil
+}
+
+// Delete borra la calificación; su reseña, con los votos y denuncias, se
+// borra en cascada.
+func (r *sqliteUserRatingRepo) Delete(profileID int, ref models.ContentRef) (bool, error) {
+	res, err := r.conn.Exec(`
+		DELETE FROM user_ratings
+		WHERE profile_id = ? AND content_id = ? AND content_type = ?
+	`, profileID, ref.ContentID, ref.ContentType)
+	if err != nil {
+		return false, fmt.Errorf("error deleting rating: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// FindByProfileID devuelve las calificaciones del perfil, de la más reciente
+// a la más antigua.
+func (r *sqliteUserRatingRepo) FindByProfileID(profileID int) ([]models.UserRating, error) {
+	rows, err := r.conn.Query(`
+		SELECT id, profile_id, content_id, content_type, rating, rated_at
+		FROM user_ratings
+		WHERE profile_id = ?
+		ORDER BY rated_at DESC, id DESC
+	`, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var ratings []models.UserRating
+	for rows.Next() {
+		var ur models.UserRating
+		if err := rows.Scan(&ur.ID, &ur.ProfileID, &ur.ContentID, &ur.ContentType, &ur.Rating, &ur.RatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning rating: %w", err)
+		}
+		ratings = append(ratings, ur)
+	}
+	return ratings, rows.Err()
+}
+
+// FindHistogram cuenta los votos del contenido por punto de la escala.
+func (r *sqliteUserRatingRepo) FindHistogram(ref models.ContentRef) ([10]int, error) {
+	var histogram [10]int
+	rows, err := r.conn.Query(`
+		SELECT MIN(CAST(rating AS INTEGER), 10), COUNT(*)
+		FROM user_ratings
+		WHERE content_id = ? AND content_type = ?
+		GROUP BY 1
+	`, ref.ContentID, ref.ContentType)
+	if err != nil {
+		return histogram, fmt.Errorf("error fetching rating histogram: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket, votes int
+		if err := rows.Scan(&bucket, &votes); err != nil {
+			return histogram, fmt.Errorf("error scanning rating histogram: %w", err)
+		}
+		if bucket >= 1 && bucket <= len(histogram) {
+			histogram[bucket-1] = votes
+		}
+	}
+	return histogram, rows.Err()
+}
+
+// RefreshStats recalcula los votos, el promedio y el puntaje bayesiano solo
+// del contenido indicado, con la media del catálogo guardada en rating_means:
+// (votos × promedio + priorVotes × media) / (votos + priorVotes). Mientras no
+// hay media guardada se usa el promedio del propio contenido. Un contenido
+// sin votos queda con puntaje 0.
+func (r *sqliteUserRatingRepo) RefreshStats(ref models.ContentRef, priorVotes float64) error {
+	table := contentTable(ref.ContentType)
+	_, err := r.conn.Exec(`
+		WITH stats AS (
+			SELECT COUNT(*) AS votes, COALESCE(AVG(rating), 0) AS average
+			FROM user_ratings
+			WHERE content_type = ? AND content_id = ?
+		)
+		UPDATE `+table+`
+		SET rating_count = stats.votes,
+			average_rating = stats.average,
+			weighted_rating = CASE WHEN stats.votes = 0 THEN 0 ELSE
+				(stats.votes * stats.average + ? * COALESCE((SELECT mean FROM rating_means WHERE content_type = ?), stats.average)) / (stats.votes + ?)
+			END
+		FROM stats
+		WHERE id = ?
+	`, ref.ContentType, ref.ContentID, priorVotes, ref.ContentType, priorVotes, ref.ContentID)
+	if err != nil {
+		return fmt.Errorf("error refreshing rating stats: %w", err)
+	}
+	return nil
+}
+
+// FindMean devuelve la media guardada del tipo de contenido y cuándo se
+// calculó. La media es nil si no hay votos o si nunca se calculó; en ese
+// caso el instante es cero.
+func (r *sqliteUserRatingRepo) FindMean(contentType string) (*float64, time.Time, error) {
+	var mean sql.NullFloat64
+	var refreshedAt time.Time
+	err := r.conn.QueryRow(`
+		SELECT mean, refreshed_at FROM rating_means WHERE content_type = ?
+	`, contentType).Scan(&mean, &refreshedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error finding catalog mean rating: %w", err)
+	}
+	if !mean.Valid {
+		return nil, time.Time{}, nil
+	}
+	return &mean.Float64, refreshedAt, nil
+}
+
+// RefreshMean recalcula la media del tipo de contenido y, con ella, el
+// puntaje bayesiano de los contenidos que tienen votos.
+func (r *sqliteUserRatingRepo) RefreshMean(contentType string, priorVotes float64, now time.Time) error {
+	table := contentTable(contentType)
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("error refreshing catalog mean rating: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO rating_means (content_type, mean, refreshed_at)
+		SELECT ?, AVG(rating), ? FROM user_ratings WHERE content_type = ?
+		ON CONFLICT(content_type) DO UPDATE SET mean = excluded.mean, refreshed_at = excluded.refreshed_at
+	`, contentType, now, contentType)
+	if err != nil {
+		return fmt.Errorf("error refreshing catalog mean rating: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE `+table+`
+		SET weighted_rating = (rating_count * average_rating + ? * (SELECT mean FROM rating_means WHERE content_type = ?)) / (rating_count + ?)
+		WHERE rating_count > 0
+	`, priorVotes, contentType, priorVotes)
+	if err != nil {
+		return fmt.Errorf("error refreshing weighted ratings: %w", err)
+	}
+	return tx.Commit()
+}
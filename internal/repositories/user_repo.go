@@ -6,6 +6,7 @@ import (
 	"SDGEStreaming/internal/models"
 	"database/sql"
 	"fmt"
+	"time"
 )
 
 type UserRepo interface {
@@ -16,6 +17,9 @@ type UserRepo interface {
 	Update(u *models.User) error
 	Delete(id int) error
 	UpdatePlan(userID int, planID int) error
+	UpdateLastLogin(userID int, at time.Time) error
+	UpdateRole(userID int, role string) error
+	UpdateCountry(userID int, country string) error
 	AddPaymentMethod(pm *models.PaymentMethod) error
 	GetDefaultPaymentMethod(userID int) (*models.PaymentMethod, error)
 }
@@ -30,7 +34,7 @@ func (r *sqliteUserRepo) FindAll() ([]models.User, error) {
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, name, email, age, plan_id, age_rating, is_admin, password_hash, created_at, last_login
+		SELECT id, name, email, age, plan_id, age_rating, country, role, password_hash, created_at, last_login
 		FROM users
 		ORDER BY id ASC
 	`
@@ -52,7 +56,8 @@ func (r *sqliteUserRepo) FindAll() ([]models.User, error) {
 			&u.Age,
 			&u.PlanID,
 			&u.AgeRating,
-			&u.IsAdmin,
+			&u.Country,
+			&u.Role,
 			&u.PasswordHash,
 			&u.CreatedAt,
 			&u.LastLogin,
@@ -70,7 +75,7 @@ func (r *sqliteUserRepo) FindByID(id int) (*models.User, error) {
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, name, email, age, plan_id, age_rating, is_admin, password_hash, created_at, last_login
+		SELECT id, name, email, age, plan_id, age_rating, country, role, password_hash, created_at, last_login
 		FROM users
 		WHERE id = ?
 	`
@@ -83,7 +88,8 @@ func (r *sqliteUserRepo) FindByID(id int) (*models.User, error) {
 		&u.Age,
 		&u.PlanID,
 		&u.AgeRating,
-		&u.IsAdmin,
+		&u.Country,
+		&u.Role,
 		&u.PasswordHash,
 		&u.CreatedAt,
 		&u.LastLogin,
@@ -99,7 +105,7 @@ func (r *sqliteUserRepo) FindByEmail(email string) (*models.User, error) {
 	conn := db.GetDB()
 
 	query := `
-		SELECT id, name, email, age, plan_id, age_rating, is_admin, password_hash, created_at, last_login
+		SELECT id, name, email, age, plan_id, age_rating, country, role, password_hash, created_at, last_login
 		FROM users
 		WHERE email = ?
 	`
@@ -112,7 +118,8 @@ func (r *sqliteUserRepo) FindByEmail(email string) (*models.User, error) {
 		&u.Age,
 		&u.PlanID,
 		&u.AgeRating,
-		&u.IsAdmin,
+		&u.Country,
+		&u.Role,
 		&u.PasswordHash,
 		&u.CreatedAt,
 		&u.LastLogin,
@@ -127,21 +134,30 @@ func (r *sqliteUserRepo) FindByEmail(email string) (*models.User, error) {
 	return &u, nil
 }
 
+// Create inserta la cuenta junto con su perfil principal, que lleva el mismo
+// nombre y clasificación que el titular.
 func (r *sqliteUserRepo) Create(u *models.User) error {
 	conn := db.GetDB()
 
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO users (name, email, age, plan_id, age_rating, is_admin, password_hash, created_at, last_login)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO users (name, email, age, plan_id, age_rating, country, role, password_hash, created_at, last_login)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := conn.Exec(query,
+	result, err := tx.Exec(query,
 		u.Name,
 		u.Email,
 		u.Age,
 		u.PlanID,
 		u.AgeRating,
-		u.IsAdmin,
+		u.Country,
+		u.Role,
 		u.PasswordHash,
 		u.CreatedAt,
 		u.LastLogin,
@@ -154,6 +170,20 @@ func (r *sqliteUserRepo) Create(u *models.User) error {
 	if err != nil {
 		return err
 	}
+
+	profile := &models.Profile{
+		UserID:    int(id),
+		Name:      u.Name,
+		AgeRating: u.AgeRating,
+		CreatedAt: u.CreatedAt,
+	}
+	if err := insertProfile(tx, profile); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
 	u.ID = int(id)
 	return nil
 }
@@ -163,7 +193,7 @@ func (r *sqliteUserRepo) Update(u *models.User) error {
 
 	query := `
 		UPDATE users
-		SET name = ?, email = ?, age = ?, plan_id = ?, age_rating = ?, is_admin = ?, password_hash = ?
+		SET name = ?, email = ?, age = ?, plan_id = ?, age_rating = ?, country = ?, role = ?, password_hash = ?
 		WHERE id = ?
 	`
 
@@ -173,7 +203,8 @@ func (r *sqliteUserRepo) Update(u *models.User) error {
 		u.Age,
 		u.PlanID,
 		u.AgeRating,
-		u.IsAdmin,
+		u.Country,
+		u.Role,
 		u.PasswordHash,
 		u.ID,
 	)
@@ -204,20 +235,63 @@ func (r *sqliteUserRepo) UpdatePlan(userID int, planID int) error {
 	return err
 }
 
+func (r *sqliteUserRepo) UpdateLastLogin(userID int, at time.Time) error {
+	conn := db.GetDB()
+
+	query := `
+		UPDATE users
+		SET last_login = ?
+		WHERE id = ?
+	`
+
+	_, err := conn.Exec(query, at.UTC(), userID)
+	return err
+}
+
+func (r *sqliteUserRepo) UpdateRole(userID int, role string) error {
+	conn := db.GetDB()
+
+	query := `
+		UPDATE users
+		SET role = ?
+		WHERE id = ?
+	`
+
+	_, err := conn.Exec(query, role, userID)
+	return err
+}
+
+func (r *sqliteUserRepo) UpdateCountry(userID int, country string) error {
+	conn := db.GetDB()
+
+	query := `
+		UPDATE users
+		SET country = ?
+		WHERE id = ?
+	`
+
+	_, err := conn.Exec(query, country, userID)
+	return err
+}
+
 func (r *sqliteUserRepo) GetDefaultPaymentMethod(userID int) (*models.PaymentMethod, error) {
 	conn := db.GetDB()
 
 	query := `
-		SELECT user_id, card_holder_name, card_number_last4, expiry_month, expiry_year, is_default, created_at
+		SELECT id, user_id, card_holder_name, token, brand, card_number_last4, expiry_month, expiry_year, is_default, created_at
 		FROM payment_methods
 		WHERE user_id = ? AND is_default = 1
+		ORDER BY id DESC
 		LIMIT 1
 	`
 
 	var pm models.PaymentMethod
 	err := conn.QueryRow(query, userID).Scan(
+		&pm.ID,
 		&pm.UserID,
 		&pm.CardHolder,
+		&pm.Token,
+		&pm.Brand,
 		&pm.Last4,
 		&pm.ExpiryMonth,
 		&pm.ExpiryYear,
@@ -234,34 +308,49 @@ func (r *sqliteUserRepo) GetDefaultPaymentMethod(userID int) (*models.PaymentMet
 	return &pm, nil
 }
 
+// AddPaymentMethod guarda una tarjeta ya tokenizada. Si es la predeterminada,
+// las demás tarjetas del usuario dejan de serlo.
 func (r *sqliteUserRepo) AddPaymentMethod(pm *models.PaymentMethod) error {
 	conn := db.GetDB()
 
+	if pm.IsDefault {
+		if _, err := conn.Exec(`UPDATE payment_methods SET is_default = 0 WHERE user_id = ?`, pm.UserID); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		INSERT INTO payment_methods (
 			user_id,
 			card_holder_name,
-			card_number,
-			expiration_date,
-			cvv,
+			token,
+			brand,
 			card_number_last4,
 			expiry_month,
 			expiry_year,
 			is_default
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := conn.Exec(query,
+	result, err := conn.Exec(query,
 		pm.UserID,
 		pm.CardHolder,
-		pm.CardNumber,
-		pm.ExpirationDate,
-		pm.CVV,
+		pm.Token,
+		pm.Brand,
 		pm.Last4,
 		pm.ExpiryMonth,
 		pm.ExpiryYear,
 		pm.IsDefault,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	pm.ID = int(id)
+	return nil
 }
@@ -0,0 +1,193 @@
+// internal/services/auth_service.go
+// Sesiones de inicio de sesión con tokens opacos que vencen, compartidas por
+// el menú interactivo y la API.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"SDGEStreaming/internal/security"
+	"time"
+)
+
+const (
+	// AccessTokenTTL es la vigencia de un token de acceso.
+	AccessTokenTTL = time.Hour
+	// RefreshTokenTTL es cuánto dura una sesión sin usarse: cada renovación
+	// extiende el plazo.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// CodeInvalidToken indica que el token presentado venció, fue revocado o no
+// corresponde a ninguna sesión.
+const CodeInvalidToken = "INVALID_TOKEN"
+
+// AuthService emite, valida, renueva y revoca sesiones.
+type AuthService struct {
+	sessionRepo repositories.AuthSessionRepo
+	userRepo    repositories.UserRepo
+	userService *UserService
+}
+
+// NewAuthService crea una nueva instancia del servicio.
+func NewAuthService(sessionRepo repositories.AuthSessionRepo, userRepo repositories.UserRepo, userService *UserService) *AuthService {
+	return &AuthService{sessionRepo: sessionRepo, userRepo: userRepo, userService: userService}
+}
+
+// Login verifica las credenciales, abre una sesión para el cliente indicado
+// (p. ej. "cli" o "api") y registra el último acceso del usuario.
+func (s *AuthService) Login(email, password, client string, now time.Time) (*models.User, *models.AuthTokens, error) {
+	user, err := s.userService.Login(email, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session := &models.AuthSession{
+		UserID:     user.ID,
+		Client:     client,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	tokens, err := issueTokens(session, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	tokens.SessionID = session.ID
+
+	if err := s.userRepo.UpdateLastLogin(user.ID, now); err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	user.LastLogin = now
+	return user, tokens, nil
+}
+
+// Authenticate valida un token de acceso y devuelve el usuario y la sesión.
+func (s *AuthService) Authenticate(accessToken string, now time.Time) (*models.User, *models.AuthSession, error) {
+	session, err := s.sessionRepo.FindByAccessTokenHash(security.HashToken(accessToken))
+	if err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	if session == nil || session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, nil, errSessionInvalid()
+	}
+
+	user, err := s.userService.GetByID(session.UserID)
+	if err != nil {
+		return nil, nil, errSessionInvalid()
+	}
+
+	if err := s.sessionRepo.Touch(session.ID, now); err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	session.LastUsedAt = now
+	return user, session, nil
+}
+
+// Refresh canjea un token de renovación por un par nuevo. El token usado
+// deja de servir, así que un token robado y ya canjeado no se puede reutilizar.
+func (s *AuthService) Refresh(refreshToken string, now time.Time) (*models.User, *models.AuthTokens, error) {
+	session, err := s.sessionRepo.FindByRefreshTokenHash(security.HashToken(refreshToken))
+	if err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	if session == nil || session.RevokedAt != nil || !now.Before(session.RefreshExpiresAt) {
+		return nil, nil, errSessionInvalid()
+	}
+
+	user, err := s.userService.GetByID(session.UserID)
+	if err != nil {
+		return nil, nil, errSessionInvalid()
+	}
+
+	tokens, err := issueTokens(session, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	session.LastUsedAt = now
+	if err := s.sessionRepo.Rotate(session); err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	tokens.SessionID = session.ID
+	return user, tokens, nil
+}
+
+// Logout revoca la sesión indicada del usuario.
+func (s *AuthService) Logout(userID, sessionID int, now time.Time) error {
+	sessions, err := s.GetSessions(userID, now)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			if err := s.sessionRepo.Revoke(session.ID, now); err != nil {
+				return apperrors.ErrDatabase(err)
+			}
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound("sesión")
+}
+
+// RevokeAll cierra todas las sesiones del usuario en todos los clientes y
+// devuelve cuántas se cerraron.
+func (s *AuthService) RevokeAll(userID int, now time.Time) (int, error) {
+	n, err := s.sessionRepo.RevokeAllByUserID(userID, now)
+	if err != nil {
+		return 0, apperrors.ErrDatabase(err)
+	}
+	return n, nil
+}
+
+// RevokeUserSessions cierra todas las sesiones de otro usuario, p. ej. ante
+// una cuenta comprometida (requiere permiso de soporte).
+func (s *AuthService) RevokeUserSessions(actor *models.User, userID int, now time.Time) (int, error) {
+	if err := authorize(actor, models.PermRevokeSessions); err != nil {
+		return 0, err
+	}
+	if _, err := s.userService.GetByID(userID); err != nil {
+		return 0, err
+	}
+	return s.RevokeAll(userID, now)
+}
+
+// GetSessions devuelve las sesiones abiertas del usuario.
+func (s *AuthService) GetSessions(userID int, now time.Time) ([]models.AuthSession, error) {
+	list, err := s.sessionRepo.FindActiveByUserID(userID, now)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return list, nil
+}
+
+// issueTokens genera un par de tokens nuevo y guarda sus hashes y
+// vencimientos en la sesión.
+func issueTokens(session *models.AuthSession, now time.Time) (*models.AuthTokens, error) {
+	access, err := security.NewToken()
+	if err != nil {
+		return nil, apperrors.ErrInternal(err)
+	}
+	refresh, err := security.NewToken()
+	if err != nil {
+		return nil, apperrors.ErrInternal(err)
+	}
+
+	session.AccessTokenHash = security.HashToken(access)
+	session.RefreshTokenHash = security.HashToken(refresh)
+	session.ExpiresAt = now.Add(AccessTokenTTL)
+	session.RefreshExpiresAt = now.Add(RefreshTokenTTL)
+
+	return &models.AuthTokens{
+		AccessToken:      access,
+		ExpiresAt:        session.ExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: session.RefreshExpiresAt,
+	}, nil
+}
+
+func errSessionInvalid() *apperrors.AppError {
+	return apperrors.New(CodeInvalidToken, "la sesión venció o fue cerrada; inicie sesión nuevamente")
+}
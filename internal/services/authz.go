@@ -0,0 +1,20 @@
+// internal/services/authz.go
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+)
+
+// authorize verifica que quien llama (actor) tenga el permiso. Los servicios
+// lo llaman al inicio de cada operación protegida, sin importar desde qué
+// frontend llegue la petición.
+func authorize(actor *models.User, perm models.Permission) error {
+	if actor == nil {
+		return apperrors.ErrUnauthorized()
+	}
+	if !actor.Can(perm) {
+		return apperrors.New("FORBIDDEN", "no tiene permiso para realizar esta acción")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// internal/services/authz_test.go
+package services
+
+import (
+	"SDGEStreaming/internal/models"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	// Lo que puede cada rol, escrito a mano para que un cambio en
+	// rolePermissions tenga que pasar también por aquí.
+	granted := map[string][]models.Permission{
+		models.RoleViewer:        {},
+		models.RoleContentEditor: {models.PermManageContent, models.PermModerateReviews},
+		models.RoleBillingAdmin:  {models.PermViewUsers, models.PermViewBilling},
+		models.RoleSupport:       {models.PermViewUsers, models.PermManageUsers, models.PermRevokeSessions, models.PermModerateReviews},
+		models.RoleSuperAdmin: {
+			models.PermManageContent, models.PermViewUsers, models.PermManageRoles, models.PermManageUsers,
+			models.PermRevokeSessions, models.PermViewBilling, models.PermModerateReviews,
+		},
+	}
+	perms := granted[models.RoleSuperAdmin]
+
+	for _, role := range models.Roles() {
+		for _, perm := range perms {
+			want := "FORBIDDEN"
+			for _, p := range granted[role] {
+				if p == perm {
+					want = ""
+				}
+			}
+			t.Run(role+"/"+string(perm), func(t *testing.T) {
+				got := errorCode(authorize(&models.User{ID: 1, Role: role}, perm))
+				if got != want {
+					t.Errorf("authorize = %q, se esperaba %q", got, want)
+				}
+			})
+		}
+	}
+
+	tests := []struct {
+		name  string
+		actor *models.User
+		want  string
+	}{
+		{name: "sin sesión", actor: nil, want: "UNAUTHORIZED"},
+		{name: "rol desconocido", actor: &models.User{ID: 1, Role: "owner"}, want: "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(authorize(tt.actor, models.PermViewUsers)); got != tt.want {
+				t.Errorf("authorize = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserServiceChecksRoles(t *testing.T) {
+	s := newTestServices(t)
+	viewer := s.newUser(t, models.RoleViewer, models.DefaultCountry)
+	other := s.newUser(t, models.RoleViewer, models.DefaultCountry)
+	support := s.newUser(t, models.RoleSupport, models.DefaultCountry)
+	billing := s.newUser(t, models.RoleBillingAdmin, models.DefaultCountry)
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{
+			name: "un cliente ve su propia cuenta",
+			call: func() error { _, err := s.users.GetUser(viewer, viewer.ID); return err },
+		},
+		{
+			name: "un cliente no ve otra cuenta",
+			call: func() error { _, err := s.users.GetUser(viewer, other.ID); return err },
+			want: "FORBIDDEN",
+		},
+		{
+			name: "facturación ve otras cuentas",
+			call: func() error { _, err := s.users.GetUser(billing, other.ID); return err },
+		},
+		{
+			name: "sin sesión no se listan usuarios",
+			call: func() error { _, err := s.users.GetAllUsers(nil); return err },
+			want: "UNAUTHORIZED",
+		},
+		{
+			name: "soporte no asigna roles",
+			call: func() error { _, err := s.users.SetRole(support, other.ID, models.RoleContentEditor); return err },
+			want: "FORBIDDEN",
+		},
+		{
+			name: "super_admin asigna roles",
+			call: func() error { _, err := s.users.SetRole(s.admin, other.ID, models.RoleContentEditor); return err },
+		},
+		{
+			name: "super_admin no cambia su propio rol",
+			call: func() error { _, err := s.users.SetRole(s.admin, s.admin.ID, models.RoleViewer); return err },
+			want: "CONFLICT",
+		},
+		{
+			name: "soporte cambia el país de otra cuenta",
+			call: func() error { _, err := s.users.SetCountry(support, viewer.ID, "US"); return err },
+		},
+		{
+			name: "soporte no cambia el país de su cuenta",
+			call: func() error { _, err := s.users.SetCountry(support, support.ID, "US"); return err },
+			want: "CONFLICT",
+		},
+		{
+			name: "facturación no cambia países",
+			call: func() error { _, err := s.users.SetCountry(billing, viewer.ID, "US"); return err },
+			want: "FORBIDDEN",
+		},
+		{
+			name: "un cliente no publica contenido",
+			call: func() error {
+				_, err := s.content.CreateAudiovisual(viewer, "Matrix", "movie", "Acción", 120, "R", "", "", 1999, "", nil)
+				return err
+			},
+			want: "FORBIDDEN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.call()); got != tt.want {
+				t.Errorf("error %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// internal/services/billing_service.go
+// Consulta y exportación de facturas y del libro de pagos.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// BillingService expone las facturas a usuarios y administradores.
+type BillingService struct {
+	billingRepo repositories.BillingRepo
+}
+
+// NewBillingService crea una nueva instancia del servicio.
+func NewBillingService(billingRepo repositories.BillingRepo) *BillingService {
+	return &BillingService{billingRepo: billingRepo}
+}
+
+// GetInvoices devuelve las facturas del usuario, la más reciente primero.
+func (s *BillingService) GetInvoices(userID int) ([]models.Invoice, error) {
+	invoices, err := s.billingRepo.FindInvoicesByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return invoices, nil
+}
+
+// GetAllInvoices devuelve las facturas de todos los usuarios (requiere
+// permiso de facturación).
+func (s *BillingService) GetAllInvoices(actor *models.User) ([]models.Invoice, error) {
+	if err := authorize(actor, models.PermViewBilling); err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.billingRepo.FindAllInvoices()
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return invoices, nil
+}
+
+// ExportInvoicesCSV escribe todas las facturas en formato CSV y devuelve
+// cuántas se exportaron.
+func (s *BillingService) ExportInvoicesCSV(actor *models.User, w io.Writer) (int, error) {
+	invoices, err := s.GetAllInvoices(actor)
+	if err != nil {
+		return 0, err
+	}
+
+	out := csv.NewWriter(w)
+	header := []string{"factura", "usuario_id", "email", "plan_id", "descripcion", "monto", "reembolsado", "moneda", "periodo_inicio", "periodo_fin", "emitida"}
+	if err := out.Write(header); err != nil {
+		return 0, err
+	}
+
+	for _, inv := range invoices {
+		record := []string{
+			inv.Number,
+			strconv.Itoa(inv.UserID),
+			inv.UserEmail,
+			strconv.Itoa(inv.PlanID),
+			inv.Description,
+			centsToDecimal(inv.AmountCents),
+			centsToDecimal(inv.RefundedCents),
+			inv.Currency,
+			inv.PeriodStart.Format("2006-01-02"),
+			inv.PeriodEnd.Format("2006-01-02"),
+			inv.IssuedAt.Format("2006-01-02 15:04:05"),
+		}
+		if err := out.Write(record); err != nil {
+			return 0, err
+		}
+	}
+
+	out.Flush()
+	return len(invoices), out.Error()
+}
+
+// centsToDecimal formatea centavos como "12.34" (sin símbolo) para el CSV.
+func centsToDecimal(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
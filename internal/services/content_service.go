@@ -2,120 +2,791 @@
 package services
 
 import (
-	"SDGEStreaming/internal/db"
+	apperrors "SDGEStreaming/internal/errors"
 	"SDGEStreaming/internal/models"
 	"SDGEStreaming/internal/repositories"
+	"SDGEStreaming/internal/utils"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
+// CodeNotLicensed indica que el contenido no tiene licencia vigente en la
+// región del usuario.
+const CodeNotLicensed = "NOT_LICENSED"
+
+// RatingPriorVotes es cuántos votos con la media del catálogo se suman a
+// los de cada contenido para su puntaje bayesiano: con pocos votos el puntaje
+// queda cerca de la media, y con muchos, cerca de su propio promedio.
+const RatingPriorVotes = 5
+
+// RatingMeanRefreshInterval es cada cuánto se recalcula la media del catálogo
+// y, con ella, el puntaje bayesiano de todos los contenidos. Entre tanto, un
+// voto solo recalcula el contenido votado con la media guardada.
+const RatingMeanRefreshInterval = time.Hour
+
 // ContentService handles content-related business logic.
 type ContentService struct {
-	contentRepo repositories.ContentRepo
+	contentRepo     repositories.ContentRepo
+	ratingRepo      repositories.RatingRepo
+	userRatingRepo  repositories.UserRatingRepo
+	userRepo        repositories.UserRepo
+	seriesRepo      repositories.SeriesRepo
+	libraryRepo     repositories.LibraryRepo
+	parentalService *ParentalService
 }
 
-func NewContentService(contentRepo repositories.ContentRepo) *ContentService {
-	return &ContentService{contentRepo: contentRepo}
+func NewContentService(contentRepo repositories.ContentRepo, ratingRepo repositories.RatingRepo, userRatingRepo repositories.UserRatingRepo, userRepo repositories.UserRepo, seriesRepo repositories.SeriesRepo, libraryRepo repositories.LibraryRepo, parentalService *ParentalService) *ContentService {
+	return &ContentService{contentRepo: contentRepo, ratingRepo: ratingRepo, userRatingRepo: userRatingRepo, userRepo: userRepo, seriesRepo: seriesRepo, libraryRepo: libraryRepo, parentalService: parentalService}
 }
 
 // --- AUDIOVISUAL ---
-func (s *ContentService) CreateAudiovisual(title, contentType, genre string, duration int, ageRating, synopsis string, releaseYear int, director string) error {
+
+// defaultRenditions son las calidades de un contenido nuevo si no se indican.
+var defaultRenditions = []string{models.QualitySD, models.QualityHD}
+
+// CreateAudiovisual agrega un contenido con las calidades indicadas
+// (SD/HD/4K); sin calidades se publica en SD y HD. La clasificación es de
+// ratingSystem (MPAA si se omite). Requiere permiso para gestionar contenido.
+func (s *ContentService) CreateAudiovisual(actor *models.User, title, contentType, genre string, duration int, ageRating, ratingSystem, synopsis string, releaseYear int, director string, renditions []string) (*models.AudiovisualContent, error) {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return nil, err
+	}
+	if err := validateNewContent(title, genre, duration, ageRating); err != nil {
+		return nil, err
+	}
+	ratingSystem, err := s.checkRating(models.ContentTypeAudiovisual, ratingSystem, ageRating)
+	if err != nil {
+		return nil, err
+	}
+	if len(renditions) == 0 {
+		renditions = defaultRenditions
+	}
+	qualities := make([]string, 0, len(renditions))
+	seen := make(map[string]bool)
+	for _, q := range renditions {
+		q = strings.ToUpper(strings.TrimSpace(q))
+		if models.QualityRank(q) == 0 {
+			return nil, apperrors.New("INVALID_INPUT", fmt.Sprintf("calidad '%s' no válida (SD, HD o 4K)", q))
+		}
+		if !seen[q] {
+			seen[q] = true
+			qualities = append(qualities, q)
+		}
+	}
+	sort.Slice(qualities, func(i, j int) bool {
+		return models.QualityRank(qualities[i]) < models.QualityRank(qualities[j])
+	})
+
 	content := &models.AudiovisualContent{
-		Title:       title,
-		Type:        contentType,
-		Genre:       genre,
-		Duration:    duration,
-		AgeRating:   ageRating,
-		Synopsis:    synopsis,
-		ReleaseYear: releaseYear,
-		Director:    director,
+		Title:        title,
+		Type:         contentType,
+		Genre:        genre,
+		Duration:     duration,
+		AgeRating:    ageRating,
+		RatingSystem: ratingSystem,
+		Synopsis:     synopsis,
+		ReleaseYear:  releaseYear,
+		Director:     director,
+		IsAvailable:  true,
+	}
+	if err := s.contentRepo.CreateAudiovisual(content); err != nil {
+		return nil, apperrors.ErrDatabase(err)
 	}
-	return s.contentRepo.CreateAudiovisual(content)
+
+	for _, q := range qualities {
+		rendition := &models.Rendition{
+			ContentID:    content.ID,
+			Quality:      q,
+			ManifestPath: fmt.Sprintf("av/%d/%s.m3u8", content.ID, strings.ToLower(q)),
+		}
+		if err := s.contentRepo.AddRendition(rendition); err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+	}
+	content.Renditions = qualities
+	return content, nil
 }
 
-func (s *ContentService) GetAudiovisualByID(id int) (*models.AudiovisualContent, error) {
-	return s.contentRepo.FindAudiovisualByID(id)
+// GetAudiovisualByID devuelve el contenido junto con sus calidades
+// disponibles y los países de su licencia, si el perfil puede verlo en now.
+func (s *ContentService) GetAudiovisualByID(profile *models.Profile, id int, now time.Time) (*models.AudiovisualContent, error) {
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parentalService, profile, models.ContentRef{ContentID: id, ContentType: models.ContentTypeAudiovisual}, now); err != nil {
+		return nil, err
+	}
+	return s.audiovisualDetail(id)
+}
+
+// audiovisualDetail es GetAudiovisualByID sin la licencia ni el perfil, para
+// la gestión del catálogo.
+func (s *ContentService) audiovisualDetail(id int) (*models.AudiovisualContent, error) {
+	content, err := s.contentRepo.FindAudiovisualByID(id)
+	if err != nil {
+		return nil, err
+	}
+	license, err := s.contentRepo.FindLicense(id, models.ContentTypeAudiovisual)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	content.Countries = license.Countries
+
+	renditions, err := s.contentRepo.FindRenditions(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for _, r := range renditions {
+		content.Renditions = append(content.Renditions, r.Quality)
+	}
+	return content, nil
 }
 
 func (s *ContentService) GetAllAudiovisual() ([]models.AudiovisualContent, error) {
 	return s.contentRepo.FindAllAudiovisual()
 }
 
-func (s *ContentService) GetAllAudiovisualForUser(ageRating string) ([]models.AudiovisualContent, error) {
-	return s.contentRepo.FindAllAudiovisualAllowed(ageRating)
+// GetAllAudiovisualForProfile devuelve el catálogo que puede ver el perfil,
+// aplicando su clasificación, su control parental y las licencias vigentes
+// en now para la región de la cuenta.
+func (s *ContentService) GetAllAudiovisualForProfile(profile *models.Profile, now time.Time) ([]models.AudiovisualContent, error) {
+	filter, err := s.profileFilter(profile, models.ContentTypeAudiovisual, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.contentRepo.FindAllAudiovisualAllowed(filter)
+}
+
+// QueryAudiovisual recorre por páginas el catálogo que puede ver el perfil,
+// con los filtros y el orden de q.
+func (s *ContentService) QueryAudiovisual(profile *models.Profile, q models.CatalogQuery, now time.Time) (*models.Page[models.AudiovisualContent], error) {
+	q, err := normalizeQuery(q, models.ContentTypeAudiovisual)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := s.profileFilter(profile, models.ContentTypeAudiovisual, now)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.contentRepo.QueryAudiovisual(filter, q)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return page, nil
+}
+
+// --- SERIES ---
+
+// GetSeries devuelve una serie con sus temporadas y episodios, si el perfil
+// puede verla en now.
+func (s *ContentService) GetSeries(profile *models.Profile, id int, now time.Time) (*models.Series, error) {
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parentalService, profile, models.ContentRef{ContentID: id, ContentType: models.ContentTypeAudiovisual}, now); err != nil {
+		return nil, err
+	}
+	return s.seriesDetail(id)
 }
 
-func (s *ContentService) SearchAudiovisualByTitle(title string) ([]models.AudiovisualContent, error) {
-	return s.contentRepo.SearchAudiovisualByTitle(title)
+// GetManagedSeries es GetSeries para la gestión del catálogo: no mira la
+// licencia y requiere permiso para gestionar contenido.
+func (s *ContentService) GetManagedSeries(actor *models.User, id int) (*models.Series, error) {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return nil, err
+	}
+	return s.seriesDetail(id)
+}
+
+// seriesDetail es GetSeries sin la licencia ni el perfil.
+func (s *ContentService) seriesDetail(id int) (*models.Series, error) {
+	content, err := s.audiovisualDetail(id)
+	if err != nil {
+		return nil, err
+	}
+	if content.Type != models.TypeSeries {
+		return nil, apperrors.New("INVALID_INPUT", fmt.Sprintf("'%s' no es una serie", content.Title))
+	}
+
+	seasons, err := s.seriesRepo.FindSeasons(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return &models.Series{AudiovisualContent: *content, Seasons: seasons}, nil
+}
+
+// GetEpisode devuelve un episodio con su serie y temporada, si el perfil
+// puede ver la serie en now.
+func (s *ContentService) GetEpisode(profile *models.Profile, id int, now time.Time) (*models.Episode, error) {
+	episode, err := s.seriesRepo.FindEpisodeByID(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if episode == nil {
+		return nil, apperrors.ErrNotFound("episodio")
+	}
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parentalService, profile, models.ContentRef{ContentID: episode.SeriesID, ContentType: models.ContentTypeAudiovisual}, now); err != nil {
+		return nil, err
+	}
+	return episode, nil
+}
+
+// AddSeason agrega una temporada a una serie. Requiere permiso para
+// gestionar contenido.
+func (s *ContentService) AddSeason(actor *models.User, seriesID, number int, title string) (*models.Season, error) {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return nil, err
+	}
+	if number <= 0 {
+		return nil, apperrors.ErrInvalidInput("number")
+	}
+	if _, err := s.seriesDetail(seriesID); err != nil {
+		return nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = fmt.Sprintf("Temporada %d", number)
+	}
+	season := &models.Season{SeriesID: seriesID, Number: number, Title: title, Episodes: []models.Episode{}}
+	if err := s.seriesRepo.CreateSeason(season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// AddEpisode agrega un episodio a una temporada. Requiere permiso para
+// gestionar contenido.
+func (s *ContentService) AddEpisode(actor *models.User, seasonID, number int, title string, duration int, synopsis string) (*models.Episode, error) {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return nil, err
+	}
+	if number <= 0 {
+		return nil, apperrors.ErrInvalidInput("number")
+	}
+	if utils.IsEmpty(title) {
+		return nil, apperrors.ErrInvalidInput("title")
+	}
+	if duration <= 0 {
+		return nil, apperrors.ErrInvalidInput("duration")
+	}
+
+	season, err := s.seriesRepo.FindSeasonByID(seasonID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if season == nil {
+		return nil, apperrors.New("NOT_FOUND", "temporada no encontrada")
+	}
+
+	episode := &models.Episode{
+		SeasonID:     season.ID,
+		SeriesID:     season.SeriesID,
+		SeasonNumber: season.Number,
+		Number:       number,
+		Title:        strings.TrimSpace(title),
+		Duration:     duration,
+		Synopsis:     strings.TrimSpace(synopsis),
+	}
+	if err := s.seriesRepo.CreateEpisode(episode); err != nil {
+		return nil, err
+	}
+	return episode, nil
 }
 
 // --- AUDIO ---
-func (s *ContentService) CreateAudio(title, contentType, genre string, duration int, ageRating, artist, album string, trackNumber int) error {
+func (s *ContentService) CreateAudio(actor *models.User, title, contentType, genre string, duration int, ageRating, ratingSystem, artist, album string, trackNumber int) (*models.AudioContent, error) {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return nil, err
+	}
+	if err := validateNewContent(title, genre, duration, ageRating); err != nil {
+		return nil, err
+	}
+	ratingSystem, err := s.checkRating(models.ContentTypeAudio, ratingSystem, ageRating)
+	if err != nil {
+		return nil, err
+	}
+
 	content := &models.AudioContent{
-		Title:       title,
-		Type:        contentType,
-		Genre:       genre,
-		Duration:    duration,
-		AgeRating:   ageRating,
-		Artist:      artist,
-		Album:       album,
-		TrackNumber: trackNumber,
+		Title:        title,
+		Type:         contentType,
+		Genre:        genre,
+		Duration:     duration,
+		AgeRating:    ageRating,
+		RatingSystem: ratingSystem,
+		Artist:       artist,
+		Album:        album,
+		TrackNumber:  trackNumber,
+		IsAvailable:  true,
+	}
+	if err := s.linkLibrary(content); err != nil {
+		return nil, err
+	}
+	if err := s.contentRepo.CreateAudio(content); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return content, nil
+}
+
+// linkLibrary asocia el audio a su programa (podcasts) o a su artista y
+// álbum, creándolos si todavía no existen.
+func (s *ContentService) linkLibrary(content *models.AudioContent) error {
+	artist := strings.TrimSpace(content.Artist)
+	if artist == "" {
+		return nil
+	}
+	if content.Type == models.TypePodcast {
+		show, err := s.libraryRepo.EnsureShow(artist)
+		if err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+		content.ShowID = &show.ID
+		return nil
+	}
+
+	a, err := s.libraryRepo.EnsureArtist(artist)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	content.ArtistID = &a.ID
+	if album := strings.TrimSpace(content.Album); album != "" {
+		al, err := s.libraryRepo.EnsureAlbum(a.ID, album)
+		if err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+		content.AlbumID = &al.ID
+	}
+	return nil
+}
+
+// validateNewContent verifica los campos obligatorios comunes a todo contenido.
+func validateNewContent(title, genre string, duration int, ageRating string) error {
+	if utils.IsEmpty(title) {
+		return apperrors.ErrInvalidInput("title")
+	}
+	if utils.IsEmpty(genre) {
+		return apperrors.ErrInvalidInput("genre")
+	}
+	if duration <= 0 {
+		return apperrors.ErrInvalidInput("duration")
+	}
+	if utils.IsEmpty(ageRating) {
+		return apperrors.ErrInvalidInput("age_rating")
+	}
+	return nil
+}
+
+// checkRating verifica que la clasificación exista en el sistema indicado (o
+// en el del catálogo si se omite) y devuelve el sistema a guardar.
+func (s *ContentService) checkRating(contentType, systemCode, ratingCode string) (string, error) {
+	systemCode = strings.ToUpper(strings.TrimSpace(systemCode))
+	if systemCode == "" {
+		systemCode = models.DefaultRatingSystem(contentType)
+	}
+
+	system, err := s.ratingRepo.FindSystem(systemCode)
+	if err != nil {
+		return "", apperrors.ErrDatabase(err)
+	}
+	if system == nil || system.ContentType != contentType {
+		return "", apperrors.New("INVALID_INPUT", fmt.Sprintf("el sistema de clasificación '%s' no existe para contenido %s", systemCode, contentType))
+	}
+	for _, r := range system.Ratings {
+		if r.Code == ratingCode {
+			return systemCode, nil
+		}
+	}
+	codes := make([]string, 0, len(system.Ratings))
+	for _, r := range system.Ratings {
+		codes = append(codes, r.Code)
+	}
+	return "", apperrors.New("INVALID_INPUT", fmt.Sprintf("clasificación '%s' no válida en %s (%s)", ratingCode, systemCode, strings.Join(codes, ", ")))
+}
+
+// --- SISTEMAS DE CLASIFICACIÓN ---
+
+// GetRatingSystems devuelve los sistemas de clasificación registrados.
+func (s *ContentService) GetRatingSystems() ([]models.RatingSystem, error) {
+	systems, err := s.ratingRepo.FindSystems()
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
 	}
-	return s.contentRepo.CreateAudio(content)
+	return systems, nil
 }
 
-func (s *ContentService) GetAudioByID(id int) (*models.AudioContent, error) {
-	return s.contentRepo.FindAudioByID(id)
+// CreateRatingSystem registra un sistema de clasificación con sus niveles de
+// madurez. Requiere permiso para gestionar contenido.
+func (s *ContentService) CreateRatingSystem(actor *models.User, system *models.RatingSystem) error {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return err
+	}
+
+	system.Code = strings.ToUpper(strings.TrimSpace(system.Code))
+	system.Name = strings.TrimSpace(system.Name)
+	system.Country = strings.ToUpper(strings.TrimSpace(system.Country))
+	if utils.IsEmpty(system.Code) {
+		return apperrors.ErrInvalidInput("code")
+	}
+	if utils.IsEmpty(system.Name) {
+		return apperrors.ErrInvalidInput("name")
+	}
+	if !models.IsContentType(system.ContentType) {
+		return apperrors.ErrInvalidInput("content_type")
+	}
+	if len(system.Ratings) == 0 {
+		return apperrors.New("INVALID_INPUT", "el sistema debe tener al menos una clasificación")
+	}
+	for i := range system.Ratings {
+		r := &system.Ratings[i]
+		r.Code = strings.TrimSpace(r.Code)
+		r.Description = strings.TrimSpace(r.Description)
+		if r.Code == "" {
+			return apperrors.ErrInvalidInput("ratings.code")
+		}
+		if r.Level < 0 || r.Level > models.MaxMaturityLevel {
+			return apperrors.New("INVALID_INPUT", fmt.Sprintf("el nivel de %s debe estar entre 0 y %d", r.Code, models.MaxMaturityLevel))
+		}
+		r.SystemCode = system.Code
+	}
+
+	return s.ratingRepo.CreateSystem(system)
+}
+
+// GetAudioByID devuelve el contenido junto con los países de su licencia,
+// si el perfil puede verlo en now.
+func (s *ContentService) GetAudioByID(profile *models.Profile, id int, now time.Time) (*models.AudioContent, error) {
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parentalService, profile, models.ContentRef{ContentID: id, ContentType: models.ContentTypeAudio}, now); err != nil {
+		return nil, err
+	}
+	content, err := s.contentRepo.FindAudioByID(id)
+	if err != nil {
+		return nil, err
+	}
+	license, err := s.contentRepo.FindLicense(id, models.ContentTypeAudio)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	content.Countries = license.Countries
+	return content, nil
 }
 
 func (s *ContentService) GetAllAudio() ([]models.AudioContent, error) {
 	return s.contentRepo.FindAllAudio()
 }
 
-func (s *ContentService) GetAllAudioForUser(ageRating string) ([]models.AudioContent, error) {
-	return s.contentRepo.FindAllAudioAllowed(ageRating)
+func (s *ContentService) GetAllAudioForProfile(profile *models.Profile, now time.Time) ([]models.AudioContent, error) {
+	filter, err := s.profileFilter(profile, models.ContentTypeAudio, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.contentRepo.FindAllAudioAllowed(filter)
 }
 
-func (s *ContentService) SearchAudioByTitle(title string) ([]models.AudioContent, error) {
-	return s.contentRepo.SearchAudioByTitle(title)
+// QueryAudio es el equivalente de QueryAudiovisual para audio.
+func (s *ContentService) QueryAudio(profile *models.Profile, q models.CatalogQuery, now time.Time) (*models.Page[models.AudioContent], error) {
+	q, err := normalizeQuery(q, models.ContentTypeAudio)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := s.profileFilter(profile, models.ContentTypeAudio, now)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.contentRepo.QueryAudio(filter, q)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return page, nil
 }
 
-// --- CALIFICACIONES ---
-func (s *ContentService) RateContent(userID, contentID int, contentType string, rating float64) error {
-	if rating < 1.0 || rating > 10.0 {
-		return fmt.Errorf("la calificación debe estar entre 1.0 y 10.0")
+// normalizeQuery valida una consulta de catálogo y completa el orden y el
+// tamaño de página por defecto. El audio no tiene año de estreno, así que no
+// se puede filtrar ni ordenar por él.
+func normalizeQuery(q models.CatalogQuery, contentType string) (models.CatalogQuery, error) {
+	q.Genre = strings.TrimSpace(q.Genre)
+	q.Kind = strings.TrimSpace(q.Kind)
+	q.AgeRating = strings.TrimSpace(q.AgeRating)
+
+	switch {
+	case q.YearFrom < 0:
+		return q, apperrors.ErrInvalidInput("year_from")
+	case q.YearTo < 0 || (q.YearTo > 0 && q.YearTo < q.YearFrom):
+		return q, apperrors.ErrInvalidInput("year_to")
+	case q.MinDuration < 0:
+		return q, apperrors.ErrInvalidInput("min_duration")
+	case q.MaxDuration < 0 || (q.MaxDuration > 0 && q.MaxDuration < q.MinDuration):
+		return q, apperrors.ErrInvalidInput("max_duration")
+	case q.MinRating < 0 || q.MinRating > 10:
+		return q, apperrors.ErrInvalidInput("min_rating")
+	case q.Offset < 0:
+		return q, apperrors.ErrInvalidInput("offset")
+	case q.Limit < 0:
+		return q, apperrors.ErrInvalidInput("limit")
+	}
+
+	if q.Sort == "" {
+		q.Sort = models.SortRating
+	}
+	if !slices.Contains(models.CatalogSorts, q.Sort) {
+		return q, apperrors.ErrInvalidInput("sort")
+	}
+	if contentType == models.ContentTypeAudio && (q.YearFrom > 0 || q.YearTo > 0 || q.Sort == models.SortNewest) {
+		return q, apperrors.New("INVALID_INPUT", "el contenido de audio no tiene año de estreno")
+	}
+
+	if q.Limit == 0 {
+		q.Limit = models.DefaultPageSize
+	}
+	if q.Limit > models.MaxPageSize {
+		q.Limit = models.MaxPageSize
 	}
+	return q, nil
+}
 
-	conn := db.GetDB()
+// allowedRefs indica, de refs, cuáles puede ver el perfil en now: los que
+// pasan su filtro de edad y control parental y tienen licencia en su región.
+func (s *ContentService) allowedRefs(profile *models.Profile, refs []models.ContentRef, now time.Time) (map[models.ContentRef]bool, error) {
+	ids := make(map[string][]int)
+	for _, ref := range refs {
+		ids[ref.ContentType] = append(ids[ref.ContentType], ref.ContentID)
+	}
 
-	// Insertar o actualizar calificación
-	_, err := conn.Exec(`
-		INSERT INTO user_ratings (user_id, content_id, content_type, rating)
-		VALUES (?, ?, ?, ?)
-		ON CONFLICT(user_id, content_id, content_type) 
-		DO UPDATE SET rating = ?, rated_at = CURRENT_TIMESTAMP
-	`, userID, contentID, contentType, rating, rating)
+	allowed := make(map[models.ContentRef]bool, len(refs))
+	for _, catalog := range models.Catalogs {
+		if len(ids[catalog.ContentType]) == 0 {
+			continue
+		}
+		filter, err := s.profileFilter(profile, catalog.ContentType, now)
+		if err != nil {
+			return nil, err
+		}
+		found, err := s.contentRepo.FindAllowedIDs(filter, catalog.ContentType, ids[catalog.ContentType])
+		if err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+		for _, id := range found {
+			allowed[models.ContentRef{ContentID: id, ContentType: catalog.ContentType}] = true
+		}
+	}
+	return allowed, nil
+}
 
+// profileFilter combina el filtro parental del perfil con la región de la
+// cuenta y el momento de la consulta.
+func (s *ContentService) profileFilter(profile *models.Profile, contentType string, now time.Time) (models.ContentFilter, error) {
+	filter, err := s.parentalService.Filter(profile, contentType)
 	if err != nil {
-		return fmt.Errorf("error al guardar calificación: %w", err)
+		return models.ContentFilter{}, err
 	}
+	user, err := s.userRepo.FindByID(profile.UserID)
+	if err != nil {
+		return models.ContentFilter{}, apperrors.ErrNotFound("usuario")
+	}
+	filter.Country = user.Country
+	filter.Now = now
+	return filter, nil
+}
 
-	// Recalcular promedio
-	return s.updateAverageRating(contentID, contentType)
+// --- LICENCIAS ---
+
+// GetLicense devuelve la ventana y los países de la licencia de un contenido.
+func (s *ContentService) GetLicense(contentID int, contentType string) (*models.ContentLicense, error) {
+	if !models.IsContentType(contentType) {
+		return nil, apperrors.ErrInvalidInput("content_type")
+	}
+	return s.contentRepo.FindLicense(contentID, contentType)
 }
 
-func (s *ContentService) updateAverageRating(contentID int, contentType string) error {
-	conn := db.GetDB()
-	var avgRating float64
+// SetLicense reemplaza la licencia de un contenido: la ventana (cualquiera de
+// las fechas puede faltar) y los países donde se puede ver (vacío: todo el
+// mundo). Requiere permiso para gestionar contenido.
+func (s *ContentService) SetLicense(actor *models.User, license *models.ContentLicense) error {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return err
+	}
+	if _, err := s.GetLicense(license.ContentID, license.ContentType); err != nil {
+		return err
+	}
+	if license.AvailableFrom != nil && license.AvailableUntil != nil && !license.AvailableFrom.Before(*license.AvailableUntil) {
+		return apperrors.New("INVALID_INPUT", "la fecha de inicio de la licencia debe ser anterior a la de fin")
+	}
 
-	err := conn.QueryRow(`
-		SELECT COALESCE(AVG(rating), 0.0)
-		FROM user_ratings
-		WHERE content_id = ? AND content_type = ?
-	`, contentID, contentType).Scan(&avgRating)
+	countries := make([]string, 0, len(license.Countries))
+	for _, c := range license.Countries {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if !utils.IsValidCountry(c) {
+			return apperrors.New("INVALID_INPUT", fmt.Sprintf("país '%s' no válido (código ISO de 2 letras)", c))
+		}
+		countries = append(countries, c)
+	}
+	license.Countries = countries
+
+	if err := s.contentRepo.SaveLicense(license); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
 
+// findItem devuelve la ficha común de un contenido de cualquier catálogo.
+func findItem(contentRepo repositories.ContentRepo, ref models.ContentRef) (*models.CatalogItem, error) {
+	catalog, ok := models.CatalogOf(ref.ContentType)
+	if !ok {
+		return nil, apperrors.ErrInvalidInput("content_type")
+	}
+	item, err := contentRepo.FindCatalogItem(ref)
 	if err != nil {
-		return fmt.Errorf("error al calcular promedio: %w", err)
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if item == nil {
+		return nil, apperrors.ErrNotFound(catalog.Label)
 	}
+	return item, nil
+}
+
+// checkVisible rechaza ref si no existe, si el perfil no puede verlo en now
+// en la región de su cuenta o si lo oculta su control parental: lo mismo que
+// aplican el catálogo y la búsqueda, pero con un error que explica el motivo.
+func checkVisible(contentRepo repositories.ContentRepo, userRepo repositories.UserRepo, parental *ParentalService, profile *models.Profile, ref models.ContentRef, now time.Time) error {
+	if _, err := findItem(contentRepo, ref); err != nil {
+		return err
+	}
+	user, err := userRepo.FindByID(profile.UserID)
+	if err != nil {
+		return apperrors.ErrNotFound("usuario")
+	}
+	if err := checkLicense(contentRepo, ref.ContentID, ref.ContentType, user.Country, now); err != nil {
+		return err
+	}
+	return parental.CheckVisible(profile, ref.ContentID, ref.ContentType)
+}
+
+// checkLicense rechaza el contenido si en now no tiene licencia vigente en
+// el país indicado.
+func checkLicense(contentRepo repositories.ContentRepo, contentID int, contentType, country string, now time.Time) error {
+	license, err := contentRepo.FindLicense(contentID, contentType)
+	if err != nil {
+		return err
+	}
+	if !license.InWindow(now) {
+		return apperrors.New(CodeNotLicensed, "este contenido no está disponible en este momento")
+	}
+	if !license.AllowsCountry(country) {
+		return apperrors.New(CodeNotLicensed, fmt.Sprintf("este contenido no está disponible en su región (%s)", country))
+	}
+	return nil
+}
+
+// --- CALIFICACIONES ---
+
+// RateContent guarda la calificación del perfil y recalcula los votos, el
+// promedio y el puntaje bayesiano. Solo se califica lo que el perfil puede
+// ver en now.
+func (s *ContentService) RateContent(profile *models.Profile, ref models.ContentRef, rating float64, now time.Time) error {
+	if rating < models.MinUserRating || rating > models.MaxUserRating {
+		return apperrors.New("INVALID_INPUT", "la calificación debe estar entre 1.0 y 10.0")
+	}
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parentalService, profile, ref, now); err != nil {
+		return err
+	}
+
+	if err := s.userRatingRepo.Save(profile.ID, ref, rating); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return s.refreshRatingStats(ref)
+}
+
+// DeleteRating borra la calificación que el perfil le dio al contenido.
+func (s *ContentService) DeleteRating(profileID int, ref models.ContentRef) error {
+	if !models.IsContentType(ref.ContentType) {
+		return apperrors.ErrInvalidInput("content_type")
+	}
+	deleted, err := s.userRatingRepo.Delete(profileID, ref)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if !deleted {
+		return apperrors.New("NOT_FOUND", "calificación no encontrada")
+	}
+	return s.refreshRatingStats(ref)
+}
 
-	return s.contentRepo.UpdateAverageRating(contentID, contentType, avgRating)
+// GetProfileRatings devuelve las calificaciones del perfil, de la más
+// reciente a la más antigua, con la ficha de cada contenido.
+func (s *ContentService) GetProfileRatings(profileID int) ([]models.UserRating, error) {
+	ratings, err := s.userRatingRepo.FindByProfileID(profileID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	refs := make([]models.ContentRef, len(ratings))
+	for i := range ratings {
+		refs[i] = ratings[i].ContentRef
+	}
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range ratings {
+		if item, ok := items[ratings[i].ContentRef]; ok {
+			ratings[i].Item = &item
+		}
+	}
+	return ratings, nil
+}
+
+// GetRatingSummary devuelve el promedio, los votos, el puntaje bayesiano y
+// el histograma de calificaciones del contenido, si el perfil puede verlo en
+// now.
+func (s *ContentService) GetRatingSummary(profile *models.Profile, ref models.ContentRef, now time.Time) (*models.RatingSummary, error) {
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parentalService, profile, ref, now); err != nil {
+		return nil, err
+	}
+	item, err := findItem(s.contentRepo, ref)
+	if err != nil {
+		return nil, err
+	}
+	histogram, err := s.userRatingRepo.FindHistogram(ref)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return &models.RatingSummary{
+		ContentRef: ref,
+		Average:    item.AverageRating,
+		Count:      item.RatingCount,
+		Weighted:   item.WeightedRating,
+		Histogram:  histogram,
+	}, nil
+}
+
+// refreshRatingStats recalcula el puntaje del contenido votado y, si no hay
+// media guardada o tiene más de RatingMeanRefreshInterval, la del catálogo.
+func (s *ContentService) refreshRatingStats(ref models.ContentRef) error {
+	if err := s.userRatingRepo.RefreshStats(ref, RatingPriorVotes); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	now := time.Now()
+	mean, refreshedAt, err := s.userRatingRepo.FindMean(ref.ContentType)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if mean != nil && now.Sub(refreshedAt) < RatingMeanRefreshInterval {
+		return nil
+	}
+	if err := s.userRatingRepo.RefreshMean(ref.ContentType, RatingPriorVotes, now); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// ratedContent devuelve los contenidos que calificó el perfil.
+func (s *ContentService) ratedContent(profileID int) ([]models.ContentRef, error) {
+	ratings, err := s.userRatingRepo.FindByProfileID(profileID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	refs := make([]models.ContentRef, len(ratings))
+	for i := range ratings {
+		refs[i] = ratings[i].ContentRef
+	}
+	return refs, nil
 }
@@ -0,0 +1,228 @@
+// internal/services/content_service_test.go
+package services
+
+import (
+	"SDGEStreaming/internal/models"
+	"math"
+	"testing"
+	"time"
+)
+
+// rate califica el contenido con el perfil principal de una cuenta nueva.
+func (s *testServices) rate(t *testing.T, ref models.ContentRef, rating float64) {
+	t.Helper()
+	voter := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+	if err := s.content.RateContent(voter, ref, rating, time.Now()); err != nil {
+		t.Fatalf("RateContent(%v, %.1f): %v", ref, rating, err)
+	}
+}
+
+func (s *testServices) weighted(t *testing.T, ref models.ContentRef) float64 {
+	t.Helper()
+	summary, err := s.content.GetRatingSummary(s.mainProfile(t, s.admin), ref, time.Now())
+	if err != nil {
+		t.Fatalf("GetRatingSummary(%v): %v", ref, err)
+	}
+	return summary.Weighted
+}
+
+func assertRating(t *testing.T, what string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %.4f, se esperaba %.4f", what, got, want)
+	}
+}
+
+func TestRateContentFirstVote(t *testing.T) {
+	s := newTestServices(t)
+	matrix := s.newMovie(t, "Matrix", "R").Ref()
+	other := s.newMovie(t, "Otra", "PG").Ref()
+
+	// Sin votos en el catálogo, el primero no se acerca a una media de 0.
+	s.rate(t, matrix, 9)
+	assertRating(t, "puntaje tras el primer voto", s.weighted(t, matrix), 9)
+
+	// La media ya quedó guardada: el voto siguiente solo recalcula su fila.
+	s.rate(t, other, 3)
+	assertRating(t, "puntaje del segundo título", s.weighted(t, other), (3+RatingPriorVotes*9)/(1+RatingPriorVotes))
+	assertRating(t, "puntaje del primer título", s.weighted(t, matrix), 9)
+}
+
+func TestRatingStatsBayesianScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes [][]float64 // votos de cada título
+		want  []float64   // puntaje de cada título con la media recalculada
+	}{
+		{
+			name:  "un solo voto en el catálogo",
+			votes: [][]float64{{9}},
+			want:  []float64{9},
+		},
+		{
+			// media = 22 / 4 = 5.5
+			name:  "pocos votos se acercan a la media",
+			votes: [][]float64{{10}, {4, 4, 4}},
+			want:  []float64{(10 + 5*5.5) / 6, (12 + 5*5.5) / 8},
+		},
+		{
+			// media = 86.5 / 10 = 8.65
+			name:  "un voto alto pierde más que muchos votos",
+			votes: [][]float64{{10}, {8.5, 8.5, 8.5, 8.5, 8.5, 8.5, 8.5, 8.5, 8.5}},
+			want:  []float64{(10 + 5*8.65) / 6, (76.5 + 5*8.65) / 14},
+		},
+		{
+			name:  "sin votos queda en 0",
+			votes: [][]float64{{7, 8}, {}},
+			want:  []float64{7.5, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			refs := make([]models.ContentRef, len(tt.votes))
+			for i, votes := range tt.votes {
+				refs[i] = s.newMovie(t, "Título", "PG").Ref()
+				for _, v := range votes {
+					s.rate(t, refs[i], v)
+				}
+			}
+			if err := s.content.userRatingRepo.RefreshMean(models.ContentTypeAudiovisual, RatingPriorVotes, time.Now()); err != nil {
+				t.Fatalf("RefreshMean: %v", err)
+			}
+			for i, ref := range refs {
+				assertRating(t, "puntaje", s.weighted(t, ref), tt.want[i])
+			}
+		})
+	}
+}
+
+func TestDeleteLastRatingResetsScore(t *testing.T) {
+	s := newTestServices(t)
+	ref := s.newMovie(t, "Matrix", "R").Ref()
+	voter := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+
+	if err := s.content.RateContent(voter, ref, 8, time.Now()); err != nil {
+		t.Fatalf("RateContent: %v", err)
+	}
+	if err := s.content.DeleteRating(voter.ID, ref); err != nil {
+		t.Fatalf("DeleteRating: %v", err)
+	}
+	summary, err := s.content.GetRatingSummary(voter, ref, time.Now())
+	if err != nil {
+		t.Fatalf("GetRatingSummary: %v", err)
+	}
+	if summary.Count != 0 || summary.Weighted != 0 {
+		t.Errorf("votos %d, puntaje %.2f; se esperaba un título sin votos", summary.Count, summary.Weighted)
+	}
+}
+
+func TestLicenseWindowsAndTerritories(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+	tests := []struct {
+		name      string
+		from      *time.Time
+		until     *time.Time
+		countries []string
+		country   string // región de la cuenta
+		want      string
+	}{
+		{name: "sin restricciones", country: "EC"},
+		{name: "dentro de la ventana", from: at(-time.Hour), until: at(time.Hour), country: "EC"},
+		{name: "la ventana todavía no empieza", from: at(time.Hour), country: "EC", want: CodeNotLicensed},
+		{name: "la ventana ya terminó", until: at(-time.Hour), country: "EC", want: CodeNotLicensed},
+		{name: "solo fecha de inicio, ya pasada", from: at(-time.Hour), country: "EC"},
+		{name: "región incluida", countries: []string{"EC", "US"}, country: "EC"},
+		{name: "región excluida", countries: []string{"US"}, country: "EC", want: CodeNotLicensed},
+		{name: "los países se guardan en mayúsculas", countries: []string{" ec "}, country: "EC"},
+		{name: "ventana vigente en otra región", from: at(-time.Hour), until: at(time.Hour), countries: []string{"US"}, country: "EC", want: CodeNotLicensed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			movie := s.newMovie(t, "Matrix", "PG")
+			license := &models.ContentLicense{
+				ContentID:      movie.ID,
+				ContentType:    models.ContentTypeAudiovisual,
+				AvailableFrom:  tt.from,
+				AvailableUntil: tt.until,
+				Countries:      tt.countries,
+			}
+			if err := s.content.SetLicense(s.admin, license); err != nil {
+				t.Fatalf("SetLicense: %v", err)
+			}
+			profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, tt.country))
+
+			_, err := s.content.GetAudiovisualByID(profile, movie.ID, now)
+			if got := errorCode(err); got != tt.want {
+				t.Errorf("GetAudiovisualByID = %q, se esperaba %q", got, tt.want)
+			}
+			catalog, err := s.content.GetAllAudiovisualForProfile(profile, now)
+			if err != nil {
+				t.Fatalf("GetAllAudiovisualForProfile: %v", err)
+			}
+			if listed := len(catalog) == 1; listed != (tt.want == "") {
+				t.Errorf("en el catálogo: %v, se esperaba %v", listed, tt.want == "")
+			}
+		})
+	}
+}
+
+func TestSetLicenseValidates(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	tests := []struct {
+		name    string
+		license models.ContentLicense
+		actor   string
+		want    string
+	}{
+		{name: "la ventana termina antes de empezar", license: models.ContentLicense{AvailableFrom: &later, AvailableUntil: &now}, actor: models.RoleContentEditor, want: "INVALID_INPUT"},
+		{name: "país que no es ISO", license: models.ContentLicense{Countries: []string{"ECU"}}, actor: models.RoleContentEditor, want: "INVALID_INPUT"},
+		{name: "un cliente no cambia licencias", license: models.ContentLicense{Countries: []string{"EC"}}, actor: models.RoleViewer, want: "FORBIDDEN"},
+		{name: "licencia válida", license: models.ContentLicense{AvailableFrom: &now, AvailableUntil: &later, Countries: []string{"EC"}}, actor: models.RoleContentEditor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			license := tt.license
+			license.ContentID = s.newMovie(t, "Matrix", "PG").ID
+			license.ContentType = models.ContentTypeAudiovisual
+			err := s.content.SetLicense(s.newUser(t, tt.actor, models.DefaultCountry), &license)
+			if got := errorCode(err); got != tt.want {
+				t.Errorf("SetLicense = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnlicensedContentIsRejectedEverywhere(t *testing.T) {
+	s := newTestServices(t)
+	movie := s.newMovie(t, "Matrix", "PG")
+	ref := movie.Ref()
+	if err := s.content.SetLicense(s.admin, &models.ContentLicense{ContentID: movie.ID, ContentType: ref.ContentType, Countries: []string{"US"}}); err != nil {
+		t.Fatalf("SetLicense: %v", err)
+	}
+	profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, "EC"))
+	now := time.Now()
+
+	calls := map[string]func() error{
+		"ficha":          func() error { _, err := s.content.GetAudiovisualByID(profile, movie.ID, now); return err },
+		"calificaciones": func() error { _, err := s.content.GetRatingSummary(profile, ref, now); return err },
+		"calificar":      func() error { return s.content.RateContent(profile, ref, 8, now) },
+		"reseñas":        func() error { _, err := s.reviews.GetReviews(profile, ref, now); return err },
+		"reseñar":        func() error { _, err := s.reviews.WriteReview(profile, ref, "Buena", false, now); return err },
+		"Mi Lista":       func() error { return s.playback.AddFavorite(profile, ref, now) },
+		"historial":      func() error { _, err := s.playback.AddToHistory(profile, ref, "", now); return err },
+		"reproducir":     func() error { _, err := s.playback.SelectRendition(profile, movie.ID, "", "", now); return err },
+	}
+	for name, call := range calls {
+		if got := errorCode(call()); got != CodeNotLicensed {
+			t.Errorf("%s: %q, se esperaba %q", name, got, CodeNotLicensed)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// internal/services/fixture_test.go
+package services
+
+import (
+	"SDGEStreaming/internal/db"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/payments"
+	"SDGEStreaming/internal/repositories"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// testServices son los servicios conectados como en cmd/sdge, sobre una base
+// de datos nueva con las migraciones aplicadas.
+type testServices struct {
+	content  *ContentService
+	parental *ParentalService
+	playback *PlaybackService
+	profiles *ProfileService
+	reviews  *ReviewService
+	streams  *StreamService
+	users    *UserService
+
+	userRepo repositories.UserRepo
+	admin    *models.User
+}
+
+// newTestDB abre una base de datos vacía en un directorio temporal.
+func newTestDB(t *testing.T) {
+	t.Helper()
+	if err := db.InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+	newTestDB(t)
+
+	userRepo := repositories.NewUserRepo()
+	contentRepo := repositories.NewContentRepo()
+	subscriptionRepo := repositories.NewSubscriptionRepo()
+	profileRepo := repositories.NewProfileRepo()
+	ratingRepo := repositories.NewRatingRepo()
+	seriesRepo := repositories.NewSeriesRepo()
+
+	parental := NewParentalService(repositories.NewParentalRepo(), profileRepo, userRepo, contentRepo, ratingRepo)
+	content := NewContentService(contentRepo, ratingRepo, repositories.NewUserRatingRepo(), userRepo, seriesRepo, repositories.NewLibraryRepo(), parental)
+	s := &testServices{
+		content:  content,
+		parental: parental,
+		playback: NewPlaybackService(repositories.NewPlaybackHistoryRepo(), repositories.NewFavoriteRepo(), contentRepo, userRepo, subscriptionRepo, seriesRepo, parental),
+		profiles: NewProfileService(profileRepo, userRepo, subscriptionRepo, repositories.NewAuthSessionRepo(), content),
+		reviews:  NewReviewService(repositories.NewReviewRepo(), contentRepo, userRepo, parental),
+		streams:  NewStreamService(repositories.NewStreamSessionRepo(), userRepo, subscriptionRepo, contentRepo, parental),
+		users:    NewUserService(userRepo, subscriptionRepo),
+		userRepo: userRepo,
+	}
+	s.admin = s.newUser(t, models.RoleSuperAdmin, models.DefaultCountry)
+	return s
+}
+
+// newUser crea una cuenta adulta en el plan Premium con el rol y la región
+// indicados.
+func (s *testServices) newUser(t *testing.T, role, country string) *models.User {
+	t.Helper()
+	n, err := s.userRepo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	user := &models.User{
+		Name:         "Prueba",
+		Email:        fmt.Sprintf("prueba%d@sdge.com", len(n)+1),
+		Age:          30,
+		PlanID:       3,
+		AgeRating:    models.AgeRatingAdult,
+		Country:      country,
+		Role:         role,
+		PasswordHash: "x",
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		t.Fatalf("crear usuario: %v", err)
+	}
+	return user
+}
+
+// mainProfile devuelve el perfil principal de la cuenta.
+func (s *testServices) mainProfile(t *testing.T, user *models.User) *models.Profile {
+	t.Helper()
+	profiles, err := s.profiles.GetProfiles(user.ID)
+	if err != nil || len(profiles) == 0 {
+		t.Fatalf("perfiles de la cuenta %d: %v", user.ID, err)
+	}
+	return &profiles[0]
+}
+
+// newMovie publica una película con la clasificación MPAA indicada.
+func (s *testServices) newMovie(t *testing.T, title, ageRating string) *models.AudiovisualContent {
+	t.Helper()
+	movie, err := s.content.CreateAudiovisual(s.admin, title, "movie", "Drama", 120, ageRating, "", "Sinopsis", 2020, "Directora", nil)
+	if err != nil {
+		t.Fatalf("CreateAudiovisual: %v", err)
+	}
+	return movie
+}
+
+// newRecordingGateway es la pasarela simulada con las tarjetas del README.
+func newRecordingGateway() *recordingGateway {
+	return &recordingGateway{Gateway: payments.NewSimulator(payments.DefaultSimulatorConfig())}
+}
@@ -0,0 +1,179 @@
+// internal/services/library_service.go
+// Artistas, álbumes y programas de podcast del catálogo de audio.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"time"
+)
+
+// LibraryService arma la discografía de los artistas, las pistas de cada
+// álbum y los episodios de cada podcast, con el mismo filtro de perfil y
+// región que el resto del catálogo.
+type LibraryService struct {
+	libraryRepo    repositories.LibraryRepo
+	contentRepo    repositories.ContentRepo
+	contentService *ContentService
+}
+
+// NewLibraryService crea una nueva instancia del servicio.
+func NewLibraryService(libraryRepo repositories.LibraryRepo, contentRepo repositories.ContentRepo, contentService *ContentService) *LibraryService {
+	return &LibraryService{
+		libraryRepo:    libraryRepo,
+		contentRepo:    contentRepo,
+		contentService: contentService,
+	}
+}
+
+// --- ARTISTAS ---
+
+func (s *LibraryService) GetArtists() ([]models.Artist, error) {
+	artists, err := s.libraryRepo.FindArtists()
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return artists, nil
+}
+
+// GetArtist devuelve el artista con su discografía: cada álbum con sus
+// pistas en orden y, aparte, las pistas sueltas. Solo incluye lo que el
+// perfil puede ver.
+func (s *LibraryService) GetArtist(profile *models.Profile, id int, now time.Time) (*models.Artist, error) {
+	artist, err := s.findArtist(id)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := s.contentService.profileFilter(profile, models.ContentTypeAudio, now)
+	if err != nil {
+		return nil, err
+	}
+
+	albums, err := s.libraryRepo.FindAlbumsByArtist(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	tracks, err := s.contentRepo.FindArtistTracks(id, filter)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	index := make(map[int]int, len(albums))
+	for i, album := range albums {
+		index[album.ID] = i
+	}
+	for _, track := range tracks {
+		i, ok := 0, false
+		if track.AlbumID != nil {
+			i, ok = index[*track.AlbumID]
+		}
+		if !ok {
+			artist.Singles = append(artist.Singles, track)
+			continue
+		}
+		albums[i].Tracks = append(albums[i].Tracks, track)
+	}
+
+	// Los álbumes que el perfil no puede escuchar no aparecen.
+	for _, album := range albums {
+		if len(album.Tracks) > 0 {
+			artist.Albums = append(artist.Albums, album)
+		}
+	}
+	return artist, nil
+}
+
+// GetAlbum devuelve el álbum con sus pistas en orden.
+func (s *LibraryService) GetAlbum(profile *models.Profile, id int, now time.Time) (*models.Album, error) {
+	album, err := s.libraryRepo.FindAlbumByID(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if album == nil {
+		return nil, apperrors.ErrNotFound("álbum")
+	}
+	filter, err := s.contentService.profileFilter(profile, models.ContentTypeAudio, now)
+	if err != nil {
+		return nil, err
+	}
+
+	album.Tracks, err = s.contentRepo.FindAlbumTracks(id, filter)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return album, nil
+}
+
+// --- SEGUIDOS ---
+
+func (s *LibraryService) FollowArtist(profileID, artistID int, now time.Time) error {
+	if _, err := s.findArtist(artistID); err != nil {
+		return err
+	}
+	if err := s.libraryRepo.Follow(profileID, artistID, now); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+func (s *LibraryService) UnfollowArtist(profileID, artistID int) error {
+	if _, err := s.findArtist(artistID); err != nil {
+		return err
+	}
+	if err := s.libraryRepo.Unfollow(profileID, artistID); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+func (s *LibraryService) GetFollowedArtists(profileID int) ([]models.Artist, error) {
+	artists, err := s.libraryRepo.FindFollowed(profileID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return artists, nil
+}
+
+func (s *LibraryService) findArtist(id int) (*models.Artist, error) {
+	artist, err := s.libraryRepo.FindArtistByID(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if artist == nil {
+		return nil, apperrors.ErrNotFound("artista")
+	}
+	return artist, nil
+}
+
+// --- PODCASTS ---
+
+func (s *LibraryService) GetShows() ([]models.Show, error) {
+	shows, err := s.libraryRepo.FindShows()
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return shows, nil
+}
+
+// GetShow devuelve el programa con sus episodios, del más reciente al más
+// antiguo.
+func (s *LibraryService) GetShow(profile *models.Profile, id int, now time.Time) (*models.Show, error) {
+	show, err := s.libraryRepo.FindShowByID(id)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if show == nil {
+		return nil, apperrors.ErrNotFound("programa")
+	}
+	filter, err := s.contentService.profileFilter(profile, models.ContentTypeAudio, now)
+	if err != nil {
+		return nil, err
+	}
+
+	show.Episodes, err = s.contentRepo.FindShowEpisodes(id, filter)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return show, nil
+}
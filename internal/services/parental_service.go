@@ -0,0 +1,316 @@
+// internal/services/parental_service.go
+// Control parental por perfil: techos de madurez, géneros y títulos
+// bloqueados, y el PIN de la cuenta que protege estos ajustes.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"SDGEStreaming/internal/security"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// CodePINRequired indica que el contenido supera el techo de madurez del
+// perfil y solo se puede reproducir con el PIN parental.
+const CodePINRequired = "PIN_REQUIRED"
+
+// CodePINLocked indica que el PIN parental está bloqueado por demasiados
+// intentos incorrectos seguidos.
+const CodePINLocked = "PIN_LOCKED"
+
+const (
+	// MaxPINAttempts es cuántos PIN incorrectos seguidos bloquean el PIN.
+	MaxPINAttempts = 5
+	// PINLockout es cuánto dura el bloqueo.
+	PINLockout = 15 * time.Minute
+)
+
+// ParentalService administra el control parental y lo aplica al catálogo y
+// a la reproducción.
+type ParentalService struct {
+	parentalRepo repositories.ParentalRepo
+	profileRepo  repositories.ProfileRepo
+	userRepo     repositories.UserRepo
+	contentRepo  repositories.ContentRepo
+	ratingRepo   repositories.RatingRepo
+}
+
+// NewParentalService crea una nueva instancia del servicio.
+func NewParentalService(parentalRepo repositories.ParentalRepo, profileRepo repositories.ProfileRepo, userRepo repositories.UserRepo, contentRepo repositories.ContentRepo, ratingRepo repositories.RatingRepo) *ParentalService {
+	return &ParentalService{
+		parentalRepo: parentalRepo,
+		profileRepo:  profileRepo,
+		userRepo:     userRepo,
+		contentRepo:  contentRepo,
+		ratingRepo:   ratingRepo,
+	}
+}
+
+// SetPIN crea o cambia el PIN parental de la cuenta (4 dígitos). Pide la
+// contraseña de la cuenta para que no lo pueda cambiar cualquiera que tenga
+// un perfil abierto.
+func (s *ParentalService) SetPIN(userID int, password, pin string, now time.Time) error {
+	if !isValidPIN(pin) {
+		return apperrors.New("INVALID_INPUT", "el PIN debe tener 4 dígitos")
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return apperrors.ErrNotFound("usuario")
+	}
+	if !security.CheckPasswordHash(password, user.PasswordHash) {
+		return apperrors.New("UNAUTHORIZED", "contraseña incorrecta")
+	}
+
+	hash, err := security.HashPassword(pin)
+	if err != nil {
+		return apperrors.ErrInternal(err)
+	}
+	if err := s.parentalRepo.SavePINHash(userID, hash, now); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// HasPIN indica si la cuenta ya tiene PIN parental.
+func (s *ParentalService) HasPIN(userID int) (bool, error) {
+	p, err := s.parentalRepo.FindPIN(userID)
+	if err != nil {
+		return false, apperrors.ErrDatabase(err)
+	}
+	return p != nil, nil
+}
+
+// VerifyPIN comprueba el PIN parental de la cuenta. Tras MaxPINAttempts
+// intentos incorrectos seguidos el PIN queda bloqueado durante PINLockout,
+// aunque después se ingrese el correcto.
+func (s *ParentalService) VerifyPIN(userID int, pin string, now time.Time) error {
+	p, err := s.parentalRepo.FindPIN(userID)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if p == nil {
+		return apperrors.New("FORBIDDEN", "la cuenta no tiene PIN parental; configúrelo primero")
+	}
+	if p.Locked(now) {
+		return pinLockedError(p, now)
+	}
+
+	if !security.CheckPasswordHash(pin, p.Hash) {
+		p, err = s.parentalRepo.RecordPINFailure(userID, MaxPINAttempts, now.Add(PINLockout))
+		if err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+		if p.Locked(now) {
+			return pinLockedError(p, now)
+		}
+		return apperrors.New("FORBIDDEN", fmt.Sprintf("PIN parental incorrecto; intentos restantes: %d", MaxPINAttempts-p.FailedAttempts))
+	}
+	if p.FailedAttempts > 0 {
+		if err := s.parentalRepo.ResetPINFailures(userID); err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+	}
+	return nil
+}
+
+// pinLockedError informa cuánto falta para que se pueda volver a probar el
+// PIN.
+func pinLockedError(p *models.ParentalPIN, now time.Time) error {
+	minutes := int(math.Ceil(p.LockedUntil.Sub(now).Minutes()))
+	return apperrors.New(CodePINLocked, fmt.Sprintf("demasiados intentos con un PIN parental incorrecto; vuelva a intentarlo en %d minutos", minutes))
+}
+
+// GetControls devuelve el control parental de un perfil de la cuenta.
+func (s *ParentalService) GetControls(userID, profileID int) (*models.ParentalControls, error) {
+	if _, err := s.ownProfile(userID, profileID); err != nil {
+		return nil, err
+	}
+	controls, err := s.parentalRepo.FindByProfileID(profileID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return controls, nil
+}
+
+// UpdateControls reemplaza el control parental de un perfil. Requiere el PIN
+// de la cuenta. El nivel máximo no puede superar el que permite la
+// clasificación del titular.
+func (s *ParentalService) UpdateControls(userID int, pin string, controls *models.ParentalControls, now time.Time) error {
+	if _, err := s.ownProfile(userID, controls.ProfileID); err != nil {
+		return err
+	}
+	if err := s.VerifyPIN(userID, pin, now); err != nil {
+		return err
+	}
+
+	owner, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return apperrors.ErrNotFound("usuario")
+	}
+	if level := controls.MaxLevel; level != nil {
+		if *level < 0 || *level > models.MaxMaturityLevel {
+			return apperrors.New("INVALID_INPUT", fmt.Sprintf("el nivel máximo debe estar entre 0 y %d", models.MaxMaturityLevel))
+		}
+		if limit := models.ProfileMaxLevel(owner.AgeRating); *level > limit {
+			return apperrors.New("FORBIDDEN", fmt.Sprintf("el nivel máximo no puede superar %d (%s), el del titular de la cuenta", limit, models.MaturityLevels[limit]))
+		}
+	}
+
+	genres := make([]string, 0, len(controls.BlockedGenres))
+	for _, genre := range controls.BlockedGenres {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			genres = append(genres, genre)
+		}
+	}
+	controls.BlockedGenres = genres
+
+	for _, b := range controls.BlockedContent {
+		if _, _, err := s.contentInfo(b.ContentID, b.ContentType); err != nil {
+			return err
+		}
+	}
+
+	if err := s.parentalRepo.Save(controls); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// Filter arma el filtro del catálogo indicado para el perfil: su nivel
+// máximo de madurez (el propio o el de su clasificación) y sus bloqueos.
+func (s *ParentalService) Filter(profile *models.Profile, contentType string) (models.ContentFilter, error) {
+	controls, err := s.parentalRepo.FindByProfileID(profile.ID)
+	if err != nil {
+		return models.ContentFilter{}, apperrors.ErrDatabase(err)
+	}
+
+	filter := models.ContentFilter{
+		MaxLevel:      models.ProfileMaxLevel(profile.AgeRating),
+		BlockedGenres: controls.BlockedGenres,
+	}
+	if controls.MaxLevel != nil {
+		filter.MaxLevel = *controls.MaxLevel
+	}
+	for _, b := range controls.BlockedContent {
+		if b.ContentType == contentType {
+			filter.BlockedIDs = append(filter.BlockedIDs, b.ContentID)
+		}
+	}
+	return filter, nil
+}
+
+// CheckPlayback decide si el perfil puede reproducir un contenido. Los
+// títulos y géneros bloqueados no se reproducen nunca; lo que supera el nivel
+// máximo del perfil solo con el PIN parental de la cuenta, y nunca por encima
+// de la clasificación del titular.
+func (s *ParentalService) CheckPlayback(profile *models.Profile, contentID int, contentType, pin string, now time.Time) error {
+	rating, filter, err := s.checkBlocked(profile, contentID, contentType)
+	if err != nil {
+		return err
+	}
+	if rating.Level <= filter.MaxLevel {
+		return nil
+	}
+
+	// El PIN no habilita nada por encima de la clasificación del titular.
+	owner, err := s.userRepo.FindByID(profile.UserID)
+	if err != nil {
+		return apperrors.ErrNotFound("usuario")
+	}
+	if rating.Level > models.ProfileMaxLevel(owner.AgeRating) {
+		return apperrors.New("FORBIDDEN", fmt.Sprintf("este contenido (%s) no está disponible para la cuenta", rating.Code))
+	}
+	if pin == "" {
+		return apperrors.New(CodePINRequired, "este contenido supera el límite de madurez del perfil; ingrese el PIN parental para verlo")
+	}
+	return s.VerifyPIN(profile.UserID, pin, now)
+}
+
+// CheckVisible decide si el perfil puede ver la ficha de un contenido, sus
+// calificaciones y reseñas, o guardarlo y calificarlo: lo mismo que el filtro
+// del catálogo, sin PIN. Lo que supera el nivel máximo del perfil solo se
+// abre al reproducirlo con el PIN.
+func (s *ParentalService) CheckVisible(profile *models.Profile, contentID int, contentType string) error {
+	rating, filter, err := s.checkBlocked(profile, contentID, contentType)
+	if err != nil {
+		return err
+	}
+	if rating.Level > filter.MaxLevel {
+		return apperrors.New("FORBIDDEN", fmt.Sprintf("este contenido (%s) supera el límite de madurez del perfil", rating.Code))
+	}
+	return nil
+}
+
+// checkBlocked rechaza el contenido si el control parental del perfil
+// bloquea el título o su género, y devuelve su clasificación y el filtro
+// del perfil.
+func (s *ParentalService) checkBlocked(profile *models.Profile, contentID int, contentType string) (*models.MaturityRating, models.ContentFilter, error) {
+	genre, rating, err := s.contentInfo(contentID, contentType)
+	if err != nil {
+		return nil, models.ContentFilter{}, err
+	}
+	filter, err := s.Filter(profile, contentType)
+	if err != nil {
+		return nil, models.ContentFilter{}, err
+	}
+
+	for _, id := range filter.BlockedIDs {
+		if id == contentID {
+			return nil, models.ContentFilter{}, apperrors.New("FORBIDDEN", "el control parental de este perfil bloquea este título")
+		}
+	}
+	for _, blocked := range filter.BlockedGenres {
+		if strings.EqualFold(blocked, genre) {
+			return nil, models.ContentFilter{}, apperrors.New("FORBIDDEN", fmt.Sprintf("el control parental de este perfil bloquea el género %s", genre))
+		}
+	}
+	return rating, filter, nil
+}
+
+// ownProfile verifica que el perfil sea de la cuenta.
+func (s *ParentalService) ownProfile(userID, profileID int) (*models.Profile, error) {
+	profile, err := s.profileRepo.FindByID(profileID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if profile == nil || profile.UserID != userID {
+		return nil, apperrors.ErrNotFound("perfil")
+	}
+	return profile, nil
+}
+
+// contentInfo devuelve el género y la clasificación de un contenido. Una
+// clasificación que no está en el registro cuenta como de adultos.
+func (s *ParentalService) contentInfo(contentID int, contentType string) (string, *models.MaturityRating, error) {
+	item, err := findItem(s.contentRepo, models.ContentRef{ContentID: contentID, ContentType: contentType})
+	if err != nil {
+		return "", nil, err
+	}
+	genre, system, code := item.Genre, item.RatingSystem, item.AgeRating
+
+	rating, err := s.ratingRepo.FindRating(system, code)
+	if err != nil {
+		return "", nil, apperrors.ErrDatabase(err)
+	}
+	if rating == nil {
+		rating = &models.MaturityRating{SystemCode: system, Code: code, Level: models.MaxMaturityLevel}
+	}
+	return genre, rating, nil
+}
+
+// isValidPIN acepta exactamente 4 dígitos.
+func isValidPIN(pin string) bool {
+	if len(pin) != 4 {
+		return false
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
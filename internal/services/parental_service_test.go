@@ -0,0 +1,194 @@
+// internal/services/parental_service_test.go
+package services
+
+import (
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/security"
+	"testing"
+	"time"
+)
+
+const testPIN = "1234"
+
+// setPIN guarda el PIN de la cuenta sin pasar por la contraseña, que en las
+// cuentas de prueba no es un hash válido.
+func (s *testServices) setPIN(t *testing.T, user *models.User) {
+	t.Helper()
+	hash, err := security.HashPassword(testPIN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.parental.parentalRepo.SavePINHash(user.ID, hash, time.Now()); err != nil {
+		t.Fatalf("SavePINHash: %v", err)
+	}
+}
+
+func (s *testServices) newProfile(t *testing.T, user *models.User, ageRating string) *models.Profile {
+	t.Helper()
+	profile, err := s.profiles.CreateProfile(user.ID, "Perfil "+ageRating, ageRating)
+	if err != nil {
+		t.Fatalf("CreateProfile(%s): %v", ageRating, err)
+	}
+	return profile
+}
+
+func TestCheckPlayback(t *testing.T) {
+	level := func(n int) *int { return &n }
+	tests := []struct {
+		name        string
+		owner       string // clasificación del titular
+		profile     string
+		movie       string // clasificación MPAA
+		controls    models.ParentalControls
+		pin         string
+		want        string
+		wantVisible string // lo que da CheckVisible, que nunca pide PIN
+	}{
+		{name: "dentro del límite del perfil", owner: models.AgeRatingAdult, profile: models.AgeRatingChild, movie: "G"},
+		{name: "por encima del límite pide PIN", owner: models.AgeRatingAdult, profile: models.AgeRatingChild, movie: "PG-13", want: CodePINRequired, wantVisible: "FORBIDDEN"},
+		{name: "el PIN habilita lo que supera el límite", owner: models.AgeRatingAdult, profile: models.AgeRatingChild, movie: "PG-13", pin: testPIN, wantVisible: "FORBIDDEN"},
+		{name: "un PIN incorrecto no habilita nada", owner: models.AgeRatingAdult, profile: models.AgeRatingChild, movie: "PG-13", pin: "0000", want: "FORBIDDEN", wantVisible: "FORBIDDEN"},
+		{name: "adolescente hasta PG-13", owner: models.AgeRatingAdult, profile: models.AgeRatingTeen, movie: "PG-13"},
+		{name: "el nivel propio reemplaza al de la clasificación", owner: models.AgeRatingAdult, profile: models.AgeRatingTeen, movie: "R", controls: models.ParentalControls{MaxLevel: level(3)}},
+		{name: "un adulto con nivel bajo pide PIN", owner: models.AgeRatingAdult, profile: models.AgeRatingAdult, movie: "PG", controls: models.ParentalControls{MaxLevel: level(0)}, want: CodePINRequired, wantVisible: "FORBIDDEN"},
+		{name: "el PIN no supera la clasificación del titular", owner: models.AgeRatingTeen, profile: models.AgeRatingTeen, movie: "R", pin: testPIN, want: "FORBIDDEN", wantVisible: "FORBIDDEN"},
+		{name: "un título bloqueado no se ve ni con PIN", owner: models.AgeRatingAdult, profile: models.AgeRatingAdult, movie: "G", controls: models.ParentalControls{BlockedContent: []models.BlockedContent{{ContentType: models.ContentTypeAudiovisual}}}, pin: testPIN, want: "FORBIDDEN", wantVisible: "FORBIDDEN"},
+		{name: "un género bloqueado no distingue mayúsculas", owner: models.AgeRatingAdult, profile: models.AgeRatingAdult, movie: "G", controls: models.ParentalControls{BlockedGenres: []string{"drama"}}, pin: testPIN, want: "FORBIDDEN", wantVisible: "FORBIDDEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			owner := s.newUser(t, models.RoleViewer, models.DefaultCountry)
+			if tt.owner != models.AgeRatingAdult {
+				owner.AgeRating = tt.owner
+				if err := s.userRepo.Update(owner); err != nil {
+					t.Fatalf("Update: %v", err)
+				}
+			}
+			s.setPIN(t, owner)
+			profile := s.newProfile(t, owner, tt.profile)
+			movie := s.newMovie(t, "Película", tt.movie)
+
+			controls := tt.controls
+			controls.ProfileID = profile.ID
+			for i := range controls.BlockedContent {
+				controls.BlockedContent[i].ContentID = movie.ID
+			}
+			if err := s.parental.parentalRepo.Save(&controls); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			now := time.Now()
+			if got := errorCode(s.parental.CheckPlayback(profile, movie.ID, models.ContentTypeAudiovisual, tt.pin, now)); got != tt.want {
+				t.Errorf("CheckPlayback = %q, se esperaba %q", got, tt.want)
+			}
+			if got := errorCode(s.parental.CheckVisible(profile, movie.ID, models.ContentTypeAudiovisual)); got != tt.wantVisible {
+				t.Errorf("CheckVisible = %q, se esperaba %q", got, tt.wantVisible)
+			}
+		})
+	}
+}
+
+func TestCatalogHidesWhatParentalControlsBlock(t *testing.T) {
+	s := newTestServices(t)
+	owner := s.newUser(t, models.RoleViewer, models.DefaultCountry)
+	child := s.newProfile(t, owner, models.AgeRatingChild)
+	s.newMovie(t, "Para todos", "G")
+	s.newMovie(t, "Para adultos", "R")
+
+	movies, err := s.content.GetAllAudiovisualForProfile(child, time.Now())
+	if err != nil {
+		t.Fatalf("GetAllAudiovisualForProfile: %v", err)
+	}
+	if len(movies) != 1 || movies[0].Title != "Para todos" {
+		t.Errorf("catálogo del perfil infantil = %v, se esperaba solo \"Para todos\"", movies)
+	}
+}
+
+func TestVerifyPINLockout(t *testing.T) {
+	type attempt struct {
+		pin   string
+		after time.Duration // desde el primer intento
+		want  string
+	}
+	wrong := func(after time.Duration) attempt { return attempt{pin: "0000", after: after, want: "FORBIDDEN"} }
+
+	tests := []struct {
+		name     string
+		attempts []attempt
+	}{
+		{
+			name: "el último intento bloquea el PIN",
+			attempts: []attempt{
+				wrong(0), wrong(0), wrong(0), wrong(0),
+				{pin: "0000", want: CodePINLocked},
+			},
+		},
+		{
+			name: "el PIN correcto no entra mientras está bloqueado",
+			attempts: []attempt{
+				wrong(0), wrong(0), wrong(0), wrong(0), {pin: "0000", want: CodePINLocked},
+				{pin: testPIN, after: PINLockout - time.Second, want: CodePINLocked},
+			},
+		},
+		{
+			name: "el bloqueo vence",
+			attempts: []attempt{
+				wrong(0), wrong(0), wrong(0), wrong(0), {pin: "0000", want: CodePINLocked},
+				{pin: testPIN, after: PINLockout},
+			},
+		},
+		{
+			name: "el bloqueo vence y vuelve a contar desde cero",
+			attempts: []attempt{
+				wrong(0), wrong(0), wrong(0), wrong(0), {pin: "0000", want: CodePINLocked},
+				wrong(PINLockout), wrong(PINLockout), wrong(PINLockout), wrong(PINLockout),
+				{pin: "0000", after: PINLockout, want: CodePINLocked},
+			},
+		},
+		{
+			name: "un PIN correcto reinicia la cuenta",
+			attempts: []attempt{
+				wrong(0), wrong(0), wrong(0), wrong(0),
+				{pin: testPIN},
+				wrong(0), wrong(0), wrong(0), wrong(0),
+				{pin: testPIN},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			user := s.newUser(t, models.RoleViewer, models.DefaultCountry)
+			s.setPIN(t, user)
+
+			start := time.Now()
+			for i, a := range tt.attempts {
+				if got := errorCode(s.parental.VerifyPIN(user.ID, a.pin, start.Add(a.after))); got != a.want {
+					t.Fatalf("intento %d: %q, se esperaba %q", i+1, got, a.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetPINClearsLockout(t *testing.T) {
+	s := newTestServices(t)
+	user := s.newUser(t, models.RoleViewer, models.DefaultCountry)
+	s.setPIN(t, user)
+
+	now := time.Now()
+	for range MaxPINAttempts {
+		s.parental.VerifyPIN(user.ID, "0000", now)
+	}
+	if got := errorCode(s.parental.VerifyPIN(user.ID, testPIN, now)); got != CodePINLocked {
+		t.Fatalf("VerifyPIN = %q, se esperaba %q", got, CodePINLocked)
+	}
+
+	s.setPIN(t, user)
+	if err := s.parental.VerifyPIN(user.ID, testPIN, now); err != nil {
+		t.Errorf("VerifyPIN tras cambiar el PIN: %v", err)
+	}
+}
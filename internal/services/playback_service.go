@@ -3,9 +3,12 @@
 package services
 
 import (
+	apperrors "SDGEStreaming/internal/errors"
 	"SDGEStreaming/internal/models"
 	"SDGEStreaming/internal/repositories"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // PlaybackService encapsula la lógica de negocio para la reproducción.
@@ -13,185 +16,372 @@ type PlaybackService struct {
 	historyRepo  repositories.PlaybackHistoryRepo
 	favoriteRepo repositories.FavoriteRepo
 	contentRepo  repositories.ContentRepo
+	userRepo     repositories.UserRepo
+	subRepo      repositories.SubscriptionRepo
+	seriesRepo   repositories.SeriesRepo
+	parental     *ParentalService
 }
 
 // NewPlaybackService crea una nueva instancia del servicio.
-func NewPlaybackService(historyRepo repositories.PlaybackHistoryRepo, favoriteRepo repositories.FavoriteRepo, contentRepo repositories.ContentRepo) *PlaybackService {
+func NewPlaybackService(historyRepo repositories.PlaybackHistoryRepo, favoriteRepo repositories.FavoriteRepo, contentRepo repositories.ContentRepo, userRepo repositories.UserRepo, subRepo repositories.SubscriptionRepo, seriesRepo repositories.SeriesRepo, parental *ParentalService) *PlaybackService {
 	return &PlaybackService{
 		historyRepo:  historyRepo,
 		favoriteRepo: favoriteRepo,
 		contentRepo:  contentRepo,
+		userRepo:     userRepo,
+		subRepo:      subRepo,
+		seriesRepo:   seriesRepo,
+		parental:     parental,
 	}
 }
 
-// AddToHistory agrega una entrada al historial de reproducción.
-func (s *PlaybackService) AddToHistory(userID, contentID int, contentType string) error {
-	if contentType != "audio" && contentType != "audiovisual" {
-		return fmt.Errorf("tipo de contenido inválido")
+// SelectRendition elige la versión de un contenido audiovisual a reproducir:
+// la de mayor calidad que permita el plan del usuario o, si se pide una
+// calidad, la mejor que no la supere. Pedir una calidad por encima del plan
+// (p. ej. 4K en el plan Estándar) se rechaza. El control parental del perfil
+// se aplica antes, junto con la licencia del contenido en la región de la
+// cuenta en now; pin es el PIN parental si el usuario lo ingresó.
+func (s *PlaybackService) SelectRendition(profile *models.Profile, contentID int, requested, pin string, now time.Time) (*models.Rendition, error) {
+	requested = strings.ToUpper(strings.TrimSpace(requested))
+	if requested != "" && models.QualityRank(requested) == 0 {
+		return nil, apperrors.New("INVALID_INPUT", fmt.Sprintf("calidad '%s' no válida (SD, HD o 4K)", requested))
 	}
 
-	// Verificar que el contenido exista
-	if contentType == "audiovisual" {
-		_, err := s.contentRepo.FindAudiovisualByID(contentID)
-		if err != nil {
-			return fmt.Errorf("contenido audiovisual no encontrado")
+	if _, err := s.contentRepo.FindAudiovisualByID(contentID); err != nil {
+		return nil, apperrors.ErrNotFound("contenido audiovisual")
+	}
+	user, err := s.userRepo.FindByID(profile.UserID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound("usuario")
+	}
+	if err := checkLicense(s.contentRepo, contentID, models.ContentTypeAudiovisual, user.Country, now); err != nil {
+		return nil, err
+	}
+	if err := s.parental.CheckPlayback(profile, contentID, models.ContentTypeAudiovisual, pin, now); err != nil {
+		return nil, err
+	}
+	plan, err := s.subRepo.GetPlanByID(user.PlanID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if plan == nil {
+		return nil, apperrors.ErrNotFound("plan")
+	}
+
+	ceiling := models.QualityRank(plan.MaxQuality)
+	if requested != "" {
+		if models.QualityRank(requested) > ceiling {
+			return nil, apperrors.New("FORBIDDEN", fmt.Sprintf("su plan %s permite hasta calidad %s", plan.Name, plan.MaxQuality))
 		}
-	} else {
-		_, err := s.contentRepo.FindAudioByID(contentID)
-		if err != nil {
-			return fmt.Errorf("contenido de audio no encontrado")
+		ceiling = models.QualityRank(requested)
+	}
+
+	renditions, err := s.contentRepo.FindRenditions(contentID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	// Vienen ordenadas de menor a mayor calidad: la última que entra es la mejor.
+	var best *models.Rendition
+	for i := range renditions {
+		if models.QualityRank(renditions[i].Quality) <= ceiling {
+			best = &renditions[i]
 		}
 	}
+	if best == nil {
+		return nil, apperrors.New("NOT_FOUND", "el contenido no está disponible en una calidad permitida por su plan")
+	}
+	return best, nil
+}
+
+// AddToHistory agrega una entrada al historial de reproducción del perfil y
+// la devuelve: cada entrada es una reproducción, a la que después se le
+// informa el progreso con ReportProgress. Las series se registran por
+// episodio con AddEpisodeToHistory. Se aplican la licencia y el control
+// parental que al reproducir; pin es el PIN parental si el usuario lo ingresó.
+func (s *PlaybackService) AddToHistory(profile *models.Profile, ref models.ContentRef, pin string, now time.Time) (*models.PlaybackHistory, error) {
+	item, err := findItem(s.contentRepo, ref)
+	if err != nil {
+		return nil, err
+	}
+	if item.Kind == models.TypeSeries {
+		return nil, apperrors.New("INVALID_INPUT", "para una serie indique el episodio (episode_id)")
+	}
+	if err := s.checkPlayable(profile, ref, pin, now); err != nil {
+		return nil, err
+	}
 
 	entry := &models.PlaybackHistory{
-		UserID:      userID,
-		ContentID:   contentID,
-		ContentType: contentType,
-		Progress:    0, // Se puede actualizar más tarde
+		ProfileID:  profile.ID,
+		ContentRef: ref,
+	}
+	if err := s.historyRepo.Create(entry); err != nil {
+		return nil, apperrors.ErrDatabase(err)
 	}
-	return s.historyRepo.Create(entry)
+	return entry, nil
 }
 
-// UpdateProgress actualiza el progreso de reproducción de un contenido.
-func (s *PlaybackService) UpdateProgress(userID, contentID int, contentType string, progressSeconds int) error {
-	if progressSeconds < 0 {
-		return fmt.Errorf("el progreso no puede ser negativo")
+// AddEpisodeToHistory agrega un episodio de una serie al historial del
+// perfil, con la licencia y el control parental de la serie.
+func (s *PlaybackService) AddEpisodeToHistory(profile *models.Profile, episodeID int, pin string, now time.Time) (*models.PlaybackHistory, error) {
+	episode, err := s.seriesRepo.FindEpisodeByID(episodeID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if episode == nil {
+		return nil, apperrors.ErrNotFound("episodio")
+	}
+	series := models.ContentRef{ContentID: episode.SeriesID, ContentType: models.ContentTypeAudiovisual}
+	if err := s.checkPlayable(profile, series, pin, now); err != nil {
+		return nil, err
 	}
 
-	return s.historyRepo.UpdateProgress(userID, contentID, contentType, progressSeconds)
+	entry := &models.PlaybackHistory{
+		ProfileID:  profile.ID,
+		ContentRef: series,
+		EpisodeID:  &episode.ID,
+	}
+	if err := s.historyRepo.Create(entry); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return entry, nil
 }
 
-// GetHistory obtiene el historial de reproducción de un usuario (últimas 10 entradas).
-func (s *PlaybackService) GetHistory(userID int) ([]models.PlaybackHistory, error) {
-	return s.historyRepo.FindByUserID(userID)
+// checkPlayable aplica a ref lo mismo que SelectRendition antes de elegir la
+// calidad: la licencia en la región de la cuenta en now y el control parental
+// del perfil.
+func (s *PlaybackService) checkPlayable(profile *models.Profile, ref models.ContentRef, pin string, now time.Time) error {
+	user, err := s.userRepo.FindByID(profile.UserID)
+	if err != nil {
+		return apperrors.ErrNotFound("usuario")
+	}
+	if err := checkLicense(s.contentRepo, ref.ContentID, ref.ContentType, user.Country, now); err != nil {
+		return err
+	}
+	return s.parental.CheckPlayback(profile, ref.ContentID, ref.ContentType, pin, now)
 }
 
-// AddFavorite agrega un contenido a la lista de favoritos del usuario.
-func (s *PlaybackService) AddFavorite(userID, contentID int, contentType string) error {
-	if contentType != "audio" && contentType != "audiovisual" {
-		return fmt.Errorf("tipo de contenido inválido para favoritos")
+// ReportProgress registra un latido de la reproducción historyID del perfil:
+// la posición, en segundos, a la que llegó. Actualiza solo esa entrada del
+// historial y la da por terminada si pasó models.CompletionThreshold de la
+// duración (la del episodio si es una serie); una vez terminada no deja de
+// estarlo aunque se retroceda. Devuelve el punto de reanudación del título,
+// que queda en esa posición.
+func (s *PlaybackService) ReportProgress(profileID, historyID, position int, now time.Time) (*models.ResumePoint, error) {
+	if position < 0 {
+		return nil, apperrors.New("INVALID_INPUT", "el progreso no puede ser negativo")
+	}
+
+	entry, err := s.historyRepo.FindByID(historyID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if entry == nil || entry.ProfileID != profileID {
+		return nil, apperrors.New("NOT_FOUND", "reproducción no encontrada")
+	}
+
+	duration, err := s.durationOf(entry)
+	if err != nil {
+		return nil, err
+	}
+	if duration > 0 && position > duration {
+		position = duration
+	}
+	completed := entry.Completed || models.IsCompleted(position, duration)
+
+	if err := s.historyRepo.UpdateProgress(entry.ID, position, completed); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	point := &models.ResumePoint{
+		ProfileID:  profileID,
+		ContentRef: entry.ContentRef,
+		EpisodeID:  entry.EpisodeID,
+		Position:   position,
+		Duration:   duration,
+		Completed:  completed,
+		UpdatedAt:  now,
 	}
+	if err := s.historyRepo.SaveResumePoint(point); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return point, nil
+}
 
-	// Verificar que el contenido exista (mismo código que en AddToHistory)
-	if contentType == "audiovisual" {
-		_, err := s.contentRepo.FindAudiovisualByID(contentID)
+// durationOf devuelve la duración en segundos de lo que se reprodujo en la
+// entrada: el episodio o el contenido.
+func (s *PlaybackService) durationOf(entry *models.PlaybackHistory) (int, error) {
+	if entry.EpisodeID != nil {
+		episode, err := s.seriesRepo.FindEpisodeByID(*entry.EpisodeID)
 		if err != nil {
-			return fmt.Errorf("contenido audiovisual no encontrado")
+			return 0, apperrors.ErrDatabase(err)
 		}
-	} else {
-		_, err := s.contentRepo.FindAudioByID(contentID)
-		if err != nil {
-			return fmt.Errorf("contenido de audio no encontrado")
+		if episode == nil {
+			return 0, apperrors.ErrNotFound("episodio")
 		}
+		return episode.Duration * 60, nil
 	}
 
-	favorite := &models.Favorite{
-		UserID:      userID,
-		ContentID:   contentID,
-		ContentType: contentType,
+	item, err := findItem(s.contentRepo, entry.ContentRef)
+	if err != nil {
+		return 0, err
 	}
-	return s.favoriteRepo.Create(favorite)
+	return item.Duration * 60, nil
 }
 
-// RemoveFavorite elimina un contenido de la lista de favoritos.
-func (s *PlaybackService) RemoveFavorite(userID, contentID int, contentType string) error {
-	return s.favoriteRepo.Delete(userID, contentID, contentType)
+// GetResumePoint devuelve dónde dejó el perfil el título la última vez. Si
+// Resumable() es true, conviene ofrecer seguir desde ahí.
+func (s *PlaybackService) GetResumePoint(profileID int, ref models.ContentRef) (*models.ResumePoint, error) {
+	point, err := s.historyRepo.FindResumePoint(profileID, ref)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if point == nil {
+		return nil, apperrors.ErrNotFound("punto de reanudación")
+	}
+	return point, nil
 }
 
-// GetFavorites obtiene la lista de favoritos de un usuario.
-func (s *PlaybackService) GetFavorites(userID int) ([]models.Favorite, error) {
-	return s.favoriteRepo.FindByUserID(userID)
-}
+// NextEpisode decide qué episodio de la serie le toca al perfil según su
+// punto de reanudación: el que estaba viendo si lo dejó a medias, el
+// siguiente si lo terminó o el primero si nunca vio la serie. Si ya vio el
+// último episodio devuelve NOT_FOUND.
+func (s *PlaybackService) NextEpisode(profileID, seriesID int) (*models.Episode, error) {
+	content, err := s.contentRepo.FindAudiovisualByID(seriesID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound("contenido audiovisual")
+	}
+	if content.Type != models.TypeSeries {
+		return nil, apperrors.New("INVALID_INPUT", fmt.Sprintf("'%s' no es una serie", content.Title))
+	}
+
+	point, err := s.historyRepo.FindResumePoint(profileID, content.Ref())
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	seasonNumber, episodeNumber := 0, 0
+	watched := point != nil && point.EpisodeID != nil
+	if watched {
+		episode, err := s.seriesRepo.FindEpisodeByID(*point.EpisodeID)
+		if err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+		if episode != nil {
+			if !point.Completed {
+				return episode, nil
+			}
+			seasonNumber, episodeNumber = episode.SeasonNumber, episode.Number
+		}
+	}
 
-// GetContinueWatching obtiene los contenidos donde el usuario dejó de ver/escuchar.
-// Devuelve los últimos 5 elementos con progreso > 0.
-func (s *PlaybackService) GetContinueWatching(userID int) ([]models.PlaybackHistory, error) {
-	return s.historyRepo.FindContinueWatching(userID)
+	next, err := s.seriesRepo.FindNextEpisode(seriesID, seasonNumber, episodeNumber)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if next == nil {
+		if !watched {
+			return nil, apperrors.New("NOT_FOUND", "la serie todavía no tiene episodios")
+		}
+		return nil, apperrors.New("NOT_FOUND", "ya vio todos los episodios de la serie")
+	}
+	return next, nil
 }
 
-// GetRecommendations genera recomendaciones simples basadas en el género de los favoritos.
-// Este es un ejemplo básico; en un sistema real se usaría un algoritmo más complejo.
-func (s *PlaybackService) GetRecommendations(userID int) ([]interface{}, error) {
-	favorites, err := s.GetFavorites(userID)
+// GetHistory obtiene el historial de reproducción de un perfil (últimas 10
+// entradas), cada una con la ficha de su contenido.
+func (s *PlaybackService) GetHistory(profileID int) ([]models.PlaybackHistory, error) {
+	history, err := s.historyRepo.FindByProfileID(profileID)
 	if err != nil {
 		return nil, err
 	}
+	return history, s.attachHistoryItems(history)
+}
+
+// AddFavorite agrega a la lista de favoritos del perfil un contenido que
+// puede ver en now.
+func (s *PlaybackService) AddFavorite(profile *models.Profile, ref models.ContentRef, now time.Time) error {
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parental, profile, ref, now); err != nil {
+		return err
+	}
 
-	if len(favorites) == 0 {
-		// Si no hay favoritos, devolver contenido popular (los primeros 5)
-		audiovisuals, err := s.contentRepo.FindAllAudiovisual()
-if err != nil {
-    // log o devolver error
-    return nil, fmt.Errorf("no se pudo obtener contenido audiovisual: %w", err)
+	favorite := &models.Favorite{
+		ProfileID:  profile.ID,
+		ContentRef: ref,
+	}
+	return s.favoriteRepo.Create(favorite)
 }
-audios, err := s.contentRepo.FindAllAudio()
-if err != nil {
-    return nil, fmt.Errorf("no se pudo obtener contenido de audio: %w", err)
+
+// RemoveFavorite elimina un contenido de la lista de favoritos.
+func (s *PlaybackService) RemoveFavorite(profileID int, ref models.ContentRef) error {
+	return s.favoriteRepo.Delete(profileID, ref)
 }
-		var recommendations []interface{}
-		for i, av := range audiovisuals {
-			if i >= 3 {
-				break
-			}
-			recommendations = append(recommendations, av)
-		}
-		for i, a := range audios {
-			if i >= 2 {
-				break
-			}
-			recommendations = append(recommendations, a)
-		}
-		return recommendations, nil
+
+// GetFavorites obtiene la lista de favoritos de un perfil, cada uno con la
+// ficha de su contenido.
+func (s *PlaybackService) GetFavorites(profileID int) ([]models.Favorite, error) {
+	favorites, err := s.favoriteRepo.FindByProfileID(profileID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Contar géneros de los favoritos
-	genreCount := make(map[string]int)
-	for _, fav := range favorites {
-		var genre string
-		if fav.ContentType == "audiovisual" {
-			content, err := s.contentRepo.FindAudiovisualByID(fav.ContentID)
-			if err != nil {
-				continue
-			}
-			genre = content.Genre
-		} else {
-			content, err := s.contentRepo.FindAudioByID(fav.ContentID)
-			if err != nil {
-				continue
-			}
-			genre = content.Genre
+	refs := make([]models.ContentRef, len(favorites))
+	for i := range favorites {
+		refs[i] = favorites[i].ContentRef
+	}
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range favorites {
+		if item, ok := items[favorites[i].ContentRef]; ok {
+			favorites[i].Item = &item
 		}
-		genreCount[genre]++
 	}
+	return favorites, nil
+}
 
-	// Encontrar el género más popular
-	mostPopularGenre := ""
-	maxCount := 0
-	for genre, count := range genreCount {
-		if count > maxCount {
-			maxCount = count
-			mostPopularGenre = genre
-		}
+// GetContinueWatching obtiene los títulos que el perfil dejó a medias, del
+// más reciente al más antiguo, cada uno con la ficha de su contenido.
+func (s *PlaybackService) GetContinueWatching(profileID int) ([]models.ResumePoint, error) {
+	points, err := s.historyRepo.FindContinueWatching(profileID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
 	}
 
-	// Buscar contenido en ese género
-	var recommendations []interface{}
-	if mostPopularGenre != "" {
-		audiovisuals, _ := s.contentRepo.SearchAudiovisualByTitle(mostPopularGenre)
-		audios, _ := s.contentRepo.SearchAudioByTitle(mostPopularGenre)
-		for i, av := range audiovisuals {
-			if i >= 3 {
-				break
-			}
-			recommendations = append(recommendations, av)
-		}
-		for i, a := range audios {
-			if i >= 2 {
-				break
-			}
-			recommendations = append(recommendations, a)
+	refs := make([]models.ContentRef, len(points))
+	for i := range points {
+		refs[i] = points[i].ContentRef
+	}
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range points {
+		if item, ok := items[points[i].ContentRef]; ok {
+			points[i].Item = &item
 		}
 	}
+	if points == nil {
+		points = []models.ResumePoint{}
+	}
+	return points, nil
+}
 
-	return recommendations, nil
+// attachHistoryItems completa cada entrada del historial con la ficha de su
+// contenido.
+func (s *PlaybackService) attachHistoryItems(history []models.PlaybackHistory) error {
+	refs := make([]models.ContentRef, len(history))
+	for i := range history {
+		refs[i] = history[i].ContentRef
+	}
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	for i := range history {
+		if item, ok := items[history[i].ContentRef]; ok {
+			history[i].Item = &item
+		}
+	}
+	return nil
 }
@@ -0,0 +1,156 @@
+// internal/services/playback_service_test.go
+package services
+
+import (
+	"SDGEStreaming/internal/models"
+	"testing"
+	"time"
+)
+
+func TestReportProgressCompletion(t *testing.T) {
+	// newMovie dura 120 minutos: 7200 segundos, terminada desde 6480.
+	tests := []struct {
+		name          string
+		positions     []int
+		wantPosition  int
+		wantCompleted bool
+		wantErr       string
+	}{
+		{name: "a medias", positions: []int{3000}, wantPosition: 3000},
+		{name: "justo antes del umbral", positions: []int{6479}, wantPosition: 6479},
+		{name: "en el umbral", positions: []int{6480}, wantPosition: 6480, wantCompleted: true},
+		{name: "más allá del final queda en el final", positions: []int{9000}, wantPosition: 7200, wantCompleted: true},
+		{name: "retroceder no la desmarca", positions: []int{7000, 100}, wantPosition: 100, wantCompleted: true},
+		{name: "avanzar de a poco la termina", positions: []int{1000, 4000, 6500}, wantPosition: 6500, wantCompleted: true},
+		{name: "posición negativa", positions: []int{-1}, wantErr: "INVALID_INPUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+			movie := s.newMovie(t, "Matrix", "PG")
+			now := time.Now()
+			entry, err := s.playback.AddToHistory(profile, movie.Ref(), "", now)
+			if err != nil {
+				t.Fatalf("AddToHistory: %v", err)
+			}
+
+			var point *models.ResumePoint
+			for _, position := range tt.positions {
+				point, err = s.playback.ReportProgress(profile.ID, entry.ID, position, now)
+			}
+			if got := errorCode(err); got != tt.wantErr {
+				t.Fatalf("ReportProgress = %q, se esperaba %q", got, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if point.Position != tt.wantPosition || point.Completed != tt.wantCompleted {
+				t.Errorf("punto de reanudación en %d (terminada: %v), se esperaba %d (%v)", point.Position, point.Completed, tt.wantPosition, tt.wantCompleted)
+			}
+			stored, err := s.playback.historyRepo.FindByID(entry.ID)
+			if err != nil {
+				t.Fatalf("FindByID: %v", err)
+			}
+			if stored.Completed != tt.wantCompleted {
+				t.Errorf("historial terminado: %v, se esperaba %v", stored.Completed, tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func TestReportProgressOnlyTouchesItsEntry(t *testing.T) {
+	s := newTestServices(t)
+	profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+	movie := s.newMovie(t, "Matrix", "PG")
+	now := time.Now()
+
+	first, err := s.playback.AddToHistory(profile, movie.Ref(), "", now)
+	if err != nil {
+		t.Fatalf("AddToHistory: %v", err)
+	}
+	if _, err := s.playback.ReportProgress(profile.ID, first.ID, 7200, now); err != nil {
+		t.Fatalf("ReportProgress: %v", err)
+	}
+	second, err := s.playback.AddToHistory(profile, movie.Ref(), "", now)
+	if err != nil {
+		t.Fatalf("AddToHistory: %v", err)
+	}
+	point, err := s.playback.ReportProgress(profile.ID, second.ID, 600, now)
+	if err != nil {
+		t.Fatalf("ReportProgress: %v", err)
+	}
+
+	if point.Completed || point.Position != 600 {
+		t.Errorf("punto de reanudación en %d (terminada: %v), se esperaba la segunda reproducción en 600", point.Position, point.Completed)
+	}
+	stored, err := s.playback.historyRepo.FindByID(first.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if !stored.Completed {
+		t.Error("la primera reproducción dejó de estar terminada")
+	}
+
+	other := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+	if _, err := s.playback.ReportProgress(other.ID, first.ID, 10, now); errorCode(err) != "NOT_FOUND" {
+		t.Errorf("otro perfil informó el progreso: %v", err)
+	}
+}
+
+func TestEpisodeCompletionUsesEpisodeDuration(t *testing.T) {
+	s := newTestServices(t)
+	profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+	series, err := s.content.CreateAudiovisual(s.admin, "Serie", models.TypeSeries, "Drama", 120, "PG", "", "Sinopsis", 2020, "Directora", nil)
+	if err != nil {
+		t.Fatalf("CreateAudiovisual: %v", err)
+	}
+	season, err := s.content.AddSeason(s.admin, series.ID, 1, "")
+	if err != nil {
+		t.Fatalf("AddSeason: %v", err)
+	}
+	var episodes []*models.Episode
+	for n := 1; n <= 2; n++ {
+		episode, err := s.content.AddEpisode(s.admin, season.ID, n, "Episodio", 30, "")
+		if err != nil {
+			t.Fatalf("AddEpisode: %v", err)
+		}
+		episodes = append(episodes, episode)
+	}
+
+	tests := []struct {
+		name      string
+		position  int
+		completed bool
+		next      int // episodio que toca después
+	}{
+		// 30 minutos: terminado desde 1620 segundos, lejos del 90 % de la serie.
+		{name: "a medias sigue en el mismo episodio", position: 1000, next: episodes[0].ID},
+		{name: "terminado pasa al siguiente", position: 1700, completed: true, next: episodes[1].ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			entry, err := s.playback.AddEpisodeToHistory(profile, episodes[0].ID, "", now)
+			if err != nil {
+				t.Fatalf("AddEpisodeToHistory: %v", err)
+			}
+			point, err := s.playback.ReportProgress(profile.ID, entry.ID, tt.position, now)
+			if err != nil {
+				t.Fatalf("ReportProgress: %v", err)
+			}
+			if point.Completed != tt.completed || point.Duration != 1800 {
+				t.Errorf("terminado: %v de %d segundos, se esperaba %v de 1800", point.Completed, point.Duration, tt.completed)
+			}
+			next, err := s.playback.NextEpisode(profile.ID, series.ID)
+			if err != nil {
+				t.Fatalf("NextEpisode: %v", err)
+			}
+			if next.ID != tt.next {
+				t.Errorf("sigue el episodio %d, se esperaba %d", next.ID, tt.next)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// internal/services/profile_service.go
+// Perfiles del hogar dentro de una cuenta, con el límite de perfiles de cada
+// plan y el perfil elegido en cada sesión.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProfileService crea, lista, borra y elige perfiles.
+type ProfileService struct {
+	profileRepo    repositories.ProfileRepo
+	userRepo       repositories.UserRepo
+	subRepo        repositories.SubscriptionRepo
+	sessionRepo    repositories.AuthSessionRepo
+	contentService *ContentService
+}
+
+// NewProfileService crea una nueva instancia del servicio.
+func NewProfileService(profileRepo repositories.ProfileRepo, userRepo repositories.UserRepo, subRepo repositories.SubscriptionRepo, sessionRepo repositories.AuthSessionRepo, contentService *ContentService) *ProfileService {
+	return &ProfileService{
+		profileRepo:    profileRepo,
+		userRepo:       userRepo,
+		subRepo:        subRepo,
+		sessionRepo:    sessionRepo,
+		contentService: contentService,
+	}
+}
+
+// GetProfiles devuelve los perfiles de la cuenta, el principal primero. Los
+// que exceden el límite del plan actual (p. ej. tras bajar de plan) vienen
+// marcados como bloqueados.
+func (s *ProfileService) GetProfiles(userID int) ([]models.Profile, error) {
+	_, plan, err := s.accountPlan(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := s.profileRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range profiles {
+		profiles[i].Locked = i >= plan.MaxProfiles
+	}
+	return profiles, nil
+}
+
+// GetProfile devuelve un perfil de la cuenta; los de otras cuentas se
+// reportan como inexistentes.
+func (s *ProfileService) GetProfile(userID, profileID int) (*models.Profile, error) {
+	profiles, err := s.GetProfiles(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if profiles[i].ID == profileID {
+			return &profiles[i], nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("perfil")
+}
+
+// CreateProfile agrega un perfil a la cuenta si el plan lo permite. Un perfil
+// no puede tener una clasificación mayor que la del titular.
+func (s *ProfileService) CreateProfile(userID int, name, ageRating string) (*models.Profile, error) {
+	name = strings.TrimSpace(name)
+	if !utils.IsValidName(name) {
+		return nil, apperrors.New("INVALID_INPUT", "nombre de perfil inválido")
+	}
+	if models.AgeRatingRank(ageRating) == 0 {
+		return nil, apperrors.New("INVALID_INPUT", fmt.Sprintf("clasificación '%s' no válida (%s)",
+			ageRating, strings.Join(models.ProfileAgeRatings(), ", ")))
+	}
+
+	user, plan, err := s.accountPlan(userID)
+	if err != nil {
+		return nil, err
+	}
+	if models.AgeRatingRank(ageRating) > models.AgeRatingRank(user.AgeRating) {
+		return nil, apperrors.New("FORBIDDEN", fmt.Sprintf(
+			"un perfil no puede tener una clasificación mayor que la del titular (%s)", user.AgeRating))
+	}
+
+	existing, err := s.profileRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if len(existing) >= plan.MaxProfiles {
+		return nil, apperrors.New("FORBIDDEN", fmt.Sprintf(
+			"su plan %s permite %d perfil(es); cambie de plan para agregar más", plan.Name, plan.MaxProfiles))
+	}
+
+	profile := &models.Profile{
+		UserID:    userID,
+		Name:      name,
+		AgeRating: ageRating,
+		CreatedAt: time.Now(),
+	}
+	if err := s.profileRepo.Create(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// DeleteProfile borra un perfil de la cuenta junto con sus favoritos,
+// historial y calificaciones. El perfil principal no se puede borrar.
+func (s *ProfileService) DeleteProfile(userID, profileID int) error {
+	profile, err := s.GetProfile(userID, profileID)
+	if err != nil {
+		return err
+	}
+	profiles, err := s.profileRepo.FindByUserID(userID)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if profiles[0].ID == profile.ID {
+		return apperrors.ErrConflict("el perfil principal de la cuenta no se puede eliminar")
+	}
+
+	rated, err := s.contentService.ratedContent(profileID)
+	if err != nil {
+		return err
+	}
+	if err := s.profileRepo.Delete(profileID); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	// Sin las calificaciones del perfil, los promedios cambian.
+	for _, ref := range rated {
+		if err := s.contentService.refreshRatingStats(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SelectProfile elige el perfil con el que se usará la sesión.
+func (s *ProfileService) SelectProfile(userID, sessionID, profileID int) (*models.Profile, error) {
+	profile, err := s.GetProfile(userID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Locked {
+		return nil, apperrors.New("FORBIDDEN", "el perfil excede el límite de su plan; cambie de plan para usarlo")
+	}
+	if err := s.sessionRepo.SetProfile(sessionID, profile.ID); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return profile, nil
+}
+
+// ActiveProfile devuelve el perfil elegido en la sesión o, si todavía no se
+// eligió ninguno (o ya no se puede usar), el perfil principal de la cuenta.
+func (s *ProfileService) ActiveProfile(session *models.AuthSession) (*models.Profile, error) {
+	profiles, err := s.GetProfiles(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, apperrors.ErrNotFound("perfil")
+	}
+
+	if session.ProfileID != nil {
+		for i := range profiles {
+			if profiles[i].ID == *session.ProfileID && !profiles[i].Locked {
+				return &profiles[i], nil
+			}
+		}
+	}
+	return &profiles[0], nil
+}
+
+// accountPlan devuelve el titular de la cuenta y su plan actual.
+func (s *ProfileService) accountPlan(userID int) (*models.User, *models.Plan, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, apperrors.ErrNotFound("usuario")
+	}
+	plan, err := s.subRepo.GetPlanByID(user.PlanID)
+	if err != nil {
+		return nil, nil, apperrors.ErrDatabase(err)
+	}
+	if plan == nil {
+		return nil, nil, apperrors.ErrNotFound("plan")
+	}
+	return user, plan, nil
+}
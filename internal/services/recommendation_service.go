@@ -0,0 +1,435 @@
+// internal/services/recommendation_service.go
+// Recomendaciones por filtrado colaborativo ítem a ítem.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cantidad de recomendaciones.
+const (
+	DefaultRecommendationLimit = 10
+	MaxRecommendationLimit     = 50
+)
+
+// RecommendationRefreshInterval es cada cuánto se recalcula la similitud
+// entre contenidos.
+const RecommendationRefreshInterval = 10 * time.Minute
+
+// Cuánto pesa en los gustos de un perfil tener un contenido en Mi Lista o
+// haberlo reproducido. Una calificación pesa según su distancia al medio de
+// la escala (ver ratingWeight) y manda sobre las otras dos señales.
+const (
+	favoriteWeight = 1.0
+	watchedWeight  = 0.5
+)
+
+// RecommendationService recomienda a cada perfil contenido parecido a lo que
+// calificó bien, guardó en Mi Lista o reprodujo. Dos contenidos se parecen
+// cuando los mismos perfiles los disfrutan (similitud coseno entre sus
+// columnas de gustos). La similitud es la misma para todos los perfiles, así
+// que se guarda en memoria y se rehace cada RecommendationRefreshInterval;
+// los gustos del perfil que pide recomendaciones se leen en cada consulta.
+type RecommendationService struct {
+	recommendationRepo repositories.RecommendationRepo
+	contentRepo        repositories.ContentRepo
+	contentService     *ContentService
+
+	mu          sync.RWMutex
+	model       *tasteModel
+	refreshedAt time.Time
+}
+
+// NewRecommendationService crea una nueva instancia del servicio.
+func NewRecommendationService(recommendationRepo repositories.RecommendationRepo, contentRepo repositories.ContentRepo, contentService *ContentService) *RecommendationService {
+	return &RecommendationService{
+		recommendationRepo: recommendationRepo,
+		contentRepo:        contentRepo,
+		contentService:     contentService,
+	}
+}
+
+// tasteModel es lo que se precalcula de los gustos de todos los perfiles.
+type tasteModel struct {
+	// tastes son los gustos de cada perfil al momento del cálculo.
+	tastes map[int]map[models.ContentRef]taste
+	// similar guarda, para cada contenido, la similitud con los contenidos
+	// que comparten perfiles con él.
+	similar map[models.ContentRef]map[models.ContentRef]float64
+	// fans es cuántos perfiles disfrutan cada contenido.
+	fans map[models.ContentRef]int
+}
+
+// taste es cuánto le gusta un contenido a un perfil y la señal de la que sale.
+type taste struct {
+	weight float64
+	signal string
+	rating float64
+}
+
+// GetRecommendations devuelve hasta limit recomendaciones para el perfil,
+// de la más a la menos afín. Nunca incluye lo que el perfil ya vio, calificó
+// o tiene en Mi Lista, ni lo que no puede ver por su clasificación, su
+// control parental o la licencia en su región. Si no alcanzan las
+// similares, completa con lo que disfrutaron otros perfiles y después con lo
+// mejor calificado. limit <= 0 usa DefaultRecommendationLimit.
+func (s *RecommendationService) GetRecommendations(profile *models.Profile, limit int, now time.Time) ([]models.Recommendation, error) {
+	if limit <= 0 {
+		limit = DefaultRecommendationLimit
+	}
+	if limit > MaxRecommendationLimit {
+		limit = MaxRecommendationLimit
+	}
+
+	model, err := s.cachedModel(now)
+	if err != nil {
+		return nil, err
+	}
+	interactions, err := s.recommendationRepo.FindInteractionsByProfileID(profile.ID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	own := buildTastes(interactions)[profile.ID]
+
+	allowed, err := s.allowedCandidates(profile, model, own, now)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := similarContent(model, own, allowed)
+	picked := make(map[models.ContentRef]bool, len(recs))
+	for _, rec := range recs {
+		picked[rec.ContentRef] = true
+	}
+	if len(recs) < limit {
+		for _, rec := range popularContent(model, profile.ID, allowed) {
+			if !picked[rec.ContentRef] {
+				recs = append(recs, rec)
+				picked[rec.ContentRef] = true
+			}
+		}
+	}
+	if len(recs) < limit {
+		topRated, err := s.topRated(profile, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range topRated {
+			if _, seen := own[item.ContentRef]; seen || picked[item.ContentRef] {
+				continue
+			}
+			recs = append(recs, models.Recommendation{CatalogItem: item, Reason: models.ReasonTopRated})
+			picked[item.ContentRef] = true
+		}
+	}
+	if len(recs) > limit {
+		recs = recs[:limit]
+	}
+
+	return s.explain(recs, own)
+}
+
+// Refresh recalcula la similitud entre contenidos con las interacciones de
+// todos los perfiles.
+func (s *RecommendationService) Refresh(now time.Time) error {
+	interactions, err := s.recommendationRepo.FindInteractions()
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	model := buildModel(buildTastes(interactions))
+
+	s.mu.Lock()
+	s.model = model
+	s.refreshedAt = now
+	s.mu.Unlock()
+	return nil
+}
+
+// cachedModel devuelve la similitud guardada, recalculándola si está vencida.
+func (s *RecommendationService) cachedModel(now time.Time) (*tasteModel, error) {
+	s.mu.RLock()
+	model, refreshedAt := s.model, s.refreshedAt
+	s.mu.RUnlock()
+
+	if model == nil || now.Sub(refreshedAt) >= RecommendationRefreshInterval || now.Before(refreshedAt) {
+		if err := s.Refresh(now); err != nil {
+			return nil, err
+		}
+		s.mu.RLock()
+		model = s.model
+		s.mu.RUnlock()
+	}
+	return model, nil
+}
+
+// buildTastes arma la matriz de gustos: para cada perfil, cuánto le gusta
+// cada contenido con el que se relacionó.
+func buildTastes(interactions []models.Interaction) map[int]map[models.ContentRef]taste {
+	tastes := make(map[int]map[models.ContentRef]taste)
+	for _, i := range interactions {
+		items, ok := tastes[i.ProfileID]
+		if !ok {
+			items = make(map[models.ContentRef]taste)
+			tastes[i.ProfileID] = items
+		}
+
+		current, seen := items[i.ContentRef]
+		switch {
+		case i.Signal == models.SignalRated:
+			items[i.ContentRef] = taste{weight: ratingWeight(i.Rating), signal: i.Signal, rating: i.Rating}
+		case seen && current.signal == models.SignalRated:
+			// La calificación manda.
+		case i.Signal == models.SignalFavorite:
+			items[i.ContentRef] = taste{weight: favoriteWeight, signal: i.Signal}
+		case !seen:
+			items[i.ContentRef] = taste{weight: watchedWeight, signal: i.Signal}
+		}
+	}
+	return tastes
+}
+
+// ratingWeight lleva una calificación de 1 a 10 a un gusto entre -1 y 1: por
+// debajo de 5.5 cuenta en contra.
+func ratingWeight(rating float64) float64 {
+	return (rating - 5.5) / 4.5
+}
+
+// allowedCandidates devuelve el contenido que se le puede recomendar al
+// perfil: el que conocen otros perfiles, que el perfil no conoce y que puede
+// ver.
+func (s *RecommendationService) allowedCandidates(profile *models.Profile, model *tasteModel, own map[models.ContentRef]taste, now time.Time) (map[models.ContentRef]bool, error) {
+	var refs []models.ContentRef
+	seen := make(map[models.ContentRef]bool)
+	for profileID, items := range model.tastes {
+		if profileID == profile.ID {
+			continue
+		}
+		for ref := range items {
+			if _, known := own[ref]; known || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return s.contentService.allowedRefs(profile, refs, now)
+}
+
+// buildModel calcula la similitud coseno entre cada par de contenidos que
+// comparten algún perfil, y cuántos perfiles disfrutan cada contenido.
+func buildModel(tastes map[int]map[models.ContentRef]taste) *tasteModel {
+	norms := make(map[models.ContentRef]float64)
+	dots := make(map[models.ContentRef]map[models.ContentRef]float64)
+	fans := make(map[models.ContentRef]int)
+	for _, items := range tastes {
+		for a, ta := range items {
+			norms[a] += ta.weight * ta.weight
+			if ta.weight > 0 {
+				fans[a]++
+			}
+			for b, tb := range items {
+				if a == b {
+					continue
+				}
+				row, ok := dots[a]
+				if !ok {
+					row = make(map[models.ContentRef]float64)
+					dots[a] = row
+				}
+				row[b] += ta.weight * tb.weight
+			}
+		}
+	}
+
+	similar := make(map[models.ContentRef]map[models.ContentRef]float64, len(dots))
+	for a, row := range dots {
+		for b, dot := range row {
+			if dot == 0 {
+				continue
+			}
+			if similar[a] == nil {
+				similar[a] = make(map[models.ContentRef]float64)
+			}
+			similar[a][b] = dot / math.Sqrt(norms[a]*norms[b])
+		}
+	}
+	return &tasteModel{tastes: tastes, similar: similar, fans: fans}
+}
+
+// similarContent puntúa cada candidato sumando, por cada contenido que el
+// perfil conoce, su gusto por él multiplicado por la similitud entre ambos.
+// Because es el contenido que más aportó. Solo quedan los de puntaje
+// positivo, del mayor al menor.
+func similarContent(model *tasteModel, own map[models.ContentRef]taste, allowed map[models.ContentRef]bool) []models.Recommendation {
+	type score struct {
+		total, best float64
+		because     models.ContentRef
+	}
+	scores := make(map[models.ContentRef]*score)
+	for seed, seedTaste := range own {
+		for candidate, similarity := range model.similar[seed] {
+			if !allowed[candidate] {
+				continue
+			}
+			contribution := seedTaste.weight * similarity
+
+			sc, ok := scores[candidate]
+			if !ok {
+				sc = &score{}
+				scores[candidate] = sc
+			}
+			sc.total += contribution
+			if contribution > sc.best || (contribution == sc.best && refLess(seed, sc.because)) {
+				sc.best = contribution
+				sc.because = seed
+			}
+		}
+	}
+
+	var recs []models.Recommendation
+	for ref, sc := range scores {
+		if sc.total <= 0 || sc.best <= 0 {
+			continue
+		}
+		because := sc.because
+		recs = append(recs, models.Recommendation{
+			CatalogItem: models.CatalogItem{ContentRef: ref},
+			Score:       sc.total,
+			Reason:      models.ReasonSimilar,
+			Because:     &because,
+		})
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Score != recs[j].Score {
+			return recs[i].Score > recs[j].Score
+		}
+		return refLess(recs[i].ContentRef, recs[j].ContentRef)
+	})
+	return recs
+}
+
+// popularContent ordena los candidatos por cuántos otros perfiles los
+// disfrutan.
+func popularContent(model *tasteModel, profileID int, allowed map[models.ContentRef]bool) []models.Recommendation {
+	fans := make(map[models.ContentRef]int)
+	for ref, n := range model.fans {
+		if !allowed[ref] {
+			continue
+		}
+		if t, ok := model.tastes[profileID][ref]; ok && t.weight > 0 {
+			n--
+		}
+		if n > 0 {
+			fans[ref] = n
+		}
+	}
+
+	recs := make([]models.Recommendation, 0, len(fans))
+	for ref := range fans {
+		recs = append(recs, models.Recommendation{CatalogItem: models.CatalogItem{ContentRef: ref}, Reason: models.ReasonPopular})
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		a, b := recs[i].ContentRef, recs[j].ContentRef
+		if fans[a] != fans[b] {
+			return fans[a] > fans[b]
+		}
+		return refLess(a, b)
+	})
+	return recs
+}
+
+// topRated devuelve lo que el perfil puede ver de todos los catálogos, del
+// mejor al peor calificado.
+func (s *RecommendationService) topRated(profile *models.Profile, now time.Time) ([]models.CatalogItem, error) {
+	audiovisuals, err := s.contentService.GetAllAudiovisualForProfile(profile, now)
+	if err != nil {
+		return nil, err
+	}
+	audios, err := s.contentService.GetAllAudioForProfile(profile, now)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.CatalogItem, 0, len(audiovisuals)+len(audios))
+	for i := range audiovisuals {
+		items = append(items, audiovisuals[i].Item())
+	}
+	for i := range audios {
+		items = append(items, audios[i].Item())
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].WeightedRating > items[j].WeightedRating
+	})
+	return items, nil
+}
+
+// explain completa la ficha de cada recomendación y el texto que la explica.
+// Las que ya no están en el catálogo se descartan.
+func (s *RecommendationService) explain(recs []models.Recommendation, own map[models.ContentRef]taste) ([]models.Recommendation, error) {
+	var refs []models.ContentRef
+	for _, rec := range recs {
+		refs = append(refs, rec.ContentRef)
+		if rec.Because != nil {
+			refs = append(refs, *rec.Because)
+		}
+	}
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	explained := make([]models.Recommendation, 0, len(recs))
+	for _, rec := range recs {
+		item, ok := items[rec.ContentRef]
+		if !ok {
+			continue
+		}
+		rec.CatalogItem = item
+
+		switch rec.Reason {
+		case models.ReasonSimilar:
+			seed, ok := items[*rec.Because]
+			if !ok {
+				continue
+			}
+			rec.Explanation = becauseText(seed, own[*rec.Because])
+		case models.ReasonPopular:
+			rec.Explanation = "Popular entre otros perfiles"
+		case models.ReasonTopRated:
+			rec.Explanation = "De lo mejor calificado del catálogo"
+		}
+		explained = append(explained, rec)
+	}
+	return explained, nil
+}
+
+// becauseText explica una recomendación por el contenido del perfil al que
+// se parece.
+func becauseText(seed models.CatalogItem, t taste) string {
+	switch t.signal {
+	case models.SignalRated:
+		return fmt.Sprintf("Porque calificaste %s con %.1f⭐", seed.Title, t.rating)
+	case models.SignalFavorite:
+		return fmt.Sprintf("Porque tienes %s en Mi Lista", seed.Title)
+	}
+	if seed.ContentType == models.ContentTypeAudio {
+		return fmt.Sprintf("Porque escuchaste %s", seed.Title)
+	}
+	return fmt.Sprintf("Porque viste %s", seed.Title)
+}
+
+// refLess ordena referencias por catálogo y id, para desempatar siempre
+// igual.
+func refLess(a, b models.ContentRef) bool {
+	if a.ContentType != b.ContentType {
+		return a.ContentType < b.ContentType
+	}
+	return a.ContentID < b.ContentID
+}
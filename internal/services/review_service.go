@@ -0,0 +1,225 @@
+// internal/services/review_service.go
+// Reseñas escritas, votos de "útil", denuncias y moderación.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ReviewService gestiona las reseñas que acompañan las calificaciones. Una
+// reseña nueva o editada no se muestra hasta que un moderador la aprueba;
+// una aprobada que recibe denuncias vuelve a la cola de moderación sin dejar
+// de mostrarse.
+type ReviewService struct {
+	reviewRepo  repositories.ReviewRepo
+	contentRepo repositories.ContentRepo
+	userRepo    repositories.UserRepo
+	parental    *ParentalService
+}
+
+// NewReviewService crea una nueva instancia del servicio.
+func NewReviewService(reviewRepo repositories.ReviewRepo, contentRepo repositories.ContentRepo, userRepo repositories.UserRepo, parental *ParentalService) *ReviewService {
+	return &ReviewService{
+		reviewRepo:  reviewRepo,
+		contentRepo: contentRepo,
+		userRepo:    userRepo,
+		parental:    parental,
+	}
+}
+
+// WriteReview crea o reemplaza la reseña del perfil sobre el contenido, que
+// tiene que haber calificado antes y poder ver en now. La reseña queda
+// pendiente de moderación.
+func (s *ReviewService) WriteReview(profile *models.Profile, ref models.ContentRef, body string, spoiler bool, now time.Time) (*models.Review, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, apperrors.New("INVALID_INPUT", "la reseña no puede estar vacía")
+	}
+	if utf8.RuneCountInString(body) > models.MaxReviewLength {
+		return nil, apperrors.New("INVALID_INPUT", fmt.Sprintf("la reseña no puede superar los %d caracteres", models.MaxReviewLength))
+	}
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parental, profile, ref, now); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.reviewRepo.Save(profile.ID, ref, body, spoiler, now)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if !saved {
+		return nil, apperrors.New("INVALID_INPUT", "califique el contenido antes de escribir una reseña")
+	}
+	return s.GetMyReview(profile.ID, ref)
+}
+
+// GetMyReview devuelve la reseña del perfil sobre el contenido, en cualquier
+// estado.
+func (s *ReviewService) GetMyReview(profileID int, ref models.ContentRef) (*models.Review, error) {
+	review, err := s.reviewRepo.FindByProfile(profileID, ref)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if review == nil {
+		return nil, apperrors.New("NOT_FOUND", "reseña no encontrada")
+	}
+	return review, nil
+}
+
+// DeleteMyReview borra la reseña del perfil; la calificación queda.
+func (s *ReviewService) DeleteMyReview(profileID int, ref models.ContentRef) error {
+	review, err := s.GetMyReview(profileID, ref)
+	if err != nil {
+		return err
+	}
+	if err := s.reviewRepo.Delete(review.ID); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// GetReviews devuelve las reseñas aprobadas del contenido, las más útiles
+// primero, indicando cuáles marcó como útiles el perfil que consulta. Solo
+// si ese perfil puede ver el contenido en now.
+func (s *ReviewService) GetReviews(viewer *models.Profile, ref models.ContentRef, now time.Time) ([]models.Review, error) {
+	if err := checkVisible(s.contentRepo, s.userRepo, s.parental, viewer, ref, now); err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviewRepo.FindApproved(ref, viewer.ID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if reviews == nil {
+		reviews = []models.Review{}
+	}
+	return reviews, nil
+}
+
+// VoteHelpful marca (o desmarca, con helpful en false) una reseña como útil
+// para el perfil. Nadie puede votar su propia reseña.
+func (s *ReviewService) VoteHelpful(reviewID, profileID int, helpful bool, now time.Time) error {
+	if _, err := s.othersVisibleReview(reviewID, profileID, "no puede votar su propia reseña"); err != nil {
+		return err
+	}
+
+	var err error
+	if helpful {
+		err = s.reviewRepo.AddVote(reviewID, profileID, now)
+	} else {
+		err = s.reviewRepo.RemoveVote(reviewID, profileID)
+	}
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// ReportReview denuncia una reseña ante los moderadores. Cada perfil puede
+// denunciar una misma reseña una sola vez.
+func (s *ReviewService) ReportReview(reviewID, profileID int, reason string, now time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return apperrors.New("INVALID_INPUT", "indique el motivo de la denuncia")
+	}
+	if utf8.RuneCountInString(reason) > models.MaxReportReasonLength {
+		return apperrors.New("INVALID_INPUT", fmt.Sprintf("el motivo no puede superar los %d caracteres", models.MaxReportReasonLength))
+	}
+	if _, err := s.othersVisibleReview(reviewID, profileID, "no puede denunciar su propia reseña"); err != nil {
+		return err
+	}
+
+	added, err := s.reviewRepo.AddReport(models.ReviewReport{
+		ReviewID:   reviewID,
+		ProfileID:  profileID,
+		Reason:     reason,
+		ReportedAt: now,
+	})
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if !added {
+		return apperrors.ErrConflict("ya denunció esta reseña")
+	}
+	return nil
+}
+
+// othersVisibleReview busca una reseña aprobada que no sea del perfil.
+func (s *ReviewService) othersVisibleReview(reviewID, profileID int, ownMessage string) (*models.Review, error) {
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if review == nil || review.Status != models.ReviewApproved {
+		return nil, apperrors.New("NOT_FOUND", "reseña no encontrada")
+	}
+	if review.ProfileID == profileID {
+		return nil, apperrors.ErrConflict(ownMessage)
+	}
+	return review, nil
+}
+
+// GetModerationQueue devuelve las reseñas pendientes y las aprobadas con
+// denuncias sin resolver, con la ficha del contenido.
+func (s *ReviewService) GetModerationQueue(actor *models.User) ([]models.Review, error) {
+	if err := authorize(actor, models.PermModerateReviews); err != nil {
+		return nil, err
+	}
+
+	queue, err := s.reviewRepo.FindModerationQueue()
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	refs := make([]models.ContentRef, len(queue))
+	for i := range queue {
+		refs[i] = queue[i].ContentRef
+	}
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range queue {
+		if item, ok := items[queue[i].ContentRef]; ok {
+			queue[i].Item = &item
+		}
+	}
+	if queue == nil {
+		queue = []models.Review{}
+	}
+	return queue, nil
+}
+
+// ModerateReview aprueba, oculta o borra una reseña. Aprobarla u ocultarla
+// resuelve sus denuncias.
+func (s *ReviewService) ModerateReview(actor *models.User, reviewID int, action string, now time.Time) error {
+	if err := authorize(actor, models.PermModerateReviews); err != nil {
+		return err
+	}
+
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if review == nil {
+		return apperrors.New("NOT_FOUND", "reseña no encontrada")
+	}
+
+	switch action {
+	case models.ModerationApprove:
+		err = s.reviewRepo.SetStatus(reviewID, models.ReviewApproved, actor.ID, now)
+	case models.ModerationHide:
+		err = s.reviewRepo.SetStatus(reviewID, models.ReviewHidden, actor.ID, now)
+	case models.ModerationDelete:
+		err = s.reviewRepo.Delete(reviewID)
+	default:
+		return apperrors.ErrInvalidInput("action")
+	}
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// internal/services/search_service.go
+// Buscador de texto completo sobre todos los catálogos.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Cantidad de resultados del buscador.
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+// SearchService busca por título, sinopsis, director, artista, álbum y
+// género, con el filtro de perfil y región del resto del catálogo.
+type SearchService struct {
+	searchRepo     repositories.SearchRepo
+	contentService *ContentService
+}
+
+// NewSearchService crea una nueva instancia del servicio.
+func NewSearchService(searchRepo repositories.SearchRepo, contentService *ContentService) *SearchService {
+	return &SearchService{
+		searchRepo:     searchRepo,
+		contentService: contentService,
+	}
+}
+
+// Search devuelve hasta limit resultados de todos los catálogos juntos, del
+// más al menos relevante. Cada palabra del texto debe aparecer en algún campo
+// (también como comienzo de palabra) y no importan las tildes ni las
+// mayúsculas. limit <= 0 usa DefaultSearchLimit.
+func (s *SearchService) Search(profile *models.Profile, text string, limit int, now time.Time) ([]models.SearchResult, error) {
+	query := ftsQuery(text)
+	if query == "" {
+		return nil, apperrors.New("INVALID_INPUT", "ingrese al menos una palabra para buscar")
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	results := []models.SearchResult{}
+	for _, catalog := range models.Catalogs {
+		filter, err := s.contentService.profileFilter(profile, catalog.ContentType, now)
+		if err != nil {
+			return nil, err
+		}
+		found, err := s.searchRepo.Search(query, catalog.ContentType, filter, limit)
+		if err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+		results = append(results, found...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// ftsQuery arma la consulta FTS4 a partir del texto del usuario: cada palabra
+// entre comillas y como prefijo, de modo que los operadores de FTS4 no se
+// interpreten. Devuelve "" si el texto no tiene palabras.
+func ftsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `*"`
+	}
+	return strings.Join(terms, " ")
+}
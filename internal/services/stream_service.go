@@ -0,0 +1,148 @@
+// internal/services/stream_service.go
+// Registro de reproducciones en curso para aplicar el límite de dispositivos
+// de cada plan.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"SDGEStreaming/internal/utils"
+	"fmt"
+	"time"
+)
+
+// CodeStreamLimit indica que el usuario ya usa todos los dispositivos que
+// permite su plan.
+const CodeStreamLimit = "STREAM_LIMIT"
+
+// StreamHeartbeatTimeout es cuánto puede pasar sin señales de un cliente antes
+// de que su sesión deje de contar como activa (p. ej. si se cerró sin avisar).
+const StreamHeartbeatTimeout = 2 * time.Minute
+
+// StreamService lleva las sesiones de reproducción por usuario y dispositivo.
+type StreamService struct {
+	streamRepo  repositories.StreamSessionRepo
+	userRepo    repositories.UserRepo
+	subRepo     repositories.SubscriptionRepo
+	contentRepo repositories.ContentRepo
+	parental    *ParentalService
+}
+
+// NewStreamService crea una nueva instancia del servicio.
+func NewStreamService(streamRepo repositories.StreamSessionRepo, userRepo repositories.UserRepo, subRepo repositories.SubscriptionRepo, contentRepo repositories.ContentRepo, parental *ParentalService) *StreamService {
+	return &StreamService{
+		streamRepo:  streamRepo,
+		userRepo:    userRepo,
+		subRepo:     subRepo,
+		contentRepo: contentRepo,
+		parental:    parental,
+	}
+}
+
+// StartStream abre una sesión de reproducción en el dispositivo indicado. Si
+// ese dispositivo ya reproducía algo, la sesión anterior se reemplaza; si no,
+// se rechaza cuando el usuario ya alcanzó el máximo de dispositivos del plan.
+// La licencia del contenido en la región de la cuenta y el control parental
+// del perfil se aplican antes; pin es el PIN parental si el usuario lo
+// ingresó.
+func (s *StreamService) StartStream(profile *models.Profile, device string, contentID int, contentType, pin string, now time.Time) (*models.StreamSession, error) {
+	if utils.IsEmpty(device) {
+		return nil, apperrors.ErrInvalidInput("device")
+	}
+	if err := s.checkContent(contentID, contentType); err != nil {
+		return nil, err
+	}
+	userID := profile.UserID
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound("usuario")
+	}
+	if err := checkLicense(s.contentRepo, contentID, contentType, user.Country, now); err != nil {
+		return nil, err
+	}
+	if err := s.parental.CheckPlayback(profile, contentID, contentType, pin, now); err != nil {
+		return nil, err
+	}
+	plan, err := s.subRepo.GetPlanByID(user.PlanID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if plan == nil {
+		return nil, apperrors.ErrNotFound("plan")
+	}
+
+	session := &models.StreamSession{
+		UserID:      userID,
+		Device:      device,
+		ContentID:   contentID,
+		ContentType: contentType,
+		StartedAt:   now,
+		LastSeenAt:  now,
+	}
+	started, err := s.streamRepo.Start(session, plan.MaxDevices, now.Add(-StreamHeartbeatTimeout))
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if !started {
+		return nil, apperrors.New(CodeStreamLimit, fmt.Sprintf(
+			"su plan %s permite %d reproducción(es) simultánea(s); detenga otra sesión para continuar", plan.Name, plan.MaxDevices))
+	}
+	return session, nil
+}
+
+// Heartbeat mantiene viva una sesión; los clientes deben llamarlo con más
+// frecuencia que StreamHeartbeatTimeout.
+func (s *StreamService) Heartbeat(userID, sessionID int, now time.Time) (*models.StreamSession, error) {
+	session, err := s.findActive(userID, sessionID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.streamRepo.Touch(session.ID, now); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	session.LastSeenAt = now
+	return session, nil
+}
+
+// StopStream termina una sesión del usuario, sea la propia al dejar de
+// reproducir o la de otro dispositivo para liberar su lugar.
+func (s *StreamService) StopStream(userID, sessionID int, now time.Time) error {
+	session, err := s.findActive(userID, sessionID, now)
+	if err != nil {
+		return err
+	}
+	if err := s.streamRepo.End(session.ID, now); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// GetActiveSessions devuelve las reproducciones en curso del usuario.
+func (s *StreamService) GetActiveSessions(userID int, now time.Time) ([]models.StreamSession, error) {
+	list, err := s.streamRepo.FindActiveByUserID(userID, now.Add(-StreamHeartbeatTimeout))
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return list, nil
+}
+
+// findActive busca una sesión activa del usuario; las ajenas se reportan
+// como inexistentes.
+func (s *StreamService) findActive(userID, sessionID int, now time.Time) (*models.StreamSession, error) {
+	session, err := s.streamRepo.FindByID(sessionID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if session == nil || session.UserID != userID || session.EndedAt != nil ||
+		session.LastSeenAt.Before(now.Add(-StreamHeartbeatTimeout)) {
+		return nil, apperrors.New("NOT_FOUND", "la sesión de reproducción no existe o ya terminó")
+	}
+	return session, nil
+}
+
+func (s *StreamService) checkContent(contentID int, contentType string) error {
+	_, err := findItem(s.contentRepo, models.ContentRef{ContentID: contentID, ContentType: contentType})
+	return err
+}
@@ -0,0 +1,100 @@
+// internal/services/stream_service_test.go
+package services
+
+import (
+	"SDGEStreaming/internal/models"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStartStreamDeviceLimit(t *testing.T) {
+	type start struct {
+		device string
+		after  time.Duration // desde el primer inicio
+		want   string
+	}
+	// newUser está en Premium 4K: hasta 4 dispositivos a la vez.
+	tests := []struct {
+		name   string
+		starts []start
+	}{
+		{
+			name: "el quinto dispositivo se rechaza",
+			starts: []start{
+				{device: "tv"}, {device: "celular"}, {device: "tablet"}, {device: "notebook"},
+				{device: "consola", want: CodeStreamLimit},
+			},
+		},
+		{
+			name: "el mismo dispositivo reemplaza su sesión",
+			starts: []start{
+				{device: "tv"}, {device: "celular"}, {device: "tablet"}, {device: "notebook"},
+				{device: "tv"}, {device: "tv"},
+			},
+		},
+		{
+			name: "las sesiones sin señales no ocupan lugar",
+			starts: []start{
+				{device: "tv"}, {device: "celular"}, {device: "tablet"}, {device: "notebook"},
+				{device: "consola", after: StreamHeartbeatTimeout + time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+			movie := s.newMovie(t, "Matrix", "PG")
+
+			start := time.Now()
+			for i, st := range tt.starts {
+				_, err := s.streams.StartStream(profile, st.device, movie.ID, models.ContentTypeAudiovisual, "", start.Add(st.after))
+				if got := errorCode(err); got != st.want {
+					t.Fatalf("inicio %d (%s): %q, se esperaba %q", i+1, st.device, got, st.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStartStreamConcurrentDevices(t *testing.T) {
+	s := newTestServices(t)
+	profile := s.mainProfile(t, s.newUser(t, models.RoleViewer, models.DefaultCountry))
+	movie := s.newMovie(t, "Matrix", "PG")
+	now := time.Now()
+
+	const devices = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, devices)
+	for i := range devices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.streams.StartStream(profile, fmt.Sprintf("dispositivo %d", i), movie.ID, models.ContentTypeAudiovisual, "", now)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	started := 0
+	for err := range errs {
+		switch errorCode(err) {
+		case "":
+			started++
+		case CodeStreamLimit:
+		default:
+			t.Errorf("StartStream: %v", err)
+		}
+	}
+	active, err := s.streams.GetActiveSessions(profile.UserID, now)
+	if err != nil {
+		t.Fatalf("GetActiveSessions: %v", err)
+	}
+	if started != 4 || len(active) != 4 {
+		t.Errorf("%d inicios aceptados y %d sesiones activas, se esperaban 4 y 4", started, len(active))
+	}
+}
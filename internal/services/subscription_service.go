@@ -2,69 +2,539 @@
 package services
 
 import (
+	apperrors "SDGEStreaming/internal/errors"
 	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/payments"
 	"SDGEStreaming/internal/repositories"
+	"SDGEStreaming/internal/utils"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// FreePlanID es el plan al que vuelve un usuario cuando su suscripción vence.
+const FreePlanID = 1
+
+const (
+	// maxPaymentAttempts es cuántas veces se intenta autorizar un pago ante
+	// fallos transitorios de la pasarela.
+	maxPaymentAttempts = 3
+	paymentRetryDelay  = 200 * time.Millisecond
 )
 
 type SubscriptionService struct {
-	subRepo  repositories.SubscriptionRepo
-	userRepo repositories.UserRepo
+	subRepo     repositories.SubscriptionRepo
+	userRepo    repositories.UserRepo
+	billingRepo repositories.BillingRepo
+	gateway     payments.Gateway
 }
 
-func NewSubscriptionService(subRepo repositories.SubscriptionRepo, userRepo repositories.UserRepo) *SubscriptionService {
-	return &SubscriptionService{subRepo: subRepo, userRepo: userRepo}
+func NewSubscriptionService(subRepo repositories.SubscriptionRepo, userRepo repositories.UserRepo, billingRepo repositories.BillingRepo, gateway payments.Gateway) *SubscriptionService {
+	return &SubscriptionService{subRepo: subRepo, userRepo: userRepo, billingRepo: billingRepo, gateway: gateway}
 }
 
 func (s *SubscriptionService) ProcessPayment(userID int, planID int, cardHolder, cardNumber string, expiryMonth, expiryYear, cvv int) error {
 	// Validación básica de la tarjeta
+	if utils.IsEmpty(cardHolder) {
+		return apperrors.New("INVALID_INPUT", "el nombre del titular es obligatorio")
+	}
 	if len(cardNumber) < 13 || len(cardNumber) > 19 {
-		return fmt.Errorf("número de tarjeta inválido")
+		return apperrors.New("INVALID_INPUT", "número de tarjeta inválido")
 	}
 	if expiryMonth < 1 || expiryMonth > 12 {
-		return fmt.Errorf("mes de vencimiento inválido")
+		return apperrors.New("INVALID_INPUT", "mes de vencimiento inválido")
 	}
-	if cvv < 100 || cvv > 999 {
-		return fmt.Errorf("CVV inválido")
+	if cvv < 100 || cvv > 9999 {
+		return apperrors.New("INVALID_INPUT", "CVV inválido")
 	}
 
-	// Obtener el plan
+	// Calcular el monto: mes completo para un alta, diferencia prorrateada
+	// para un plan superior. Los planes inferiores no se cobran.
+	now := time.Now()
+	change, err := s.PreviewPlanChange(userID, planID, now)
+	if err != nil {
+		return err
+	}
+	if change.AmountCents == 0 {
+		return apperrors.New("INVALID_INPUT", "este cambio de plan no requiere pago")
+	}
 	plan, err := s.subRepo.GetPlanByID(planID)
 	if err != nil {
 		return err
 	}
 
-	// Simular el procesamiento del pago
-	fmt.Printf("Procesando pago de $%.2f para el plan '%s'...\n", plan.Price, plan.Name)
-	fmt.Println("¡Pago aprobado!")
+	// Tokenizar la tarjeta: el número completo y el CVV solo llegan a la
+	// pasarela, nunca a la base de datos.
+	card, err := s.gateway.Tokenize(payments.Card{
+		Holder:      cardHolder,
+		Number:      cardNumber,
+		ExpiryMonth: expiryMonth,
+		ExpiryYear:  expiryYear,
+		CVV:         fmt.Sprintf("%03d", cvv),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cobrar antes de tocar la base de datos: si el pago falla, el plan no cambia
+	charge, err := s.charge(card.Token, change.AmountCents)
+	if err != nil {
+		return err
+	}
 
-	// Guardar el método de pago
-	last4 := cardNumber[len(cardNumber)-4:]
-expirationDate := fmt.Sprintf("%02d/%04d", expiryMonth, expiryYear)
+	// Todo cobro queda en el libro de pagos antes de aplicar el cambio de plan
+	kind := models.TransactionCharge
+	if change.Kind == models.PlanChangeUpgrade {
+		kind = models.TransactionProration
+	}
+	invoice, err := s.recordCharge(userID, plan, kind, charge, now, change.PeriodEnd)
+	if err != nil {
+		s.refundUnrecorded(charge)
+		return err
+	}
 
-method := &models.PaymentMethod{
-    UserID:        userID,
-    CardHolder:    cardHolder,
-    CardNumber:    cardNumber,
-    ExpirationDate: expirationDate,
-    CVV:           fmt.Sprintf("%03d", cvv),
-    Last4:         last4,
-    ExpiryMonth:   expiryMonth,
-    ExpiryYear:    expiryYear,
-    IsDefault:     true,
-}
+	// Guardar el método de pago tokenizado
+	method := &models.PaymentMethod{
+		UserID:      userID,
+		CardHolder:  cardHolder,
+		Token:       card.Token,
+		Brand:       card.Brand,
+		Last4:       card.Last4,
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+		IsDefault:   true,
+	}
 	if err := s.userRepo.AddPaymentMethod(method); err != nil {
+		s.refund(invoice, charge, "no se pudo guardar el método de pago")
 		return fmt.Errorf("error al guardar el método de pago")
 	}
 
-	// Actualizar el plan del usuario
-	if err := s.userRepo.UpdatePlan(userID, planID); err != nil {
-		return fmt.Errorf("error al actualizar el plan")
+	// Abrir el nuevo período y actualizar el plan del usuario
+	if _, err := s.startSubscription(userID, planID, now, change.PeriodEnd); err != nil {
+		s.refund(invoice, charge, "no se pudo aplicar el cambio de plan")
+		return err
 	}
 
 	return nil
 }
 
+// PreviewPlanChange calcula qué pasaría al cambiar al plan indicado, sin
+// aplicar nada. Un plan superior se cobra hoy por la parte que queda del
+// período, descontando lo no usado del plan actual; uno inferior no se cobra
+// y se aplica al terminar el período.
+func (s *SubscriptionService) PreviewPlanChange(userID, planID int, now time.Time) (*models.PlanChange, error) {
+	plan, err := s.subRepo.GetPlanByID(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, apperrors.ErrNotFound("plan")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound("usuario")
+	}
+
+	sub, err := s.subRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	change := &models.PlanChange{CurrentPlanID: user.PlanID, NewPlanID: plan.ID}
+	if sub != nil {
+		change.CurrentPlanID = sub.PlanID
+	}
+	if change.CurrentPlanID == plan.ID {
+		// Volver al plan actual solo tiene sentido para deshacer un cambio
+		// programado, y no se cobra.
+		if sub == nil || sub.PendingPlanID == nil {
+			return nil, apperrors.ErrConflict("ya está suscrito a este plan")
+		}
+		change.Kind = models.PlanChangeUpgrade
+		change.EffectiveAt = now
+		change.PeriodEnd = sub.EndDate
+		return change, nil
+	}
+
+	// Sin período en curso (plan Free o asignado a mano) se empieza de cero.
+	if sub == nil {
+		change.Kind = models.PlanChangeNew
+		change.EffectiveAt = now
+		change.PeriodEnd = nextPeriodEnd(now)
+		if plan.ID != FreePlanID {
+			change.AmountCents = payments.ToCents(plan.Price)
+		}
+		return change, nil
+	}
+
+	current, err := s.subRepo.GetPlanByID(sub.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, apperrors.ErrNotFound("plan")
+	}
+
+	change.PeriodEnd = sub.EndDate
+	if plan.Price <= current.Price {
+		change.Kind = models.PlanChangeDowngrade
+		change.EffectiveAt = sub.EndDate
+		return change, nil
+	}
+
+	remaining := prorate(sub, now)
+	change.Kind = models.PlanChangeUpgrade
+	change.EffectiveAt = now
+	change.CreditCents = remaining(payments.ToCents(current.Price))
+	change.AmountCents = remaining(payments.ToCents(plan.Price)) - change.CreditCents
+	return change, nil
+}
+
+// ChangePlan aplica los cambios de plan que no requieren pago: programa el
+// paso a un plan inferior para el final del período (a Free equivale a
+// cancelar la renovación). Volver al plan actual deshace un cambio programado.
+// Los cambios con cobro se hacen con ProcessPayment.
+func (s *SubscriptionService) ChangePlan(userID, planID int, now time.Time) (*models.PlanChange, error) {
+	change, err := s.PreviewPlanChange(userID, planID, now)
+	if err != nil {
+		return nil, err
+	}
+	if change.AmountCents > 0 {
+		return nil, apperrors.New("INVALID_INPUT", "este cambio de plan requiere pago")
+	}
+
+	sub, err := s.subRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	switch {
+	case sub == nil:
+		// Solo llega aquí el paso a Free desde un plan asignado a mano.
+		if err := s.userRepo.UpdatePlan(userID, planID); err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+	case sub.PlanID == planID:
+		if err := s.subRepo.SetPendingPlan(sub.ID, nil); err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+	case change.Kind == models.PlanChangeUpgrade:
+		// Diferencia prorrateada nula: el período queda igual, solo cambia el plan.
+		if _, err := s.startSubscription(userID, planID, now, change.PeriodEnd); err != nil {
+			return nil, err
+		}
+	case planID == FreePlanID:
+		if sub.PendingPlanID != nil {
+			if err := s.subRepo.SetPendingPlan(sub.ID, nil); err != nil {
+				return nil, apperrors.ErrDatabase(err)
+			}
+		}
+		if sub.AutoRenew {
+			if err := s.subRepo.Cancel(userID); err != nil {
+				return nil, apperrors.ErrDatabase(err)
+			}
+		}
+	case !sub.AutoRenew:
+		return nil, apperrors.ErrConflict("la suscripción está cancelada y no se renovará")
+	default:
+		if err := s.subRepo.SetPendingPlan(sub.ID, &planID); err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+	}
+	return change, nil
+}
+
+// prorate devuelve una función que calcula la parte de un monto mensual
+// correspondiente a lo que queda del período a la fecha `now`.
+func prorate(sub *models.Subscription, now time.Time) func(int64) int64 {
+	total := sub.EndDate.Sub(sub.StartDate)
+	left := sub.EndDate.Sub(now)
+	if left < 0 {
+		left = 0
+	}
+	if left > total {
+		left = total
+	}
+	return func(cents int64) int64 {
+		if total <= 0 {
+			return 0
+		}
+		return int64(math.Round(float64(cents) * float64(left) / float64(total)))
+	}
+}
+
+// charge autoriza y captura el monto. Los fallos transitorios de la pasarela
+// se reintentan; si la captura falla se anula la autorización para liberar
+// los fondos retenidos.
+func (s *SubscriptionService) charge(token string, amountCents int64) (*payments.Charge, error) {
+	var auth *payments.Authorization
+	var err error
+	for attempt := 1; attempt <= maxPaymentAttempts; attempt++ {
+		auth, err = s.gateway.Authorize(token, amountCents)
+		if err == nil || !payments.IsRetryable(err) {
+			break
+		}
+		if attempt < maxPaymentAttempts {
+			time.Sleep(paymentRetryDelay * time.Duration(attempt))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.gateway.Capture(auth.ID)
+	if err != nil {
+		if voidErr := s.gateway.Void(auth.ID); voidErr != nil {
+			log.Printf("no se pudo anular la autorización %s: %v", auth.ID, voidErr)
+		}
+		return nil, err
+	}
+	return charge, nil
+}
+
+// recordCharge emite la factura del período y registra el cobro en el libro.
+// kind es TransactionCharge para un mes completo o TransactionProration para
+// la diferencia de un cambio a mitad de período.
+func (s *SubscriptionService) recordCharge(userID int, plan *models.Plan, kind string, charge *payments.Charge, start, end time.Time) (*models.Invoice, error) {
+	description := fmt.Sprintf("Plan %s (%s - %s)", plan.Name, start.Format("02/01/2006"), end.Format("02/01/2006"))
+	entry := "Cobro del plan " + plan.Name
+	if kind == models.TransactionProration {
+		description = "Cambio a " + description + ", prorrateado"
+		entry = "Prorrateo del cambio al plan " + plan.Name
+	}
+
+	invoice := &models.Invoice{
+		UserID:      userID,
+		PlanID:      plan.ID,
+		Description: description,
+		AmountCents: charge.AmountCents,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Transactions: []models.PaymentTransaction{{
+			Kind:        kind,
+			AmountCents: charge.AmountCents,
+			GatewayRef:  charge.ID,
+			Description: entry,
+		}},
+	}
+	if err := s.billingRepo.CreateInvoice(invoice); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return invoice, nil
+}
+
+// refund devuelve el cobro de una factura cuyo cambio de plan no se pudo
+// aplicar, y deja la devolución en el libro de pagos.
+func (s *SubscriptionService) refund(invoice *models.Invoice, charge *payments.Charge, reason string) {
+	refund, err := s.gateway.Refund(charge.ID, charge.AmountCents)
+	if err != nil {
+		log.Printf("no se pudo devolver el cobro %s: %v", charge.ID, err)
+		return
+	}
+
+	entry := &models.PaymentTransaction{
+		UserID:      invoice.UserID,
+		InvoiceID:   invoice.ID,
+		Kind:        models.TransactionRefund,
+		AmountCents: -refund.AmountCents,
+		GatewayRef:  refund.ID,
+		Description: "Devolución: " + reason,
+	}
+	if err := s.billingRepo.AddTransaction(entry); err != nil {
+		log.Printf("devolución %s realizada pero no registrada en el libro: %v", refund.ID, err)
+	}
+}
+
+// refundUnrecorded devuelve un cobro que no llegó a facturarse.
+func (s *SubscriptionService) refundUnrecorded(charge *payments.Charge) {
+	if _, err := s.gateway.Refund(charge.ID, charge.AmountCents); err != nil {
+		log.Printf("no se pudo devolver el cobro %s: %v", charge.ID, err)
+	}
+}
+
 func (s *SubscriptionService) GetAvailablePlans() ([]models.Plan, error) {
 	return s.subRepo.GetAllPlans()
 }
+
+// GetActiveSubscription devuelve el período en curso del usuario, o nil si
+// no tiene ninguno (plan Free o plan asignado manualmente).
+func (s *SubscriptionService) GetActiveSubscription(userID int) (*models.Subscription, error) {
+	sub, err := s.subRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return sub, nil
+}
+
+// GetSubscriptionHistory devuelve todos los períodos del usuario, el más
+// reciente primero.
+func (s *SubscriptionService) GetSubscriptionHistory(userID int) ([]models.Subscription, error) {
+	list, err := s.subRepo.FindHistoryByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return list, nil
+}
+
+// CancelSubscription desactiva la renovación automática. El usuario conserva
+// el plan hasta el final del período pagado y luego pasa a Free.
+func (s *SubscriptionService) CancelSubscription(userID int) (*models.Subscription, error) {
+	sub, err := s.subRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	if sub == nil {
+		return nil, apperrors.New("NOT_FOUND", "no tiene una suscripción activa")
+	}
+	if !sub.AutoRenew {
+		return nil, apperrors.ErrConflict("la suscripción ya está cancelada")
+	}
+
+	if err := s.subRepo.Cancel(userID); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return s.GetActiveSubscription(userID)
+}
+
+// ProcessExpirations cierra los períodos vencidos a la fecha `now`: renueva
+// los que tienen renovación automática y devuelve a Free al resto. Si una
+// suscripción falla se sigue con las demás, que ya quedan confirmadas, y se
+// devuelven juntos los errores. Devuelve cuántas suscripciones se procesaron
+// bien.
+func (s *SubscriptionService) ProcessExpirations(now time.Time) (int, error) {
+	expired, err := s.subRepo.FindExpired(now)
+	if err != nil {
+		return 0, apperrors.ErrDatabase(err)
+	}
+
+	processed := 0
+	var errs []error
+	for _, sub := range expired {
+		if err := s.closePeriod(sub, now); err != nil {
+			errs = append(errs, fmt.Errorf("suscripción %d: %w", sub.ID, err))
+			continue
+		}
+		processed++
+	}
+	return processed, errors.Join(errs...)
+}
+
+// RefreshSubscription aplica el vencimiento solo para un usuario; se llama
+// al iniciar sesión para que el plan mostrado esté al día.
+func (s *SubscriptionService) RefreshSubscription(userID int, now time.Time) error {
+	sub, err := s.subRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	if sub == nil || sub.EndDate.After(now) {
+		return nil
+	}
+	return s.closePeriod(*sub, now)
+}
+
+// closePeriod cierra un período vencido y abre el siguiente si corresponde.
+func (s *SubscriptionService) closePeriod(sub models.Subscription, now time.Time) error {
+	if err := s.subRepo.Deactivate(sub.ID); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	// Un cambio a un plan inferior programado rige desde la renovación.
+	if sub.PendingPlanID != nil {
+		sub.PlanID = *sub.PendingPlanID
+	}
+
+	if !sub.AutoRenew || sub.PlanID == FreePlanID {
+		if err := s.userRepo.UpdatePlan(sub.UserID, FreePlanID); err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+		return nil
+	}
+
+	// El período nuevo continúa al anterior; si la aplicación estuvo detenida
+	// más de un ciclo, se cobra un solo período a partir de `now`.
+	start := sub.EndDate
+	if !nextPeriodEnd(start).After(now) {
+		start = now
+	}
+	end := nextPeriodEnd(start)
+
+	// Cobrar la renovación con la tarjeta predeterminada. Sin tarjeta o con
+	// el pago rechazado, el usuario vuelve a Free.
+	if err := s.chargeRenewal(sub, start, end); err != nil {
+		log.Printf("renovación de la suscripción %d rechazada: %v", sub.ID, err)
+		if err := s.userRepo.UpdatePlan(sub.UserID, FreePlanID); err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+		return nil
+	}
+
+	if err := s.userRepo.UpdatePlan(sub.UserID, sub.PlanID); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	renewal := &models.Subscription{
+		UserID:    sub.UserID,
+		PlanID:    sub.PlanID,
+		StartDate: start,
+		EndDate:   end,
+		IsActive:  true,
+		AutoRenew: true,
+	}
+	if err := s.subRepo.Create(renewal); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// chargeRenewal cobra y factura un nuevo período del plan con la tarjeta
+// predeterminada.
+func (s *SubscriptionService) chargeRenewal(sub models.Subscription, start, end time.Time) error {
+	plan, err := s.subRepo.GetPlanByID(sub.PlanID)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return apperrors.ErrNotFound("plan")
+	}
+
+	method, err := s.userRepo.GetDefaultPaymentMethod(sub.UserID)
+	if err != nil {
+		return err
+	}
+
+	charge, err := s.charge(method.Token, payments.ToCents(plan.Price))
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.recordCharge(sub.UserID, plan, models.TransactionCharge, charge, start, end); err != nil {
+		s.refundUnrecorded(charge)
+		return err
+	}
+	return nil
+}
+
+// startSubscription cierra el período activo (si hay) y abre uno nuevo en el
+// plan indicado desde `now` hasta `end`.
+func (s *SubscriptionService) startSubscription(userID, planID int, now, end time.Time) (*models.Subscription, error) {
+	sub := &models.Subscription{
+		UserID:    userID,
+		PlanID:    planID,
+		StartDate: now,
+		EndDate:   end,
+		IsActive:  true,
+		AutoRenew: true,
+	}
+	if err := s.subRepo.StartPeriod(sub); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return sub, nil
+}
+
+// nextPeriodEnd calcula el fin de un período de facturación (un mes).
+func nextPeriodEnd(start time.Time) time.Time {
+	return start.AddDate(0, 1, 0)
+}
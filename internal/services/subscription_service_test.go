@@ -0,0 +1,402 @@
+// internal/services/subscription_service_test.go
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/payments"
+	"SDGEStreaming/internal/repositories"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Planes sembrados por la migración inicial.
+const (
+	testStandardPlanID = 2 // 9,99
+	testPremiumPlanID  = 3 // 15,99
+)
+
+// recordingGateway envuelve la pasarela para contar las llamadas que el
+// servicio hace a Authorize y Void.
+type recordingGateway struct {
+	payments.Gateway
+	authorizations int
+	voided         []string
+}
+
+func (g *recordingGateway) Authorize(token string, amountCents int64) (*payments.Authorization, error) {
+	g.authorizations++
+	return g.Gateway.Authorize(token, amountCents)
+}
+
+func (g *recordingGateway) Void(authorizationID string) error {
+	if err := g.Gateway.Void(authorizationID); err != nil {
+		return err
+	}
+	g.voided = append(g.voided, authorizationID)
+	return nil
+}
+
+// subscriptionFixture es un servicio sobre una base de datos nueva, con un
+// usuario en el plan Free y la pasarela simulada con las tarjetas del README.
+type subscriptionFixture struct {
+	service *SubscriptionService
+	gateway *recordingGateway
+	subRepo repositories.SubscriptionRepo
+	userID  int
+}
+
+func newSubscriptionFixture(t *testing.T) *subscriptionFixture {
+	t.Helper()
+	newTestDB(t)
+
+	userRepo := repositories.NewUserRepo()
+	user := &models.User{
+		Name:         "Prueba",
+		Email:        "prueba@sdge.com",
+		Age:          30,
+		PlanID:       FreePlanID,
+		AgeRating:    models.AgeRatingAdult,
+		Country:      "AR",
+		Role:         models.RoleViewer,
+		PasswordHash: "x",
+	}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("crear usuario: %v", err)
+	}
+
+	gateway := newRecordingGateway()
+	subRepo := repositories.NewSubscriptionRepo()
+	return &subscriptionFixture{
+		service: NewSubscriptionService(subRepo, userRepo, repositories.NewBillingRepo(), gateway),
+		gateway: gateway,
+		subRepo: subRepo,
+		userID:  user.ID,
+	}
+}
+
+func (f *subscriptionFixture) pay(planID int, cardNumber string) error {
+	return f.service.ProcessPayment(f.userID, planID, "Titular Prueba", cardNumber, 12, time.Now().Year()+2, 123)
+}
+
+// subscribe abre un período del plan desde `start` sin pasar por la pasarela.
+func (f *subscriptionFixture) subscribe(t *testing.T, planID int, start time.Time) *models.Subscription {
+	t.Helper()
+	sub, err := f.service.startSubscription(f.userID, planID, start, nextPeriodEnd(start))
+	if err != nil {
+		t.Fatalf("startSubscription: %v", err)
+	}
+	return sub
+}
+
+func (f *subscriptionFixture) active(t *testing.T) *models.Subscription {
+	t.Helper()
+	sub, err := f.service.GetActiveSubscription(f.userID)
+	if err != nil {
+		t.Fatalf("GetActiveSubscription: %v", err)
+	}
+	if sub == nil {
+		t.Fatal("no hay suscripción activa")
+	}
+	return sub
+}
+
+func (f *subscriptionFixture) userPlan(t *testing.T) int {
+	t.Helper()
+	user, err := f.service.userRepo.FindByID(f.userID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	return user.PlanID
+}
+
+func errorCode(err error) string {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
+
+func TestProcessPaymentGatewayFailures(t *testing.T) {
+	tests := []struct {
+		name           string
+		card           string
+		wantCode       string // vacío si el pago debe aprobarse
+		authorizations int
+		voids          int
+	}{
+		{name: "tarjeta aprobada", card: "4111111111111111", authorizations: 1},
+		{name: "tarjeta rechazada", card: "4000000000000002", wantCode: payments.CodeDeclined, authorizations: 1},
+		{name: "tiempo agotado no se reintenta", card: "4000000000000697", wantCode: payments.CodeTimeout, authorizations: 1},
+		{name: "fallo transitorio resuelto con reintentos", card: "4000000000000119", authorizations: 3},
+		{name: "fallo transitorio que agota los reintentos", card: "4000000000000259", wantCode: payments.CodeUnavailable, authorizations: maxPaymentAttempts},
+		{name: "fallo en la captura anula la autorización", card: "4000000000000341", wantCode: payments.CodeDeclined, authorizations: 1, voids: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSubscriptionFixture(t)
+
+			err := f.pay(testPremiumPlanID, tt.card)
+			if code := errorCode(err); code != tt.wantCode || (tt.wantCode == "" && err != nil) {
+				t.Fatalf("ProcessPayment: error %v, se esperaba código %q", err, tt.wantCode)
+			}
+			if f.gateway.authorizations != tt.authorizations {
+				t.Errorf("Authorize llamado %d veces, se esperaban %d", f.gateway.authorizations, tt.authorizations)
+			}
+			if len(f.gateway.voided) != tt.voids {
+				t.Errorf("%d autorizaciones anuladas, se esperaban %d", len(f.gateway.voided), tt.voids)
+			}
+
+			sub, err := f.service.GetActiveSubscription(f.userID)
+			if err != nil {
+				t.Fatalf("GetActiveSubscription: %v", err)
+			}
+			method, _ := f.service.userRepo.GetDefaultPaymentMethod(f.userID)
+			invoices, err := f.service.billingRepo.FindInvoicesByUserID(f.userID)
+			if err != nil {
+				t.Fatalf("FindInvoicesByUserID: %v", err)
+			}
+			user, err := f.service.userRepo.FindByID(f.userID)
+			if err != nil {
+				t.Fatalf("FindByID: %v", err)
+			}
+
+			if tt.wantCode != "" {
+				// Un pago fallido no deja rastro: ni período, ni tarjeta, ni factura.
+				if sub != nil {
+					t.Errorf("quedó una suscripción activa al plan %d", sub.PlanID)
+				}
+				if method != nil {
+					t.Errorf("quedó guardado el método de pago terminado en %s", method.Last4)
+				}
+				if len(invoices) != 0 {
+					t.Errorf("se emitieron %d facturas", len(invoices))
+				}
+				if user.PlanID != FreePlanID {
+					t.Errorf("el usuario quedó en el plan %d", user.PlanID)
+				}
+				return
+			}
+
+			if sub == nil || sub.PlanID != testPremiumPlanID {
+				t.Fatalf("suscripción activa = %+v, se esperaba el plan %d", sub, testPremiumPlanID)
+			}
+			if method == nil || method.Last4 != tt.card[len(tt.card)-4:] {
+				t.Errorf("método de pago = %+v", method)
+			}
+			if len(invoices) != 1 || invoices[0].AmountCents != payments.ToCents(15.99) {
+				t.Errorf("facturas = %+v, se esperaba una de 1599 centavos", invoices)
+			}
+			if user.PlanID != testPremiumPlanID {
+				t.Errorf("el usuario quedó en el plan %d", user.PlanID)
+			}
+		})
+	}
+}
+
+func TestProrate(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := nextPeriodEnd(start) // 31 días
+	sub := &models.Subscription{StartDate: start, EndDate: end}
+
+	tests := []struct {
+		name string
+		sub  *models.Subscription
+		now  time.Time
+		want int64
+	}{
+		{name: "inicio del período", sub: sub, now: start, want: 1599},
+		{name: "antes del inicio se limita al total", sub: sub, now: start.AddDate(0, 0, -3), want: 1599},
+		{name: "mitad del período redondea", sub: sub, now: start.Add(end.Sub(start) / 2), want: 800},
+		{name: "un día usado", sub: sub, now: start.AddDate(0, 0, 1), want: 1547},
+		{name: "último segundo", sub: sub, now: end.Add(-time.Second), want: 0},
+		{name: "fin del período", sub: sub, now: end, want: 0},
+		{name: "período vencido", sub: sub, now: end.AddDate(0, 0, 5), want: 0},
+		{name: "período vacío", sub: &models.Subscription{StartDate: start, EndDate: start}, now: start, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prorate(tt.sub, tt.now)(1599); got != tt.want {
+				t.Errorf("prorate(1599) = %d, se esperaba %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewPlanChange(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := nextPeriodEnd(start)
+
+	tests := []struct {
+		name      string
+		current   int // 0: sin período en curso
+		target    int
+		now       time.Time
+		wantCode  string
+		kind      string
+		amount    int64
+		credit    int64
+		effective time.Time
+	}{
+		{name: "alta desde Free", target: testPremiumPlanID, now: start, kind: models.PlanChangeNew, amount: 1599, effective: start},
+		{name: "mejora el mismo día del alta", current: testStandardPlanID, target: testPremiumPlanID, now: start, kind: models.PlanChangeUpgrade, amount: 600, credit: 999, effective: start},
+		{name: "mejora a mitad de período", current: testStandardPlanID, target: testPremiumPlanID, now: start.Add(end.Sub(start) / 2), kind: models.PlanChangeUpgrade, amount: 300, credit: 500, effective: start.Add(end.Sub(start) / 2)},
+		{name: "mejora al final del período", current: testStandardPlanID, target: testPremiumPlanID, now: end, kind: models.PlanChangeUpgrade, effective: end},
+		{name: "baja de plan rige al renovar", current: testPremiumPlanID, target: testStandardPlanID, now: start, kind: models.PlanChangeDowngrade, effective: end},
+		{name: "baja a Free rige al renovar", current: testPremiumPlanID, target: FreePlanID, now: start, kind: models.PlanChangeDowngrade, effective: end},
+		{name: "mismo plan sin cambio programado", current: testStandardPlanID, target: testStandardPlanID, now: start, wantCode: "CONFLICT"},
+		{name: "plan inexistente", current: testStandardPlanID, target: 99, now: start, wantCode: "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSubscriptionFixture(t)
+			if tt.current != 0 {
+				f.subscribe(t, tt.current, start)
+			}
+
+			change, err := f.service.PreviewPlanChange(f.userID, tt.target, tt.now)
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("error %v, se esperaba código %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PreviewPlanChange: %v", err)
+			}
+			if change.Kind != tt.kind || change.AmountCents != tt.amount || change.CreditCents != tt.credit {
+				t.Errorf("cambio = %s, cobro %d, crédito %d; se esperaba %s, cobro %d, crédito %d",
+					change.Kind, change.AmountCents, change.CreditCents, tt.kind, tt.amount, tt.credit)
+			}
+			if !change.EffectiveAt.Equal(tt.effective) {
+				t.Errorf("rige desde %v, se esperaba %v", change.EffectiveAt, tt.effective)
+			}
+		})
+	}
+}
+
+func TestChangePlanSchedulesAndUndoesDowngrade(t *testing.T) {
+	f := newSubscriptionFixture(t)
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := nextPeriodEnd(start)
+	now := start.AddDate(0, 0, 10)
+
+	// Mejorar requiere pago y no se puede hacer con ChangePlan.
+	f.subscribe(t, testStandardPlanID, start)
+	if _, err := f.service.ChangePlan(f.userID, testPremiumPlanID, now); errorCode(err) != "INVALID_INPUT" {
+		t.Fatalf("mejora sin pago: error %v, se esperaba INVALID_INPUT", err)
+	}
+	f.subscribe(t, testPremiumPlanID, start)
+
+	// Bajar de plan solo lo programa: el plan actual sigue hasta el final.
+	change, err := f.service.ChangePlan(f.userID, testStandardPlanID, now)
+	if err != nil {
+		t.Fatalf("ChangePlan a Estándar: %v", err)
+	}
+	if change.Kind != models.PlanChangeDowngrade || !change.EffectiveAt.Equal(end) {
+		t.Errorf("cambio = %+v, se esperaba una baja al %v", change, end)
+	}
+	sub := f.active(t)
+	if sub.PlanID != testPremiumPlanID || sub.PendingPlanID == nil || *sub.PendingPlanID != testStandardPlanID {
+		t.Fatalf("suscripción = plan %d, pendiente %v; se esperaba Premium con Estándar pendiente", sub.PlanID, sub.PendingPlanID)
+	}
+	if plan := f.userPlan(t); plan != testPremiumPlanID {
+		t.Errorf("el usuario pasó al plan %d antes de terminar el período", plan)
+	}
+
+	// Volver al plan actual deshace el cambio programado sin cobrar.
+	change, err = f.service.ChangePlan(f.userID, testPremiumPlanID, now)
+	if err != nil {
+		t.Fatalf("deshacer: %v", err)
+	}
+	if change.AmountCents != 0 {
+		t.Errorf("deshacer cobró %d centavos", change.AmountCents)
+	}
+	if sub := f.active(t); sub.PendingPlanID != nil {
+		t.Errorf("sigue pendiente el plan %d", *sub.PendingPlanID)
+	}
+
+	// Sin nada programado, el plan actual ya no es un cambio.
+	if _, err := f.service.ChangePlan(f.userID, testPremiumPlanID, now); errorCode(err) != "CONFLICT" {
+		t.Errorf("repetir el plan: error %v, se esperaba CONFLICT", err)
+	}
+}
+
+func TestChangePlanToFreeCancelsRenewal(t *testing.T) {
+	f := newSubscriptionFixture(t)
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	f.subscribe(t, testPremiumPlanID, start)
+	now := start.AddDate(0, 0, 3)
+
+	if _, err := f.service.ChangePlan(f.userID, testStandardPlanID, now); err != nil {
+		t.Fatalf("ChangePlan a Estándar: %v", err)
+	}
+	if _, err := f.service.ChangePlan(f.userID, FreePlanID, now); err != nil {
+		t.Fatalf("ChangePlan a Free: %v", err)
+	}
+	sub := f.active(t)
+	if sub.AutoRenew || sub.PendingPlanID != nil {
+		t.Errorf("renovación %v, pendiente %v; se esperaba cancelada y sin cambio programado", sub.AutoRenew, sub.PendingPlanID)
+	}
+
+	// Con la renovación cancelada no se programan otras bajas.
+	if _, err := f.service.ChangePlan(f.userID, testStandardPlanID, now); errorCode(err) != "CONFLICT" {
+		t.Errorf("baja tras cancelar: error %v, se esperaba CONFLICT", err)
+	}
+}
+
+func TestUpgradeThenDowngrade(t *testing.T) {
+	f := newSubscriptionFixture(t)
+	// ProcessPayment usa la hora actual, así que el período se abre hace
+	// unos días para que la mejora sea a mitad de período.
+	start := time.Now().UTC().Truncate(time.Second).AddDate(0, 0, -10)
+	end := nextPeriodEnd(start)
+	f.subscribe(t, testStandardPlanID, start)
+
+	if err := f.pay(testPremiumPlanID, "4111111111111111"); err != nil {
+		t.Fatalf("mejora: %v", err)
+	}
+	sub := f.active(t)
+	if sub.PlanID != testPremiumPlanID || !sub.EndDate.Equal(end) {
+		t.Fatalf("tras la mejora: plan %d hasta %v; se esperaba Premium hasta %v", sub.PlanID, sub.EndDate, end)
+	}
+	invoices, err := f.service.billingRepo.FindInvoicesByUserID(f.userID)
+	if err != nil {
+		t.Fatalf("FindInvoicesByUserID: %v", err)
+	}
+	if len(invoices) != 1 || invoices[0].AmountCents <= 0 || invoices[0].AmountCents >= 600 {
+		t.Fatalf("facturas = %+v, se esperaba un prorrateo menor a la diferencia de un mes", invoices)
+	}
+
+	// La baja inmediata posterior no devuelve nada y rige al renovar.
+	change, err := f.service.ChangePlan(f.userID, testStandardPlanID, time.Now())
+	if err != nil {
+		t.Fatalf("baja: %v", err)
+	}
+	if change.Kind != models.PlanChangeDowngrade || change.AmountCents != 0 || !change.EffectiveAt.Equal(end) {
+		t.Errorf("cambio = %+v, se esperaba una baja sin cobro al %v", change, end)
+	}
+
+	// Al vencer, la renovación se cobra con el plan programado.
+	processed, err := f.service.ProcessExpirations(end)
+	if err != nil || processed != 1 {
+		t.Fatalf("ProcessExpirations = %d, %v", processed, err)
+	}
+	sub = f.active(t)
+	if sub.PlanID != testStandardPlanID || sub.PendingPlanID != nil || !sub.StartDate.Equal(end) {
+		t.Errorf("renovación = plan %d desde %v, pendiente %v; se esperaba Estándar desde %v", sub.PlanID, sub.StartDate, sub.PendingPlanID, end)
+	}
+	if plan := f.userPlan(t); plan != testStandardPlanID {
+		t.Errorf("el usuario quedó en el plan %d", plan)
+	}
+	invoices, err = f.service.billingRepo.FindInvoicesByUserID(f.userID)
+	if err != nil {
+		t.Fatalf("FindInvoicesByUserID: %v", err)
+	}
+	if len(invoices) != 2 {
+		t.Fatalf("se emitieron %d facturas, se esperaban 2", len(invoices))
+	}
+}
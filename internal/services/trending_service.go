@@ -0,0 +1,221 @@
+// internal/services/trending_service.go
+// Tendencias del catálogo según las interacciones recientes.
+package services
+
+import (
+	apperrors "SDGEStreaming/internal/errors"
+	"SDGEStreaming/internal/models"
+	"SDGEStreaming/internal/repositories"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cantidad de contenidos en tendencia.
+const (
+	DefaultTrendingLimit = 10
+	MaxTrendingLimit     = 50
+)
+
+// TrendingRefreshInterval es cada cuánto se recalculan las tendencias.
+const TrendingRefreshInterval = 10 * time.Minute
+
+// Cuánto suma cada interacción al puntaje de tendencia, antes de aplicarle
+// el decaimiento. Una calificación suma más cuanto mejor es y resta si es
+// mala (ver ratingWeight).
+const (
+	playPoints       = 1.0
+	completionPoints = 2.0
+	favoritePoints   = 3.0
+	ratingPoints     = 2.0
+)
+
+// TrendingService calcula qué contenido está en tendencia en cada ventana
+// (24h, 7d y 30d). El cálculo es el mismo para todos los perfiles, así que se
+// guarda en memoria y se rehace cada TrendingRefreshInterval; lo que cada
+// perfil no puede ver se filtra al consultar.
+type TrendingService struct {
+	trendingRepo   repositories.TrendingRepo
+	contentRepo    repositories.ContentRepo
+	contentService *ContentService
+
+	mu          sync.RWMutex
+	ranking     map[string][]models.TrendingItem
+	refreshedAt time.Time
+}
+
+// NewTrendingService crea una nueva instancia del servicio.
+func NewTrendingService(trendingRepo repositories.TrendingRepo, contentRepo repositories.ContentRepo, contentService *ContentService) *TrendingService {
+	return &TrendingService{
+		trendingRepo:   trendingRepo,
+		contentRepo:    contentRepo,
+		contentService: contentService,
+	}
+}
+
+// GetTrending devuelve hasta limit contenidos en tendencia en la ventana
+// indicada que el perfil puede ver, del más al menos popular. El resultado
+// sale del cálculo guardado, así que puede tener hasta
+// TrendingRefreshInterval de antigüedad; si no hay cálculo o está vencido, lo
+// rehace. limit <= 0 usa DefaultTrendingLimit.
+func (s *TrendingService) GetTrending(profile *models.Profile, window string, limit int, now time.Time) ([]models.TrendingItem, error) {
+	if _, ok := models.TrendingWindowOf(window); !ok {
+		return nil, apperrors.ErrInvalidInput("window")
+	}
+	if limit <= 0 {
+		limit = DefaultTrendingLimit
+	}
+	if limit > MaxTrendingLimit {
+		limit = MaxTrendingLimit
+	}
+
+	ranking, err := s.cachedRanking(window, now)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]models.ContentRef, len(ranking))
+	for i := range ranking {
+		refs[i] = ranking[i].ContentRef
+	}
+	allowed, err := s.contentService.allowedRefs(profile, refs, now)
+	if err != nil {
+		return nil, err
+	}
+
+	trending := []models.TrendingItem{}
+	for _, item := range ranking {
+		if len(trending) == limit {
+			break
+		}
+		if allowed[item.ContentRef] {
+			item.Rank = len(trending) + 1
+			trending = append(trending, item)
+		}
+	}
+	return trending, nil
+}
+
+// Refresh recalcula las tendencias de todas las ventanas a partir de las
+// interacciones hasta now.
+func (s *TrendingService) Refresh(now time.Time) error {
+	longest := models.TrendingWindows[len(models.TrendingWindows)-1]
+	events, err := s.trendingRepo.FindEngagement(now.Add(-longest.Span))
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	ranking := make(map[string][]models.TrendingItem, len(models.TrendingWindows))
+	var refs []models.ContentRef
+	seen := make(map[models.ContentRef]bool)
+	for _, window := range models.TrendingWindows {
+		ranking[window.Name] = rankEngagement(events, window, now)
+		for _, item := range ranking[window.Name] {
+			if !seen[item.ContentRef] {
+				seen[item.ContentRef] = true
+				refs = append(refs, item.ContentRef)
+			}
+		}
+	}
+
+	items, err := s.contentRepo.FindCatalogItems(refs)
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	for name, list := range ranking {
+		withItems := list[:0]
+		for _, t := range list {
+			if item, ok := items[t.ContentRef]; ok {
+				t.CatalogItem = item
+				withItems = append(withItems, t)
+			}
+		}
+		ranking[name] = withItems
+	}
+
+	s.mu.Lock()
+	s.ranking = ranking
+	s.refreshedAt = now
+	s.mu.Unlock()
+	return nil
+}
+
+// RefreshNow rehace las tendencias sin esperar a que venza el cálculo
+// guardado, por ejemplo después de cargar contenido.
+func (s *TrendingService) RefreshNow(actor *models.User, now time.Time) error {
+	if err := authorize(actor, models.PermManageContent); err != nil {
+		return err
+	}
+	return s.Refresh(now)
+}
+
+// cachedRanking devuelve el ranking guardado de la ventana, recalculándolo
+// si está vencido.
+func (s *TrendingService) cachedRanking(window string, now time.Time) ([]models.TrendingItem, error) {
+	s.mu.RLock()
+	ranking, refreshedAt := s.ranking, s.refreshedAt
+	s.mu.RUnlock()
+
+	if ranking == nil || now.Sub(refreshedAt) >= TrendingRefreshInterval || now.Before(refreshedAt) {
+		if err := s.Refresh(now); err != nil {
+			return nil, err
+		}
+		s.mu.RLock()
+		ranking = s.ranking
+		s.mu.RUnlock()
+	}
+	return ranking[window], nil
+}
+
+// rankEngagement puntúa cada contenido con las interacciones dentro de la
+// ventana. Cada una vale sus puntos multiplicados por 0.5^(antigüedad /
+// vida media). Solo quedan los de puntaje positivo, del mayor al menor.
+func rankEngagement(events []models.Engagement, window models.TrendingWindow, now time.Time) []models.TrendingItem {
+	byRef := make(map[models.ContentRef]*models.TrendingItem)
+	for _, e := range events {
+		age := now.Sub(e.At)
+		if age < 0 {
+			age = 0
+		}
+		if age > window.Span {
+			continue
+		}
+
+		item, ok := byRef[e.ContentRef]
+		if !ok {
+			item = &models.TrendingItem{CatalogItem: models.CatalogItem{ContentRef: e.ContentRef}}
+			byRef[e.ContentRef] = item
+		}
+
+		var points float64
+		switch e.Signal {
+		case models.SignalWatched:
+			points = playPoints
+			item.Plays++
+		case models.SignalCompleted:
+			points = completionPoints
+			item.Completions++
+		case models.SignalFavorite:
+			points = favoritePoints
+			item.Favorites++
+		case models.SignalRated:
+			points = ratingPoints * ratingWeight(e.Rating)
+			item.Ratings++
+		}
+		item.Score += points * math.Pow(0.5, age.Hours()/window.HalfLife.Hours())
+	}
+
+	ranking := make([]models.TrendingItem, 0, len(byRef))
+	for _, item := range byRef {
+		if item.Score > 0 {
+			ranking = append(ranking, *item)
+		}
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return refLess(ranking[i].ContentRef, ranking[j].ContentRef)
+	})
+	return ranking
+}
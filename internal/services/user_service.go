@@ -2,12 +2,15 @@
 package services
 
 import (
+	apperrors "SDGEStreaming/internal/errors"
 	"SDGEStreaming/internal/models"
 	"SDGEStreaming/internal/repositories"
 	"SDGEStreaming/internal/security"
 	"SDGEStreaming/internal/utils"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -20,22 +23,25 @@ func NewUserService(userRepo repositories.UserRepo, subscriptionRepo repositorie
 	return &UserService{userRepo: userRepo, subscriptionRepo: subscriptionRepo}
 }
 
-func (s *UserService) Register(name string, age int, email, password string, isAdmin bool) (*models.User, error) {
+// Register crea una cuenta de cliente (rol viewer); los demás roles los
+// asigna un super_admin con SetRole. La cuenta empieza en
+// models.DefaultCountry: la región decide qué contenido se puede ver, así que
+// solo la cambia el personal con SetCountry.
+func (s *UserService) Register(name string, age int, email, password string) (*models.User, error) {
 	if !utils.IsValidName(name) {
-		return nil, fmt.Errorf("nombre inválido")
+		return nil, apperrors.New("INVALID_INPUT", "nombre inválido")
 	}
 	if age < 13 || age > 120 {
-		return nil, fmt.Errorf("edad debe estar entre 13 y 120 años")
+		return nil, apperrors.New("INVALID_INPUT", "edad debe estar entre 13 y 120 años")
 	}
 	if !utils.IsValidEmail(email) {
-		return nil, fmt.Errorf("email inválido")
+		return nil, apperrors.New("INVALID_INPUT", "email inválido")
 	}
 	if !utils.IsValidPassword(password) {
-		return nil, fmt.Errorf("contraseña debe tener al menos 6 caracteres")
+		return nil, apperrors.New("INVALID_INPUT", "contraseña debe tener al menos 6 caracteres")
 	}
-
 	if existing, _ := s.userRepo.FindByEmail(email); existing != nil {
-		return nil, fmt.Errorf("el email ya está registrado")
+		return nil, apperrors.ErrConflict("el email ya está registrado")
 	}
 
 	hashedPass, err := security.HashPassword(password)
@@ -54,7 +60,8 @@ func (s *UserService) Register(name string, age int, email, password string, isA
 		PasswordHash: hashedPass,
 		PlanID:       1, // plan Free por defecto
 		AgeRating:    ageRating,
-		IsAdmin:      isAdmin,
+		Country:      models.DefaultCountry,
+		Role:         models.RoleViewer,
 		CreatedAt:    now,
 		LastLogin:    now,
 	}
@@ -70,11 +77,11 @@ func (s *UserService) Register(name string, age int, email, password string, isA
 func classifyAge(age int) string {
 	switch {
 	case age < 13:
-		return "Niño"
+		return models.AgeRatingChild
 	case age < 18:
-		return "Adolescente"
+		return models.AgeRatingTeen
 	default:
-		return "Adulto"
+		return models.AgeRatingAdult
 	}
 }
 
@@ -82,11 +89,11 @@ func classifyAge(age int) string {
 func (s *UserService) Login(email, password string) (*models.User, error) {
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil || user == nil {
-		return nil, errors.New("email o contraseña incorrectos")
+		return nil, apperrors.New("UNAUTHORIZED", "email o contraseña incorrectos")
 	}
 
 	if !security.CheckPasswordHash(password, user.PasswordHash) {
-		return nil, errors.New("email o contraseña incorrectos")
+		return nil, apperrors.New("UNAUTHORIZED", "email o contraseña incorrectos")
 	}
 
 	return user, nil
@@ -94,11 +101,33 @@ func (s *UserService) Login(email, password string) (*models.User, error) {
 
 // GetByID retrieves a user by ID.
 func (s *UserService) GetByID(id int) (*models.User, error) {
-	return s.userRepo.FindByID(id)
+	user, err := s.userRepo.FindByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.ErrNotFound("usuario")
+	}
+	if err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return user, nil
+}
+
+// GetUser devuelve un usuario: el propio siempre, cualquier otro solo con
+// permiso para ver usuarios.
+func (s *UserService) GetUser(actor *models.User, id int) (*models.User, error) {
+	if actor == nil || actor.ID != id {
+		if err := authorize(actor, models.PermViewUsers); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetByID(id)
 }
 
-// GetAllUsers para el admin
-func (s *UserService) GetAllUsers() ([]models.User, error) {
+// GetAllUsers lista todos los usuarios (requiere permiso para ver usuarios).
+func (s *UserService) GetAllUsers(actor *models.User) ([]models.User, error) {
+	if err := authorize(actor, models.PermViewUsers); err != nil {
+		return nil, err
+	}
+
 	users, err := s.userRepo.FindAll()
 	if err != nil {
 		return nil, fmt.Errorf("no se pudo obtener los usuarios: %w", err)
@@ -106,13 +135,67 @@ func (s *UserService) GetAllUsers() ([]models.User, error) {
 	return users, nil
 }
 
-// UpdateUserPlan actualiza el plan (usado por main)
-func (s *UserService) UpdateUserPlan(userID, planID int) error {
-	// actualizar tabla users
-	if err := s.userRepo.UpdatePlan(userID, planID); err != nil {
-		return fmt.Errorf("no se pudo actualizar plan en users: %w", err)
+// SetRole cambia el rol de un usuario (solo super_admin). Nadie puede
+// cambiar su propio rol, para no quedarse sin un super_admin por error.
+func (s *UserService) SetRole(actor *models.User, userID int, role string) (*models.User, error) {
+	if err := authorize(actor, models.PermManageRoles); err != nil {
+		return nil, err
+	}
+	if !models.IsValidRole(role) {
+		return nil, apperrors.ErrInvalidInput("role")
+	}
+	if actor.ID == userID {
+		return nil, apperrors.ErrConflict("no puede cambiar su propio rol")
+	}
+
+	user, err := s.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateRole(userID, role); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	user.Role = role
+	return user, nil
+}
+
+// SetCountry cambia la región de una cuenta, que decide qué contenido
+// licenciado puede ver. Solo lo hace el personal con permiso para gestionar
+// usuarios, y nadie sobre su propia cuenta: si no, bastaría mudarse al país
+// que licencia un título para verlo.
+func (s *UserService) SetCountry(actor *models.User, userID int, country string) (*models.User, error) {
+	if err := authorize(actor, models.PermManageUsers); err != nil {
+		return nil, err
+	}
+	if actor.ID == userID {
+		return nil, apperrors.ErrConflict("no puede cambiar el país de su propia cuenta")
+	}
+	country, err := normalizeCountry(country)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateCountry(userID, country); err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	user.Country = country
+	return user, nil
+}
+
+// normalizeCountry pasa el país a mayúsculas y lo valida; vacío es
+// models.DefaultCountry.
+func normalizeCountry(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return models.DefaultCountry, nil
+	}
+	if !utils.IsValidCountry(country) {
+		return "", apperrors.New("INVALID_INPUT", fmt.Sprintf("país '%s' no válido (código ISO de 2 letras)", country))
 	}
-	return nil
+	return country, nil
 }
 
 // GetDefaultPaymentMethod
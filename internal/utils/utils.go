@@ -47,3 +47,13 @@ func Normalize(s string) string {
 func IsEmpty(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
+
+// FormatMoney formats an amount in cents as dollars, e.g. 999 -> "$9.99".
+func FormatMoney(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, cents/100, cents%100)
+}
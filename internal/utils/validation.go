@@ -55,3 +55,16 @@ func ValidatePassword(password string) error {
 	}
 	return nil
 }
+
+// IsValidCountry checks if a country is an ISO 3166-1 alpha-2 code (e.g. EC).
+func IsValidCountry(country string) bool {
+	if len(country) != 2 {
+		return false
+	}
+	for _, r := range country {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}